@@ -12,8 +12,11 @@ import (
 )
 
 func main() {
-	serverCmd := flag.NewFlagSet("server", flag.ExitOnError) // create a new flag set for server command
-	seedCmd := flag.NewFlagSet("seed", flag.ExitOnError)     // create a new flag set for seed command
+	serverCmd := flag.NewFlagSet("server", flag.ExitOnError)        // create a new flag set for server command
+	seedCmd := flag.NewFlagSet("seed", flag.ExitOnError)            // create a new flag set for seed command
+	ratesCmd := flag.NewFlagSet("exchange-rates", flag.ExitOnError) // create a new flag set for exchange-rates command
+	relayCmd := flag.NewFlagSet("outbox-relay", flag.ExitOnError)   // create a new flag set for outbox-relay command
+	consumerCmd := flag.NewFlagSet("consumer", flag.ExitOnError)    // create a new flag set for consumer command
 
 	if len(os.Args) < 2 { // check if no command provided
 		log.Info().Msg("No command provided, defaulting to 'server'")
@@ -26,6 +29,12 @@ func main() {
 		cmd.RunServer(serverCmd, os.Args[2:])
 	case "seed":
 		cmd.RunSeed(seedCmd, os.Args[2:])
+	case "exchange-rates":
+		cmd.RunExchangeRates(ratesCmd, os.Args[2:])
+	case "outbox-relay":
+		cmd.RunOutboxRelay(relayCmd, os.Args[2:])
+	case "consumer":
+		cmd.RunConsumer(consumerCmd, os.Args[2:])
 	default:
 		log.Info().Msg("Invalid command provided, defaulting to 'server' with provided flags")
 		if os.Args[1][0] == '-' { // check if the first argument is a flag
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"product-service/internal/adapter"
+	"product-service/internal/infrastructure"
+	"product-service/internal/module/consumer/handler/subscriber"
+	"product-service/pkg/validator"
+	"runtime"
+	"syscall"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// RunConsumer function is used to handle the events of other services until
+// the process is stopped. Messages being handled finish before the broker
+// and the database are closed.
+func RunConsumer(cmd *flag.FlagSet, args []string) {
+	var (
+		envs        = infrastructure.Envs
+		maxAttempts = cmd.Int("max-attempts", envs.Consumer.MaxAttempts, "Handling attempts before an event is dead-lettered") // ex: go run main.go consumer -max-attempts=5
+	)
+
+	logLevel, err := zerolog.ParseLevel(envs.App.LogLevel)
+	if err != nil {
+		logLevel = zerolog.InfoLevel
+	}
+
+	infrastructure.InitializeLogger(envs.App.Environtment, "consumer.log", logLevel)
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	if *maxAttempts < 1 {
+		log.Fatal().Int("max_attempts", *maxAttempts).Msg("Max attempts must be at least 1")
+	}
+
+	adapter.Adapters.Sync(
+		adapter.WithShopeefunProductPostgres(),
+		adapter.WithCache(),
+		adapter.WithValidator(validator.NewValidator()),
+		adapter.WithSharedBroker(),
+	)
+
+	shutdownSignals := []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGINT}
+	if runtime.GOOS == "windows" {
+		shutdownSignals = []os.Signal{os.Interrupt}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), shutdownSignals...)
+	defer stop()
+
+	if err := subscriber.NewConsumerSubscriber(*maxAttempts).Subscribe(ctx); err != nil {
+		log.Error().Err(err).Msg("Error while subscribing")
+		stop()
+	}
+
+	<-ctx.Done()
+	log.Info().Msg("Consumer is shutting down ...")
+
+	// the broker closes before the database, after its handlers returned
+	if err := adapter.Adapters.Unsync(); err != nil {
+		log.Error().Msgf("Error while closing adapters: %v", err)
+	}
+
+	log.Info().Msg("Consumer gracefully stopped")
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"product-service/internal/adapter"
+	"product-service/internal/module/currency/entity"
+	"product-service/internal/module/currency/repository"
+	"product-service/internal/module/currency/service"
+	"product-service/pkg/decimal"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunExchangeRates function is used to load exchange rates from a CSV file
+// with from_currency,to_currency,rate rows, a header row is optional.
+func RunExchangeRates(cmd *flag.FlagSet, args []string) {
+	var (
+		file = cmd.String("file", "", "CSV file with exchange rates") // ex: go run main.go exchange-rates -file=rates.csv
+	)
+
+	if err := cmd.Parse(args); err != nil { // parse the flags
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	if *file == "" {
+		log.Fatal().Msg("No exchange rates file provided")
+	}
+
+	rates, err := readExchangeRates(*file)
+	if err != nil {
+		log.Fatal().Err(err).Str("file", *file).Msg("Error while reading exchange rates")
+	}
+
+	adapter.Adapters.Sync(
+		adapter.WithShopeefunProductPostgres(),
+		adapter.WithCache(),
+	)
+	defer func() {
+		if err := adapter.Adapters.Unsync(); err != nil {
+			log.Fatal().Err(err).Msg("Error while unsyncing adapters")
+		}
+	}()
+
+	// through the service, so cached converted prices are invalidated too
+	repo := repository.NewCurrencyRepository(adapter.Adapters.ShopeefunProductPostgres)
+	svc := service.NewCurrencyService(repo, adapter.Adapters.Cache)
+	if _, err := svc.UpsertExchangeRates(context.Background(), &entity.UpsertExchangeRatesRequest{Rates: rates}); err != nil {
+		log.Fatal().Err(err).Msg("Error while saving exchange rates")
+	}
+
+	log.Info().Int("total", len(rates)).Msg("Exchange rates loaded successfully")
+}
+
+func readExchangeRates(path string) ([]entity.ExchangeRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		rates  = make([]entity.ExchangeRate, 0)
+		reader = csv.NewReader(f)
+	)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rate, err := decimal.ParseRate(record[2])
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("line %d: invalid rate %q", line, record[2])
+		}
+
+		if rate.Sign() <= 0 {
+			return nil, fmt.Errorf("line %d: rate must be greater than 0", line)
+		}
+
+		rates = append(rates, entity.ExchangeRate{
+			FromCurrency: strings.ToUpper(record[0]),
+			ToCurrency:   strings.ToUpper(record[1]),
+			Rate:         rate,
+		})
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"product-service/internal/adapter"
+	"product-service/internal/infrastructure"
+	"product-service/internal/module/outbox/handler/relay"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// RunOutboxRelay function is used to publish the outbox to the broker until
+// the process is stopped, or just once with -once.
+func RunOutboxRelay(cmd *flag.FlagSet, args []string) {
+	var (
+		envs     = infrastructure.Envs
+		interval = cmd.Int("interval", envs.Outbox.RelayInterval, "Seconds between relay runs") // ex: go run main.go outbox-relay -interval=5
+		once     = cmd.Bool("once", false, "Relay the due events once and exit")
+	)
+
+	logLevel, err := zerolog.ParseLevel(envs.App.LogLevel)
+	if err != nil {
+		logLevel = zerolog.InfoLevel
+	}
+
+	infrastructure.InitializeLogger(envs.App.Environtment, "outbox-relay.log", logLevel)
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	if *interval < 1 {
+		log.Fatal().Int("interval", *interval).Msg("Relay interval must be at least 1 second")
+	}
+
+	adapter.Adapters.Sync(
+		adapter.WithShopeefunProductPostgres(),
+		adapter.WithSharedBroker(),
+	)
+	defer func() {
+		if err := adapter.Adapters.Unsync(); err != nil {
+			log.Error().Msgf("Error while closing adapters: %v", err)
+		}
+	}()
+
+	shutdownSignals := []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGINT}
+	if runtime.GOOS == "windows" {
+		shutdownSignals = []os.Signal{os.Interrupt}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), shutdownSignals...)
+	defer stop()
+
+	outboxRelay := relay.NewOutboxRelay(time.Duration(*interval)*time.Second, envs.Outbox.BatchSize, envs.Outbox.MaxAttempts)
+
+	if *once {
+		if err := outboxRelay.Once(ctx); err != nil {
+			log.Error().Err(err).Msg("Error while relaying outbox events")
+		}
+		return
+	}
+
+	outboxRelay.Run(ctx)
+	log.Info().Msg("Outbox relay gracefully stopped")
+}
@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"flag"
+	"net"
 	"os"
 	"os/signal"
 	"product-service/internal/adapter"
 	"product-service/internal/infrastructure"
+	m "product-service/internal/middleware"
+	"product-service/internal/module/product/handler/scheduler"
+	"product-service/internal/module/webhook/handler/dispatcher"
 	"product-service/internal/route"
 	"product-service/pkg/validator"
 	"runtime"
@@ -15,18 +20,22 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
 )
 
 // RunServer function is used to run the server
-// It will initialize the logger, fiber app, and the routes
-// It will also handle graceful shutdown
+// It will initialize the logger, fiber app, gRPC server, and the routes
+// It will also handle graceful shutdown of both servers
 func RunServer(cmd *flag.FlagSet, args []string) {
 	var (
 		SERVER_PORT string
+		GRPC_PORT   string
 		envs        = infrastructure.Envs
-		flagAppPort = cmd.String("port", "8080", "Application port") // ex: go run main.go server -port=8080
+		flagAppPort = cmd.String("port", "8080", "Application port")                     // ex: go run main.go server -port=8080
+		flagGrpc    = cmd.String("grpc-port", "", "gRPC port, APP_GRPC_PORT when empty") // ex: go run main.go server -grpc-port=9090
 	)
 
 	logLevel, err := zerolog.ParseLevel(envs.App.LogLevel)
@@ -44,6 +53,15 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 
 	// Application Middlewares
 
+	// Recover middleware, a panicking handler answers 500 instead of taking
+	// the process down
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace: true,
+		StackTraceHandler: func(c *fiber.Ctx, e any) {
+			log.Error().Any("panic", e).Str("path", c.Path()).Msg("Recovered from panic in handler")
+		},
+	}))
+
 	// Rate limiter middleware
 	if envs.App.Environtment == "production" {
 		app.Use(limiter.New(limiter.Config{
@@ -58,6 +76,12 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 		SERVER_PORT = infrastructure.Envs.App.Port
 	}
 
+	if *flagGrpc != "" {
+		GRPC_PORT = *flagGrpc
+	} else {
+		GRPC_PORT = envs.App.GrpcPort
+	}
+
 	// CORS middleware
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
@@ -66,13 +90,26 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 	}))
 	// End Application Middlewares
 
+	// stock reservations are admin-only on REST, gRPC callers outside the
+	// host must present the shared token
+	if envs.App.GrpcToken == "" && !isLoopback(envs.App.GrpcHost) {
+		log.Fatal().Str("host", envs.App.GrpcHost).Msg("APP_GRPC_TOKEN is required when gRPC listens beyond loopback")
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(m.RecoverUnary, m.AuthUnary(envs.App.GrpcToken)),
+	)
+
 	adapter.Adapters.Sync(
 		adapter.WithRestServer(app),
+		adapter.WithGrpcServer(grpcServer),
 		adapter.WithShopeefunProductPostgres(),
 		adapter.WithValidator(validator.NewValidator()),
+		adapter.WithCache(),
 	)
 
 	route.SetupRoutes(app)
+	route.SetupGrpc(grpcServer)
 
 	// Run server in goroutine
 	go func() {
@@ -82,6 +119,46 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 	}()
 	// End Run server in goroutine
 
+	// Run gRPC server in goroutine
+	go func() {
+		lis, err := net.Listen("tcp", net.JoinHostPort(envs.App.GrpcHost, GRPC_PORT))
+		if err != nil {
+			log.Fatal().Msgf("Error while listening for gRPC: %v", err)
+		}
+
+		// a shutdown before Serve started is not a failure
+		if err := grpcServer.Serve(lis); err != nil && err != grpc.ErrServerStopped {
+			log.Fatal().Msgf("Error while starting gRPC server: %v", err)
+		}
+	}()
+	// End Run gRPC server in goroutine
+
+	// Run the publish scheduler until shutdown, 0 disables it
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		if envs.Product.SchedulerInterval > 0 {
+			interval := time.Duration(envs.Product.SchedulerInterval) * time.Second
+			scheduler.NewPublishScheduler(interval).Run(schedulerCtx)
+		}
+	}()
+	// End Run the publish scheduler
+
+	// Run the webhook dispatcher until shutdown, 0 disables it
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		if envs.Webhook.DispatchInterval > 0 {
+			var (
+				interval = time.Duration(envs.Webhook.DispatchInterval) * time.Second
+				timeout  = time.Duration(envs.Webhook.Timeout) * time.Second
+			)
+			dispatcher.NewWebhookDispatcher(interval, timeout, envs.Webhook.BatchSize, envs.Webhook.MaxAttempts).Run(schedulerCtx)
+		}
+	}()
+	// End Run the webhook dispatcher
+
 	// Handle graceful shutdown
 	quit := make(chan os.Signal, 1)
 
@@ -94,6 +171,11 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 	<-quit
 	log.Info().Msg("Server is shutting down ...")
 
+	// let a scheduler or dispatcher run in progress finish before the database closes
+	stopScheduler()
+	<-schedulerDone
+	<-dispatcherDone
+
 	err = adapter.Adapters.Unsync()
 	if err != nil {
 		log.Error().Msgf("Error while closing adapters: %v", err)
@@ -101,3 +183,13 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 
 	log.Info().Msg("Server gracefully stopped")
 }
+
+// isLoopback reports whether host only accepts connections from this machine.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS product_inquiries (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    product_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    question TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS product_inquiries_product_id_created_at_idx ON product_inquiries (product_id, created_at DESC) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS product_inquiry_answers (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    inquiry_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    answer TEXT NOT NULL,
+    is_accepted BOOLEAN DEFAULT FALSE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+
+    FOREIGN KEY (inquiry_id) REFERENCES product_inquiries(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS product_inquiry_answers_inquiry_id_idx ON product_inquiry_answers (inquiry_id) WHERE deleted_at IS NULL;
+
+-- only one accepted answer per inquiry
+CREATE UNIQUE INDEX IF NOT EXISTS product_inquiry_answers_accepted_uidx ON product_inquiry_answers (inquiry_id) WHERE is_accepted AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_inquiry_answers;
+DROP TABLE IF EXISTS product_inquiries;
+-- +goose StatementEnd
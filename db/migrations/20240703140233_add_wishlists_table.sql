@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS wishlists (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    UNIQUE (user_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS wishlists_product_id_idx ON wishlists (product_id);
+
+-- denormalized counter, maintained together with wishlists rows
+ALTER TABLE products ADD COLUMN IF NOT EXISTS favourite_count INT DEFAULT 0 NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products DROP COLUMN IF EXISTS favourite_count;
+DROP TABLE IF EXISTS wishlists;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS products_live_category_idx ON products (category_id, favourite_count DESC, created_at DESC) WHERE deleted_at IS NULL AND stock > 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS products_live_category_idx;
+-- +goose StatementEnd
@@ -0,0 +1,80 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS brands (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    logo_url TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS brands_normalized_name_uidx ON brands (normalized_name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS brands_slug_uidx ON brands (slug) WHERE deleted_at IS NULL;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS brand_id UUID REFERENCES brands(id);
+ALTER TABLE products ALTER COLUMN brand SET DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS products_brand_id_idx ON products (brand_id) WHERE deleted_at IS NULL;
+
+-- dedupe existing free-text brands, normalization must match pkg.NormalizeBrandName
+CREATE TEMPORARY TABLE product_brand_keys AS
+SELECT
+    id AS product_id,
+    TRIM(brand) AS original,
+    regexp_replace(lower(regexp_replace(TRIM(brand), '\s+', ' ', 'g')), ',?\s+(inc|ltd|llc|corp|co)\.?$', '') AS normalized_name
+FROM
+    products
+WHERE
+    TRIM(brand) <> '';
+
+WITH grouped AS (
+    SELECT
+        normalized_name,
+        MODE() WITHIN GROUP (ORDER BY original) AS name,
+        COALESCE(NULLIF(TRIM(BOTH '-' FROM regexp_replace(normalized_name, '[^a-z0-9]+', '-', 'g')), ''), 'brand') AS slug
+    FROM
+        product_brand_keys
+    GROUP BY
+        normalized_name
+),
+slugged AS (
+    SELECT
+        normalized_name,
+        name,
+        slug,
+        ROW_NUMBER() OVER (PARTITION BY slug ORDER BY normalized_name) AS slug_rank
+    FROM
+        grouped
+)
+INSERT INTO brands (name, normalized_name, slug)
+SELECT
+    name,
+    normalized_name,
+    CASE WHEN slug_rank = 1 THEN slug ELSE slug || '-' || slug_rank END
+FROM
+    slugged;
+
+UPDATE
+    products p
+SET
+    brand_id = b.id,
+    brand = b.name
+FROM
+    product_brand_keys k
+JOIN
+    brands b ON b.normalized_name = k.normalized_name
+WHERE
+    p.id = k.product_id;
+
+DROP TABLE product_brand_keys;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products ALTER COLUMN brand DROP DEFAULT;
+ALTER TABLE products DROP COLUMN IF EXISTS brand_id;
+DROP TABLE IF EXISTS brands;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS category_attributes (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    category_id UUID NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'number', 'enum', 'boolean')),
+    unit VARCHAR(20),
+    options TEXT[] NOT NULL DEFAULT '{}',
+    is_required BOOLEAN DEFAULT FALSE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+
+    FOREIGN KEY (category_id) REFERENCES product_categories(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS category_attributes_category_id_code_uidx ON category_attributes (category_id, code) WHERE deleted_at IS NULL;
+
+-- attribute values keyed by category_attributes.code
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB DEFAULT '{}' NOT NULL;
+
+CREATE INDEX IF NOT EXISTS products_attributes_idx ON products USING GIN (attributes jsonb_path_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS products_attributes_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+DROP TABLE IF EXISTS category_attributes;
+-- +goose StatementEnd
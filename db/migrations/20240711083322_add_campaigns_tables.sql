@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS campaigns (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS campaigns_period_idx ON campaigns (starts_at, ends_at) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS campaign_products (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    campaign_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    shop_id UUID NOT NULL,
+    campaign_price DECIMAL(19, 4) NOT NULL CHECK (campaign_price > 0),
+    quota INT NOT NULL CHECK (quota > 0),
+    sold_count INT DEFAULT 0 NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (shop_id) REFERENCES shops(id),
+    -- last line of defence for the quota, claims are guarded in the UPDATE as well
+    CHECK (sold_count >= 0 AND sold_count <= quota)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS campaign_products_campaign_id_product_id_idx ON campaign_products (campaign_id, product_id);
+CREATE INDEX IF NOT EXISTS campaign_products_product_id_idx ON campaign_products (product_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS campaign_products;
+DROP TABLE IF EXISTS campaigns;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS vouchers (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    shop_id UUID NOT NULL,
+    code VARCHAR(32) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('percentage', 'fixed')),
+    value DECIMAL(19, 4) NOT NULL CHECK (value > 0),
+    min_spend DECIMAL(19, 4) DEFAULT 0 NOT NULL CHECK (min_spend >= 0),
+    usage_limit INT CHECK (usage_limit > 0),
+    per_user_limit INT CHECK (per_user_limit > 0),
+    used_count INT DEFAULT 0 NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    category_ids UUID[] DEFAULT '{}' NOT NULL,
+    product_ids UUID[] DEFAULT '{}' NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+
+    FOREIGN KEY (shop_id) REFERENCES shops(id)
+);
+
+-- codes are stored upper-cased, a shop can reuse a code once the old voucher is deleted
+CREATE UNIQUE INDEX IF NOT EXISTS vouchers_shop_id_code_idx ON vouchers (shop_id, code) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS voucher_usages (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    voucher_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    order_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    FOREIGN KEY (voucher_id) REFERENCES vouchers(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS voucher_usages_voucher_id_order_id_idx ON voucher_usages (voucher_id, order_id);
+CREATE INDEX IF NOT EXISTS voucher_usages_voucher_id_user_id_idx ON voucher_usages (voucher_id, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS voucher_usages;
+DROP TABLE IF EXISTS vouchers;
+-- +goose StatementEnd
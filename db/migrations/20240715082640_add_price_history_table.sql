@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS price_history (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    product_id UUID NOT NULL,
+    price DECIMAL(19, 4) NOT NULL,
+    -- product: the regular price, discount/campaign: a promotional price
+    source VARCHAR(20) NOT NULL CHECK (source IN ('product', 'discount', 'campaign')),
+    reference_id UUID,
+    effective_from TIMESTAMP NOT NULL,
+    -- NULL only for the current regular price
+    effective_to TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX IF NOT EXISTS price_history_product_id_effective_from_idx ON price_history (product_id, effective_from DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS price_history_open_product_price_idx ON price_history (product_id) WHERE source = 'product' AND effective_to IS NULL;
+
+INSERT INTO price_history (product_id, price, source, effective_from)
+SELECT
+    id,
+    price,
+    'product',
+    created_at
+FROM
+    products;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS price_history;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS currencies (
+    code CHAR(3) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    -- decimal places prices in this currency are rounded to
+    scale SMALLINT NOT NULL CHECK (scale BETWEEN 0 AND 4)
+);
+
+INSERT INTO currencies (code, name, scale) VALUES
+    ('IDR', 'Indonesian Rupiah', 0),
+    ('MYR', 'Malaysian Ringgit', 2),
+    ('SGD', 'Singapore Dollar', 2),
+    ('USD', 'US Dollar', 2)
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    rate DECIMAL(19, 8) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (from_currency, to_currency),
+    FOREIGN KEY (from_currency) REFERENCES currencies(code),
+    FOREIGN KEY (to_currency) REFERENCES currencies(code),
+    CHECK (from_currency <> to_currency)
+);
+
+-- every existing product was priced in rupiah
+ALTER TABLE products ADD COLUMN IF NOT EXISTS currency CHAR(3) DEFAULT 'IDR' NOT NULL REFERENCES currencies(code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
+DROP TABLE IF EXISTS exchange_rates;
+DROP TABLE IF EXISTS currencies;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- products listed before moderation existed stay live
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(20) DEFAULT 'published' NOT NULL
+    CHECK (status IN ('draft', 'pending_review', 'published', 'rejected'));
+ALTER TABLE products ALTER COLUMN status SET DEFAULT 'draft';
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS products_moderation_queue_idx ON products (submitted_at) WHERE status = 'pending_review' AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS products_moderation_queue_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE products DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE products DROP COLUMN IF EXISTS submitted_at;
+ALTER TABLE products DROP COLUMN IF EXISTS rejection_reason;
+ALTER TABLE products DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a published product is only live inside [publish_at, unpublish_at), a NULL bound is open
+ALTER TABLE products ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP;
+ALTER TABLE products ADD CONSTRAINT products_schedule_check CHECK (unpublish_at > publish_at);
+
+-- when the scheduler last announced the product going live or offline
+ALTER TABLE products ADD COLUMN IF NOT EXISTS live_event_at TIMESTAMP;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS offline_event_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS products_publish_at_idx ON products (publish_at) WHERE publish_at IS NOT NULL AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS products_unpublish_at_idx ON products (unpublish_at) WHERE unpublish_at IS NOT NULL AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS products_unpublish_at_idx;
+DROP INDEX IF EXISTS products_publish_at_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS offline_event_at;
+ALTER TABLE products DROP COLUMN IF EXISTS live_event_at;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_schedule_check;
+ALTER TABLE products DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE products DROP COLUMN IF EXISTS publish_at;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE outbox_events RENAME TO outbox;
+
+DROP INDEX IF EXISTS outbox_events_unpublished_idx;
+
+-- seq orders events, created_at is shared by every event of a transaction
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS seq BIGSERIAL NOT NULL,
+    ADD COLUMN IF NOT EXISTS attempts INT DEFAULT 0 NOT NULL,
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    ADD COLUMN IF NOT EXISTS last_error TEXT,
+    ADD COLUMN IF NOT EXISTS failed_at TIMESTAMP;
+
+CREATE UNIQUE INDEX IF NOT EXISTS outbox_seq_idx ON outbox (seq);
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (seq) WHERE published_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_pending_aggregate_idx ON outbox (aggregate_id, seq) WHERE published_at IS NULL AND failed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS outbox_pending_aggregate_idx;
+DROP INDEX IF EXISTS outbox_pending_idx;
+DROP INDEX IF EXISTS outbox_seq_idx;
+
+ALTER TABLE outbox
+    DROP COLUMN IF EXISTS failed_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS seq;
+
+ALTER TABLE outbox RENAME TO outbox_events;
+
+CREATE INDEX IF NOT EXISTS outbox_events_unpublished_idx ON outbox_events (created_at) WHERE published_at IS NULL;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- inbound events by message id, a message handled once is skipped when redelivered
+CREATE TABLE IF NOT EXISTS inbox (
+    message_id VARCHAR(255) PRIMARY KEY,
+    subject VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INT DEFAULT 0 NOT NULL,
+    last_error TEXT,
+    received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    processed_at TIMESTAMP,
+    dead_lettered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS inbox_dead_lettered_idx ON inbox (dead_lettered_at) WHERE dead_lettered_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS inbox;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a NULL threshold turns low stock alerts off for the product
+ALTER TABLE products ADD COLUMN IF NOT EXISTS low_stock_threshold INT CHECK (low_stock_threshold >= 0);
+
+-- when the current low stock alert was raised, cleared once the seller restocks
+ALTER TABLE products ADD COLUMN IF NOT EXISTS low_stock_alerted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS products_low_stock_idx ON products (shop_id, stock) WHERE low_stock_threshold IS NOT NULL AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS products_low_stock_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS low_stock_alerted_at;
+ALTER TABLE products DROP COLUMN IF EXISTS low_stock_threshold;
+-- +goose StatementEnd
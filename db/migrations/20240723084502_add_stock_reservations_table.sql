@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- stock taken out for an order, kept to release exactly what was reserved
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    order_id VARCHAR(255) NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    -- the discount whose stock cap the reservation counted against
+    discount_id UUID,
+    discount_quantity INT DEFAULT 0 NOT NULL,
+    reserved_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    released_at TIMESTAMP,
+
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (discount_id) REFERENCES product_discounts(id),
+    UNIQUE (order_id, product_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stock_reservations;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- quota taken by an order, kept so claims are idempotent and can be released
+CREATE TABLE IF NOT EXISTS campaign_claims (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    order_id VARCHAR(255) NOT NULL,
+    campaign_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    claimed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    released_at TIMESTAMP,
+
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    UNIQUE (order_id, campaign_id, product_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS campaign_claims;
+-- +goose StatementEnd
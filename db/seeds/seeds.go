@@ -3,6 +3,8 @@ package seeds
 import (
 	"os"
 	"product-service/internal/adapter"
+	"product-service/pkg"
+	"product-service/pkg/decimal"
 
 	"github.com/brianvoe/gofakeit/v7"
 	"github.com/jmoiron/sqlx"
@@ -34,6 +36,10 @@ func (s *Seed) run(table string, total int) {
 		s.usersSeed(total)
 	case "product_categories":
 		s.ProductCategoriesSeed(total)
+	case "brands":
+		s.BrandsSeed(total)
+	case "products":
+		s.ProductsSeed(total)
 	default:
 		log.Info().Msg("No seed to run")
 	}
@@ -75,6 +81,122 @@ func (s *Seed) ProductCategoriesSeed(total int) {
 	log.Info().Msg("product_categories table seeded successfully")
 }
 
+// this function is used to seed the brands table
+// with random data
+func (s *Seed) BrandsSeed(total int) {
+	var (
+		args  = make([]map[string]any, 0)
+		seen  = make(map[string]bool)
+		query = `
+			INSERT INTO brands (name, normalized_name, slug)
+			VALUES (:name, :normalized_name, :slug)
+			ON CONFLICT DO NOTHING
+		`
+	)
+
+	for i := 0; i < total; i++ {
+		var (
+			name       = gofakeit.Company()
+			normalized = pkg.NormalizeBrandName(name)
+			slug       = pkg.Slugify(normalized)
+			arg        = make(map[string]any)
+		)
+
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		arg["name"] = name
+		arg["normalized_name"] = normalized
+		arg["slug"] = slug
+		args = append(args, arg)
+	}
+
+	_, err := s.db.NamedExec(query, args)
+	if err != nil {
+		log.Error().Err(err).Msg("Error creating brands")
+	}
+
+	log.Info().Msg("brands table seeded successfully")
+}
+
+// this function is used to seed the products table
+// with random data, spread over the existing shops and categories
+func (s *Seed) ProductsSeed(total int) {
+	var (
+		shopIds     []string
+		categoryIds []string
+		args        = make([]map[string]any, 0)
+		query       = `
+			INSERT INTO products (shop_id, category_id, name, description, stock, price, brand)
+			VALUES (:shop_id, :category_id, :name, :description, :stock, :price, :brand)
+		`
+		historyQuery = `
+			INSERT INTO price_history (product_id, price, source, effective_from)
+			SELECT
+				p.id,
+				p.price,
+				'product',
+				p.created_at
+			FROM
+				products p
+			WHERE
+				NOT EXISTS (
+					SELECT 1 FROM price_history ph
+					WHERE ph.product_id = p.id AND ph.source = 'product' AND ph.effective_to IS NULL
+				)
+		`
+	)
+
+	if err := s.db.Select(&shopIds, "SELECT id FROM shops WHERE deleted_at IS NULL"); err != nil {
+		log.Error().Err(err).Msg("Error getting shops")
+		return
+	}
+
+	if err := s.db.Select(&categoryIds, "SELECT id FROM product_categories WHERE deleted_at IS NULL"); err != nil {
+		log.Error().Err(err).Msg("Error getting product categories")
+		return
+	}
+
+	if len(shopIds) == 0 || len(categoryIds) == 0 {
+		log.Warn().Msg("Seed shops and product_categories before products")
+		return
+	}
+
+	for i := 0; i < total; i++ {
+		var (
+			description = gofakeit.ProductDescription()
+			// whole hundreds, the way prices are listed in IDR
+			price = decimal.NewFromInt(int64(gofakeit.Number(10, 50000) * 100))
+			arg   = make(map[string]any)
+		)
+
+		arg["shop_id"] = shopIds[gofakeit.Number(0, len(shopIds)-1)]
+		arg["category_id"] = categoryIds[gofakeit.Number(0, len(categoryIds)-1)]
+		arg["name"] = gofakeit.ProductName()
+		arg["description"] = &description
+		arg["stock"] = gofakeit.Number(0, 500)
+		arg["price"] = price
+		arg["brand"] = gofakeit.Company()
+		args = append(args, arg)
+	}
+
+	_, err := s.db.NamedExec(query, args)
+	if err != nil {
+		log.Error().Err(err).Msg("Error creating products")
+		return
+	}
+
+	// every product has an open regular price, like the API records it
+	_, err = s.db.Exec(historyQuery)
+	if err != nil {
+		log.Error().Err(err).Msg("Error recording product prices")
+	}
+
+	log.Info().Msg("products table seeded successfully")
+}
+
 // this function is used to seed the users table
 // with random data
 func (s *Seed) usersSeed(total int) {
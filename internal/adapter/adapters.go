@@ -2,12 +2,15 @@ package adapter
 
 import (
 	"fmt"
+	"product-service/pkg/broker"
+	"product-service/pkg/cache"
 	"strings"
 
 	// import "product-service/internal/pkg/validator"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
 )
 
 var (
@@ -23,10 +26,13 @@ type Validator interface {
 type Adapter struct {
 	// Driving Adapters
 	RestServer *fiber.App
+	GrpcServer *grpc.Server
 
 	// Driven Adapters
 	ShopeefunProductPostgres *sqlx.DB
 	Validator                Validator // *validator.Validator
+	Broker                   broker.Broker
+	Cache                    cache.Cache
 }
 
 func (a *Adapter) Sync(opts ...Option) {
@@ -46,6 +52,26 @@ func (a *Adapter) Unsync() error {
 		log.Info().Msg("Rest server disconnected")
 	}
 
+	if a.GrpcServer != nil {
+		// waits for the calls in flight, like Shutdown does for requests
+		a.GrpcServer.GracefulStop()
+		log.Info().Msg("gRPC server disconnected")
+	}
+
+	if a.Broker != nil {
+		if err := a.Broker.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+		log.Info().Msg("Broker disconnected")
+	}
+
+	if a.Cache != nil {
+		if err := a.Cache.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+		log.Info().Msg("Cache disconnected")
+	}
+
 	if a.ShopeefunProductPostgres != nil {
 		if err := a.ShopeefunProductPostgres.Close(); err != nil {
 			errs = append(errs, err.Error())
@@ -0,0 +1,61 @@
+package adapter
+
+import (
+	"context"
+	"product-service/internal/infrastructure"
+	"product-service/pkg/broker"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// WithBroker connects the broker named by BROKER_DRIVER. The memory broker
+// only reaches subscribers of the same process, it is meant for tests and
+// in-process use.
+func WithBroker() Option {
+	return func(a *Adapter) {
+		var (
+			envs   = infrastructure.Envs.Broker
+			driver = envs.Driver
+		)
+
+		switch driver {
+		case "memory":
+			a.Broker = broker.NewMemory()
+		case "nats":
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			nats, err := broker.NewNats(ctx, broker.NatsConfig{
+				Url:        envs.NatsUrl,
+				Stream:     envs.NatsStream,
+				Subjects:   strings.Split(envs.NatsSubjects, ","),
+				Durable:    envs.NatsDurable,
+				RetryDelay: time.Duration(infrastructure.Envs.Consumer.RetryDelay) * time.Second,
+			})
+			if err != nil {
+				log.Fatal().Err(err).Msg("Error connecting to NATS")
+			}
+			a.Broker = nats
+		default:
+			log.Fatal().Str("driver", driver).Msg("Unsupported broker driver")
+		}
+
+		log.Info().Str("driver", driver).Msg("Broker connected")
+	}
+}
+
+// WithSharedBroker is WithBroker for processes exchanging events with other
+// processes, like the outbox relay and the consumer. It refuses the memory
+// broker, the relay would mark events published that nobody received and
+// the consumer would wait for messages that never come.
+func WithSharedBroker() Option {
+	return func(a *Adapter) {
+		if driver := infrastructure.Envs.Broker.Driver; driver == "memory" {
+			log.Fatal().Str("driver", driver).Msg("The memory broker doesn't reach other processes, set BROKER_DRIVER=nats")
+		}
+
+		WithBroker()(a)
+	}
+}
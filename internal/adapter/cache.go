@@ -0,0 +1,43 @@
+package adapter
+
+import (
+	"context"
+	"product-service/internal/infrastructure"
+	"product-service/pkg/cache"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// WithCache connects the cache named by CACHE_DRIVER. The memory cache is
+// not shared, an invalidation only reaches the replica making it and the
+// others serve stale entries until their ttl runs out.
+func WithCache() Option {
+	return func(a *Adapter) {
+		var (
+			envs   = infrastructure.Envs.Cache
+			driver = envs.Driver
+		)
+
+		switch driver {
+		case "memory":
+			a.Cache = cache.NewMemory(envs.Size)
+		case "redis":
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			redis, err := cache.NewRedis(ctx, cache.RedisConfig{
+				Url:    envs.RedisUrl,
+				Prefix: envs.RedisPrefix,
+			})
+			if err != nil {
+				log.Fatal().Err(err).Msg("Error connecting to Redis")
+			}
+			a.Cache = redis
+		default:
+			log.Fatal().Str("driver", driver).Msg("Unsupported cache driver")
+		}
+
+		log.Info().Str("driver", driver).Msg("Cache connected")
+	}
+}
@@ -16,6 +16,9 @@ type Config struct {
 	App struct {
 		Environtment string `env:"APP_ENV" env-default:"development"`
 		Port         string `env:"APP_PORT" env-default:"8080"`
+		GrpcPort     string `env:"APP_GRPC_PORT" env-default:"9090" env-description:"port of the gRPC server for internal services"`
+		GrpcHost     string `env:"APP_GRPC_HOST" env-default:"127.0.0.1" env-description:"address the gRPC server listens on, keep it internal"`
+		GrpcToken    string `env:"APP_GRPC_TOKEN" env-description:"bearer token gRPC callers must send, required unless the server only listens on loopback"`
 		LogLevel     string `env:"APP_LOG_LEVEL" env-default:"debug"`
 	}
 	DB struct {
@@ -24,6 +27,44 @@ type Config struct {
 		MaxIdleCons       int `env:"DB_MAX_IdLE_CONS" env-default:"20" env-description:"database max idle conn in seconds"`
 		ConnMaxLifetime   int `env:"DB_CONN_MAX_LIFETIME" env-default:"0" env-description:"database conn max lifetime in seconds"`
 	}
+	Product struct {
+		DefaultCurrency   string `env:"PRODUCT_DEFAULT_CURRENCY" env-default:"IDR" env-description:"currency of products created without one"`
+		FacetPriceBuckets string `env:"PRODUCT_FACET_PRICE_BUCKETS" env-default:"0,50000,100000,500000,1000000,5000000" env-description:"ascending price bounds of the price facet"`
+		SchedulerInterval int    `env:"PRODUCT_SCHEDULER_INTERVAL" env-default:"30" env-description:"seconds between scheduled publishing runs"`
+	}
+	Broker struct {
+		Driver       string `env:"BROKER_DRIVER" env-default:"nats" env-description:"message broker of domain events: nats, or memory for tests and in-process use"`
+		NatsUrl      string `env:"BROKER_NATS_URL" env-default:"nats://localhost:4222"`
+		NatsStream   string `env:"BROKER_NATS_STREAM" env-default:"PRODUCT_EVENTS" env-description:"JetStream stream created for the events of this service"`
+		NatsSubjects string `env:"BROKER_NATS_SUBJECTS" env-default:"product.>,shop.>" env-description:"comma separated subjects of the stream"`
+		NatsDurable  string `env:"BROKER_NATS_DURABLE" env-default:"product-service" env-description:"prefix of the durable consumers"`
+	}
+	Cache struct {
+		Driver      string `env:"CACHE_DRIVER" env-default:"memory" env-description:"cache of product reads: memory or redis"`
+		Size        int    `env:"CACHE_SIZE" env-default:"10000" env-description:"entries kept by the memory cache"`
+		RedisUrl    string `env:"CACHE_REDIS_URL" env-default:"redis://localhost:6379/0"`
+		RedisPrefix string `env:"CACHE_REDIS_PREFIX" env-default:"product-service:"`
+		ProductTTL  int    `env:"CACHE_PRODUCT_TTL" env-default:"60" env-description:"seconds a product or listing stays cached"`
+	}
+	Webhook struct {
+		DispatchInterval int `env:"WEBHOOK_DISPATCH_INTERVAL" env-default:"5" env-description:"seconds between webhook dispatch runs, 0 disables the dispatcher"`
+		Timeout          int `env:"WEBHOOK_TIMEOUT" env-default:"10" env-description:"seconds a webhook receiver has to answer"`
+		BatchSize        int `env:"WEBHOOK_BATCH_SIZE" env-default:"20" env-description:"deliveries sent concurrently per dispatch"`
+		MaxAttempts      int `env:"WEBHOOK_MAX_ATTEMPTS" env-default:"8" env-description:"attempts before a delivery is marked failed"`
+	}
+	Graphql struct {
+		MaxDepth      int `env:"GRAPHQL_MAX_DEPTH" env-default:"6" env-description:"deepest field nesting a GraphQL query may select"`
+		MaxComplexity int `env:"GRAPHQL_MAX_COMPLEXITY" env-default:"1000" env-description:"highest cost of a GraphQL query, lists count once per item they may return"`
+	}
+	Consumer struct {
+		MaxAttempts int `env:"CONSUMER_MAX_ATTEMPTS" env-default:"5" env-description:"handling attempts before an inbound event is dead-lettered"`
+		RetryDelay  int `env:"CONSUMER_RETRY_DELAY" env-default:"10" env-description:"seconds before a failed inbound event is redelivered"`
+	}
+	Outbox struct {
+		RelayInterval int `env:"OUTBOX_RELAY_INTERVAL" env-default:"5" env-description:"seconds between outbox relay runs"`
+		BatchSize     int `env:"OUTBOX_BATCH_SIZE" env-default:"100" env-description:"events published per relay transaction"`
+		MaxAttempts   int `env:"OUTBOX_MAX_ATTEMPTS" env-default:"10" env-description:"publish attempts before an event is marked failed"`
+	}
 	Guard struct {
 		JwtPrivateKey string `env:"JWT_PRIVATE_KEY"`
 	}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CachePolicy is the Cache-Control of a route. Shared caches like a CDN may
+// keep anonymous responses for SharedMaxAge, responses to a request naming
+// a user are private to the user's browser. Private routes are never kept
+// by shared caches.
+type CachePolicy struct {
+	MaxAge       time.Duration
+	SharedMaxAge time.Duration
+	Private      bool
+}
+
+func (p CachePolicy) header(personal bool) string {
+	maxAge := int(p.MaxAge.Seconds())
+
+	if p.Private || personal {
+		return fmt.Sprintf("private, max-age=%d, must-revalidate", maxAge)
+	}
+
+	return fmt.Sprintf("public, max-age=%d, s-maxage=%d", maxAge, int(p.SharedMaxAge.Seconds()))
+}
+
+// CacheControl sets the policy on successful and not modified responses,
+// errors are left uncached. Register it on the route before the handler.
+func CacheControl(policy CachePolicy) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		switch c.Response().StatusCode() {
+		case fiber.StatusOK, fiber.StatusNotModified:
+			personal := c.Query("user_id") != "" || c.Get(fiber.HeaderAuthorization) != ""
+			c.Set(fiber.HeaderCacheControl, policy.header(personal))
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoverUnary answers a panicking gRPC handler with Internal instead of
+// taking the process down, like the recover middleware of the REST server.
+func RecoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			log.Error().Any("panic", e).Str("method", info.FullMethod).Bytes("stack", debug.Stack()).Msg("Recovered from panic in handler")
+			err = status.Error(codes.Internal, "Your request has been failed to process")
+		}
+	}()
+
+	return handler(ctx, req)
+}
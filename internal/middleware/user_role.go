@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"product-service/internal/adapter"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// UserRole loads the role of the user_id query param into c.Locals("role")
+// so AuthRole can be chained after AuthQueryParams.
+func UserRole(c *fiber.Ctx) error {
+	var (
+		role   string
+		userId = c.Query("user_id")
+		db     = adapter.Adapters.ShopeefunProductPostgres
+	)
+
+	query := `SELECT role FROM users WHERE id = $1 AND deleted_at IS NULL`
+
+	if err := db.GetContext(c.Context(), &role, query, userId); err != nil {
+		log.Warn().Err(err).Str("user_id", userId).Msg("middleware: Failed to load user role")
+		return c.Next()
+	}
+
+	c.Locals("role", role)
+	return c.Next()
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	TypeText    = "text"
+	TypeNumber  = "number"
+	TypeEnum    = "enum"
+	TypeBoolean = "boolean"
+)
+
+type CreateAttributeRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	CategoryId string   `params:"id" validate:"required,uuid"`
+	Code       string   `json:"code" validate:"required,min=1,max=50"`
+	Name       string   `json:"name" validate:"required,min=1,max=255"`
+	Type       string   `json:"type" validate:"required,oneof=text number enum boolean"`
+	Unit       *string  `json:"unit" validate:"omitempty,max=20"`
+	Options    []string `json:"options" validate:"omitempty,max=100,unique_in_slice,dive,required,max=100"`
+	IsRequired bool     `json:"is_required"`
+}
+
+// UpdateAttributeRequest can't change code and type, products already store
+// values under them.
+type UpdateAttributeRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	CategoryId string   `params:"id" validate:"required,uuid"`
+	Id         string   `params:"attribute_id" validate:"required,uuid"`
+	Name       string   `json:"name" validate:"required,min=1,max=255"`
+	Unit       *string  `json:"unit" validate:"omitempty,max=20"`
+	Options    []string `json:"options" validate:"omitempty,max=100,unique_in_slice,dive,required,max=100"`
+	IsRequired bool     `json:"is_required"`
+}
+
+type DeleteAttributeRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	CategoryId string `params:"id" validate:"required,uuid"`
+	Id         string `params:"attribute_id" validate:"required,uuid"`
+}
+
+type GetAttributesRequest struct {
+	CategoryId string `params:"id" validate:"required,uuid"`
+}
+
+type AttributeResponse struct {
+	Id         string         `json:"id" db:"id"`
+	CategoryId string         `json:"category_id" db:"category_id"`
+	Code       string         `json:"code" db:"code"`
+	Name       string         `json:"name" db:"name"`
+	Type       string         `json:"type" db:"type"`
+	Unit       *string        `json:"unit" db:"unit"`
+	Options    pq.StringArray `json:"options" db:"options"`
+	IsRequired bool           `json:"is_required" db:"is_required"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+type GetAttributesResponse struct {
+	Items []AttributeResponse `json:"items"`
+}
@@ -0,0 +1,148 @@
+package rest
+
+import (
+	"product-service/internal/adapter"
+	m "product-service/internal/middleware"
+	"product-service/internal/module/attribute/entity"
+	"product-service/internal/module/attribute/ports"
+	"product-service/internal/module/attribute/repository"
+	"product-service/internal/module/attribute/service"
+	"product-service/pkg/errmsg"
+	"product-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type attributeHandler struct {
+	service ports.AttributeService
+}
+
+func NewAttributeHandler() *attributeHandler {
+	repo := repository.NewAttributeRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewAttributeService(repo)
+
+	return &attributeHandler{
+		service: service,
+	}
+}
+
+func (h *attributeHandler) Register(router fiber.Router) {
+	adminOnly := m.AuthRole([]string{"admin"})
+
+	router.Get("/categories/:id/attributes", h.getAttributes)
+	router.Post("/categories/:id/attributes", m.AuthQueryParams, m.UserRole, adminOnly, h.createAttribute)
+	router.Patch("/categories/:id/attributes/:attribute_id", m.AuthQueryParams, m.UserRole, adminOnly, h.updateAttribute)
+	router.Delete("/categories/:id/attributes/:attribute_id", m.AuthQueryParams, m.UserRole, adminOnly, h.deleteAttribute)
+}
+
+func (h *attributeHandler) createAttribute(c *fiber.Ctx) error {
+	var (
+		req = &entity.CreateAttributeRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.CategoryId = c.Params("id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateAttribute(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *attributeHandler) updateAttribute(c *fiber.Ctx) error {
+	var (
+		req = &entity.UpdateAttributeRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.CategoryId = c.Params("id")
+	req.Id = c.Params("attribute_id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpdateAttribute(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *attributeHandler) deleteAttribute(c *fiber.Ctx) error {
+	var (
+		req = &entity.DeleteAttributeRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.CategoryId = c.Params("id")
+	req.Id = c.Params("attribute_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.DeleteAttribute(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *attributeHandler) getAttributes(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetAttributesRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.CategoryId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetAttributes(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/attribute/entity"
+)
+
+type AttributeService interface {
+	CreateAttribute(ctx context.Context, req *entity.CreateAttributeRequest) (entity.AttributeResponse, error)
+	UpdateAttribute(ctx context.Context, req *entity.UpdateAttributeRequest) (entity.AttributeResponse, error)
+	DeleteAttribute(ctx context.Context, req *entity.DeleteAttributeRequest) error
+	GetAttributes(ctx context.Context, req *entity.GetAttributesRequest) (entity.GetAttributesResponse, error)
+}
+
+type AttributeRepository interface {
+	CreateAttribute(ctx context.Context, req *entity.CreateAttributeRequest) (entity.AttributeResponse, error)
+	UpdateAttribute(ctx context.Context, req *entity.UpdateAttributeRequest) (entity.AttributeResponse, error)
+	DeleteAttribute(ctx context.Context, req *entity.DeleteAttributeRequest) error
+	GetAttributes(ctx context.Context, req *entity.GetAttributesRequest) (entity.GetAttributesResponse, error)
+	GetAttributeType(ctx context.Context, categoryId, id string) (string, error)
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"product-service/internal/module/attribute/entity"
+	"product-service/internal/module/attribute/ports"
+	"product-service/pkg/errmsg"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+type attributeRepository struct {
+	db *sqlx.DB
+}
+
+func NewAttributeRepository(db *sqlx.DB) ports.AttributeRepository {
+	return &attributeRepository{
+		db: db,
+	}
+}
+
+func (r *attributeRepository) CreateAttribute(ctx context.Context, req *entity.CreateAttributeRequest) (entity.AttributeResponse, error) {
+	var (
+		res entity.AttributeResponse
+	)
+
+	query := `
+		INSERT INTO
+			category_attributes (category_id, code, name, type, unit, options, is_required)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING
+			id, category_id, code, name, type, unit, options, is_required, created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query,
+		req.CategoryId,
+		req.Code,
+		req.Name,
+		req.Type,
+		req.Unit,
+		pq.Array(req.Options),
+		req.IsRequired,
+	).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateAttribute failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *attributeRepository) UpdateAttribute(ctx context.Context, req *entity.UpdateAttributeRequest) (entity.AttributeResponse, error) {
+	var (
+		res entity.AttributeResponse
+	)
+
+	query := `
+		UPDATE
+			category_attributes
+		SET
+			name = $1,
+			unit = $2,
+			options = $3,
+			is_required = $4,
+			updated_at = NOW()
+		WHERE
+			id = $5
+			AND category_id = $6
+			AND deleted_at IS NULL
+		RETURNING
+			id, category_id, code, name, type, unit, options, is_required, created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query,
+		req.Name,
+		req.Unit,
+		pq.Array(req.Options),
+		req.IsRequired,
+		req.Id,
+		req.CategoryId,
+	).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Attribute not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Attribute not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateAttribute failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *attributeRepository) DeleteAttribute(ctx context.Context, req *entity.DeleteAttributeRequest) error {
+	query := `
+		UPDATE
+			category_attributes
+		SET
+			deleted_at = NOW()
+		WHERE
+			id = $1
+			AND category_id = $2
+			AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, req.Id, req.CategoryId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteAttribute failed")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteAttribute failed")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("payload", req).Msg("repository: Attribute not found")
+		return errmsg.NewCostumErrors(404, errmsg.WithMessage("Attribute not found"))
+	}
+
+	return nil
+}
+
+func (r *attributeRepository) GetAttributes(ctx context.Context, req *entity.GetAttributesRequest) (entity.GetAttributesResponse, error) {
+	var (
+		res entity.GetAttributesResponse
+	)
+	res.Items = make([]entity.AttributeResponse, 0)
+
+	query := `
+		SELECT
+			id,
+			category_id,
+			code,
+			name,
+			type,
+			unit,
+			options,
+			is_required,
+			created_at,
+			updated_at
+		FROM
+			category_attributes
+		WHERE
+			category_id = $1
+			AND deleted_at IS NULL
+		ORDER BY name ASC
+	`
+
+	err := r.db.SelectContext(ctx, &res.Items, query, req.CategoryId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetAttributes failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *attributeRepository) GetAttributeType(ctx context.Context, categoryId, id string) (string, error) {
+	var (
+		attrType string
+		payload  = struct {
+			CategoryId string `json:"category_id"`
+			Id         string `json:"id"`
+		}{categoryId, id}
+	)
+
+	query := `
+		SELECT
+			type
+		FROM
+			category_attributes
+		WHERE
+			id = $1
+			AND category_id = $2
+			AND deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &attrType, query, id, categoryId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", payload).Msg("repository: Attribute not found")
+			return attrType, errmsg.NewCostumErrors(404, errmsg.WithMessage("Attribute not found"))
+		}
+		log.Error().Err(err).Any("payload", payload).Msg("repository: GetAttributeType failed")
+		return attrType, err
+	}
+
+	return attrType, nil
+}
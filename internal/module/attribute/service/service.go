@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/attribute/entity"
+	"product-service/internal/module/attribute/ports"
+	"product-service/pkg"
+	"product-service/pkg/errmsg"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+type attributeService struct {
+	repo ports.AttributeRepository
+}
+
+func NewAttributeService(r ports.AttributeRepository) ports.AttributeService {
+	return &attributeService{
+		repo: r,
+	}
+}
+
+func (s *attributeService) CreateAttribute(ctx context.Context, req *entity.CreateAttributeRequest) (entity.AttributeResponse, error) {
+	var res entity.AttributeResponse
+
+	// the range suffixes of attribute filters are reserved
+	if !pkg.IsAttributeCode(req.Code) || strings.HasSuffix(req.Code, "_min") || strings.HasSuffix(req.Code, "_max") {
+		log.Warn().Any("payload", req).Msg("service: Invalid attribute code")
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("code", "code must be lowercase snake case and must not end with _min or _max."))
+	}
+
+	if err := validateOptions(req.Type, req.Options); err != nil {
+		log.Warn().Any("payload", req).Msg("service: Invalid attribute options")
+		return res, err
+	}
+
+	if req.Options == nil {
+		req.Options = make([]string, 0)
+	}
+
+	return s.repo.CreateAttribute(ctx, req)
+}
+
+func (s *attributeService) UpdateAttribute(ctx context.Context, req *entity.UpdateAttributeRequest) (entity.AttributeResponse, error) {
+	var res entity.AttributeResponse
+
+	attrType, err := s.repo.GetAttributeType(ctx, req.CategoryId, req.Id)
+	if err != nil {
+		return res, err
+	}
+
+	if err := validateOptions(attrType, req.Options); err != nil {
+		log.Warn().Any("payload", req).Msg("service: Invalid attribute options")
+		return res, err
+	}
+
+	if req.Options == nil {
+		req.Options = make([]string, 0)
+	}
+
+	return s.repo.UpdateAttribute(ctx, req)
+}
+
+func (s *attributeService) DeleteAttribute(ctx context.Context, req *entity.DeleteAttributeRequest) error {
+	return s.repo.DeleteAttribute(ctx, req)
+}
+
+func (s *attributeService) GetAttributes(ctx context.Context, req *entity.GetAttributesRequest) (entity.GetAttributesResponse, error) {
+	return s.repo.GetAttributes(ctx, req)
+}
+
+func validateOptions(attrType string, options []string) error {
+	if attrType == entity.TypeEnum && len(options) == 0 {
+		return errmsg.NewCostumErrors(422, errmsg.WithErrors("options", "options is required for enum attributes."))
+	}
+
+	if attrType != entity.TypeEnum && len(options) > 0 {
+		return errmsg.NewCostumErrors(422, errmsg.WithErrors("options", "options is only allowed for enum attributes."))
+	}
+
+	return nil
+}
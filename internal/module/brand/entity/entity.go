@@ -0,0 +1,85 @@
+package entity
+
+import "time"
+
+type CreateBrandRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	Name    string  `json:"name" validate:"required,min=1,max=255"`
+	LogoUrl *string `json:"logo_url" validate:"omitempty,url"`
+
+	NormalizedName string `json:"-"`
+	Slug           string `json:"-"`
+}
+
+type UpdateBrandRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	Id      string  `params:"id" validate:"required,uuid"`
+	Name    string  `json:"name" validate:"required,min=1,max=255"`
+	LogoUrl *string `json:"logo_url" validate:"omitempty,url"`
+
+	NormalizedName string `json:"-"`
+	Slug           string `json:"-"`
+}
+
+type DeleteBrandRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	Id string `params:"id" validate:"required,uuid"`
+}
+
+// GetBrandRequest looks a brand up by id or by slug.
+type GetBrandRequest struct {
+	IdOrSlug string `params:"id" validate:"required,max=255"`
+}
+
+type GetBrandsRequest struct {
+	Name string `query:"name" validate:"omitempty,max=255"`
+
+	Page  int `query:"page" validate:"required,min=1"`
+	Limit int `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetBrandsRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type BrandResponse struct {
+	Id        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Slug      string    `json:"slug" db:"slug"`
+	LogoUrl   *string   `json:"logo_url" db:"logo_url"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type GetBrandsResponse struct {
+	Items []BrandResponse `json:"items"`
+	Meta  Meta            `json:"meta"`
+}
+
+type Meta struct {
+	TotalData int `json:"total_data"`
+	TotalPage int `json:"total_page"`
+	Page      int `json:"page"`
+	Limit     int `json:"limit"`
+}
+
+func (m *Meta) CountTotalPage() {
+	if m.TotalData == 0 {
+		m.TotalPage = 0
+		return
+	}
+
+	m.TotalPage = m.TotalData / m.Limit
+	if m.TotalData%m.Limit > 0 {
+		m.TotalPage++
+	}
+}
@@ -0,0 +1,175 @@
+package rest
+
+import (
+	"product-service/internal/adapter"
+	m "product-service/internal/middleware"
+	"product-service/internal/module/brand/entity"
+	"product-service/internal/module/brand/ports"
+	"product-service/internal/module/brand/repository"
+	"product-service/internal/module/brand/service"
+	"product-service/pkg/errmsg"
+	"product-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type brandHandler struct {
+	service ports.BrandService
+}
+
+func NewBrandHandler() *brandHandler {
+	repo := repository.NewBrandRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewBrandService(repo, adapter.Adapters.Cache)
+
+	return &brandHandler{
+		service: service,
+	}
+}
+
+func (h *brandHandler) Register(router fiber.Router) {
+	adminOnly := m.AuthRole([]string{"admin"})
+
+	router.Get("/brands", h.getBrands)
+	router.Get("/brands/:id", h.getBrand)
+	router.Post("/brands", m.AuthQueryParams, m.UserRole, adminOnly, h.createBrand)
+	router.Patch("/brands/:id", m.AuthQueryParams, m.UserRole, adminOnly, h.updateBrand)
+	router.Delete("/brands/:id", m.AuthQueryParams, m.UserRole, adminOnly, h.deleteBrand)
+}
+
+func (h *brandHandler) createBrand(c *fiber.Ctx) error {
+	var (
+		req = &entity.CreateBrandRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateBrand(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *brandHandler) updateBrand(c *fiber.Ctx) error {
+	var (
+		req = &entity.UpdateBrandRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.Id = c.Params("id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpdateBrand(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *brandHandler) deleteBrand(c *fiber.Ctx) error {
+	var (
+		req = &entity.DeleteBrandRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.DeleteBrand(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *brandHandler) getBrand(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetBrandRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.IdOrSlug = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetBrand(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *brandHandler) getBrands(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetBrandsRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetBrands(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
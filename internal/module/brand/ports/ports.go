@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/brand/entity"
+)
+
+type BrandService interface {
+	CreateBrand(ctx context.Context, req *entity.CreateBrandRequest) (entity.BrandResponse, error)
+	UpdateBrand(ctx context.Context, req *entity.UpdateBrandRequest) (entity.BrandResponse, error)
+	DeleteBrand(ctx context.Context, req *entity.DeleteBrandRequest) error
+	GetBrand(ctx context.Context, req *entity.GetBrandRequest) (entity.BrandResponse, error)
+	GetBrands(ctx context.Context, req *entity.GetBrandsRequest) (entity.GetBrandsResponse, error)
+}
+
+type BrandRepository interface {
+	CreateBrand(ctx context.Context, req *entity.CreateBrandRequest) (entity.BrandResponse, error)
+	UpdateBrand(ctx context.Context, req *entity.UpdateBrandRequest) (entity.BrandResponse, []string, error)
+	DeleteBrand(ctx context.Context, req *entity.DeleteBrandRequest) error
+	GetBrand(ctx context.Context, req *entity.GetBrandRequest) (entity.BrandResponse, error)
+	GetBrands(ctx context.Context, req *entity.GetBrandsRequest) (entity.GetBrandsResponse, error)
+}
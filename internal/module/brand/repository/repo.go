@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"product-service/internal/module/brand/entity"
+	"product-service/internal/module/brand/ports"
+	outboxEntity "product-service/internal/module/outbox/entity"
+	outboxRepository "product-service/internal/module/outbox/repository"
+	productEntity "product-service/internal/module/product/entity"
+	"product-service/pkg/errmsg"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type brandRepository struct {
+	db *sqlx.DB
+}
+
+func NewBrandRepository(db *sqlx.DB) ports.BrandRepository {
+	return &brandRepository{
+		db: db,
+	}
+}
+
+func (r *brandRepository) CreateBrand(ctx context.Context, req *entity.CreateBrandRequest) (entity.BrandResponse, error) {
+	var (
+		res entity.BrandResponse
+	)
+
+	query := `
+		INSERT INTO
+			brands (name, normalized_name, slug, logo_url)
+		VALUES ($1, $2, $3, $4)
+		RETURNING
+			id, name, slug, logo_url, created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, req.Name, req.NormalizedName, req.Slug, req.LogoUrl).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateBrand failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// UpdateBrand renames the brand and its products, it returns the ids of the
+// renamed products.
+func (r *brandRepository) UpdateBrand(ctx context.Context, req *entity.UpdateBrandRequest) (entity.BrandResponse, []string, error) {
+	var (
+		res entity.BrandResponse
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateBrand failed to begin transaction")
+		return res, nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE
+			brands
+		SET
+			name = $1,
+			normalized_name = $2,
+			slug = $3,
+			logo_url = $4,
+			updated_at = NOW()
+		WHERE
+			id = $5
+			AND deleted_at IS NULL
+		RETURNING
+			id, name, slug, logo_url, created_at, updated_at
+	`
+
+	err = tx.QueryRowxContext(ctx, query, req.Name, req.NormalizedName, req.Slug, req.LogoUrl, req.Id).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Brand not found")
+			return res, nil, errmsg.NewCostumErrors(404, errmsg.WithMessage("Brand not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateBrand failed")
+		return res, nil, err
+	}
+
+	// keep the denormalized products.brand column in sync, the renamed
+	// products are changed products for caches and subscribers too
+	productsQuery := `
+		UPDATE
+			products
+		SET
+			brand = $1,
+			updated_at = NOW()
+		WHERE
+			brand_id = $2
+			AND deleted_at IS NULL
+			AND brand <> $1
+		RETURNING
+			id, shop_id, category_id, brand_id, brand, name, description, image_url, price, currency, stock, low_stock_threshold, status, publish_at, unpublish_at, attributes, created_at, updated_at
+	`
+
+	var products []productEntity.UpsertProductResponse
+
+	err = sqlx.SelectContext(ctx, tx, &products, productsQuery, req.Name, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateBrand failed to sync products")
+		return res, nil, err
+	}
+
+	productIds := make([]string, 0, len(products))
+	for _, product := range products {
+		productIds = append(productIds, product.Id)
+
+		err = outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+			AggregateType: outboxEntity.AggregateProduct,
+			AggregateId:   product.Id,
+			EventType:     outboxEntity.EventProductUpdated,
+			ShopId:        product.ShopId,
+			Payload:       productEntity.NewProductEvent(product),
+		})
+		if err != nil {
+			return res, nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateBrand failed to commit transaction")
+		return res, nil, err
+	}
+
+	return res, productIds, nil
+}
+
+func (r *brandRepository) DeleteBrand(ctx context.Context, req *entity.DeleteBrandRequest) error {
+	query := `
+		UPDATE
+			brands
+		SET
+			deleted_at = NOW()
+		WHERE
+			id = $1
+			AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteBrand failed")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteBrand failed")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("payload", req).Msg("repository: Brand not found")
+		return errmsg.NewCostumErrors(404, errmsg.WithMessage("Brand not found"))
+	}
+
+	return nil
+}
+
+func (r *brandRepository) GetBrand(ctx context.Context, req *entity.GetBrandRequest) (entity.BrandResponse, error) {
+	var (
+		res entity.BrandResponse
+	)
+
+	query := `
+		SELECT
+			id,
+			name,
+			slug,
+			logo_url,
+			created_at,
+			updated_at
+		FROM
+			brands
+		WHERE
+			(id::text = $1 OR slug = $1)
+			AND deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &res, query, req.IdOrSlug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Brand not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Brand not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetBrand failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *brandRepository) GetBrands(ctx context.Context, req *entity.GetBrandsRequest) (entity.GetBrandsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.BrandResponse
+	}
+	var (
+		res  entity.GetBrandsResponse
+		data = make([]dao, 0)
+		arg  = make(map[string]any)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.BrandResponse, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			id,
+			name,
+			slug,
+			logo_url,
+			created_at,
+			updated_at
+		FROM
+			brands
+		WHERE
+			deleted_at IS NULL
+	`
+
+	if req.Name != "" {
+		query += " AND name ILIKE '%' || :name || '%'"
+		arg["name"] = req.Name
+	}
+
+	query += `
+		ORDER BY name ASC
+		LIMIT :limit
+		OFFSET :offset
+	`
+	arg["limit"] = req.Limit
+	arg["offset"] = (req.Page - 1) * req.Limit
+
+	nstmt, err := r.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetBrands failed")
+		return res, err
+	}
+	defer nstmt.Close()
+
+	err = nstmt.SelectContext(ctx, &data, arg)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetBrands failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.BrandResponse)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/brand/entity"
+	"product-service/internal/module/brand/ports"
+	productService "product-service/internal/module/product/service"
+	"product-service/pkg"
+	"product-service/pkg/cache"
+	"product-service/pkg/errmsg"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+type brandService struct {
+	repo  ports.BrandRepository
+	cache cache.Cache
+}
+
+func NewBrandService(r ports.BrandRepository, c cache.Cache) ports.BrandService {
+	return &brandService{
+		repo:  r,
+		cache: c,
+	}
+}
+
+func (s *brandService) CreateBrand(ctx context.Context, req *entity.CreateBrandRequest) (entity.BrandResponse, error) {
+	var res entity.BrandResponse
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.NormalizedName = pkg.NormalizeBrandName(req.Name)
+	req.Slug = pkg.Slugify(req.NormalizedName)
+
+	if req.Slug == "" {
+		log.Warn().Any("payload", req).Msg("service: Brand name can't be slugified")
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("name", "name must contain at least one letter or number."))
+	}
+
+	return s.repo.CreateBrand(ctx, req)
+}
+
+func (s *brandService) UpdateBrand(ctx context.Context, req *entity.UpdateBrandRequest) (entity.BrandResponse, error) {
+	var res entity.BrandResponse
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.NormalizedName = pkg.NormalizeBrandName(req.Name)
+	req.Slug = pkg.Slugify(req.NormalizedName)
+
+	if req.Slug == "" {
+		log.Warn().Any("payload", req).Msg("service: Brand name can't be slugified")
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("name", "name must contain at least one letter or number."))
+	}
+
+	res, productIds, err := s.repo.UpdateBrand(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	// the renamed products carry the brand name
+	if len(productIds) > 0 {
+		productService.InvalidateProducts(ctx, s.cache, productIds...)
+	}
+
+	return res, nil
+}
+
+func (s *brandService) DeleteBrand(ctx context.Context, req *entity.DeleteBrandRequest) error {
+	return s.repo.DeleteBrand(ctx, req)
+}
+
+func (s *brandService) GetBrand(ctx context.Context, req *entity.GetBrandRequest) (entity.BrandResponse, error) {
+	return s.repo.GetBrand(ctx, req)
+}
+
+func (s *brandService) GetBrands(ctx context.Context, req *entity.GetBrandsRequest) (entity.GetBrandsResponse, error) {
+	return s.repo.GetBrands(ctx, req)
+}
@@ -0,0 +1,141 @@
+package entity
+
+import (
+	"product-service/pkg/decimal"
+	"time"
+)
+
+type CreateCampaignRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	Name     string    `json:"name" validate:"required,min=1,max=255"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+}
+
+type DeleteCampaignRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	Id string `params:"id" validate:"required,uuid"`
+}
+
+type GetCampaignsRequest struct {
+	Page  int `query:"page" validate:"required,min=1"`
+	Limit int `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetCampaignsRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type CampaignResponse struct {
+	Id        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	IsLive    bool      `json:"is_live" db:"is_live"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type GetCampaignsResponse struct {
+	Items []CampaignResponse `json:"items"`
+	Meta  Meta               `json:"meta"`
+}
+
+type EnrolProductRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	CampaignId    string          `params:"id" validate:"required,uuid"`
+	ProductId     string          `json:"product_id" validate:"required,uuid"`
+	CampaignPrice decimal.Decimal `json:"campaign_price" validate:"required,gt=0"`
+	Quota         int             `json:"quota" validate:"required,min=1"`
+}
+
+type CampaignProductResponse struct {
+	Id            string          `json:"id" db:"id"`
+	CampaignId    string          `json:"campaign_id" db:"campaign_id"`
+	ProductId     string          `json:"product_id" db:"product_id"`
+	ShopId        string          `json:"shop_id" db:"shop_id"`
+	CampaignPrice decimal.Decimal `json:"campaign_price" db:"campaign_price"`
+	Quota         int             `json:"quota" db:"quota"`
+	SoldCount     int             `json:"sold_count" db:"sold_count"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// ClaimQuotaRequest takes quantity units from a live campaign product for an
+// order, it is called by the order service when a buyer checks out at the
+// campaign price. An order claims a product once, claiming it again returns
+// the first claim.
+type ClaimQuotaRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	CampaignId string `params:"id" validate:"required,uuid"`
+	ProductId  string `params:"product_id" validate:"required,uuid"`
+	OrderId    string `json:"order_id" validate:"required,max=255"`
+	Quantity   int    `json:"quantity" validate:"required,min=1,max=100000"`
+}
+
+// ReleaseClaimRequest gives the quota the order claimed back to the campaign.
+type ReleaseClaimRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	CampaignId string `params:"id" validate:"required,uuid"`
+	ProductId  string `params:"product_id" validate:"required,uuid"`
+	OrderId    string `json:"order_id" validate:"required,max=255"`
+}
+
+type ClaimQuotaResponse struct {
+	CampaignId     string `json:"campaign_id" db:"campaign_id"`
+	ProductId      string `json:"product_id" db:"product_id"`
+	OrderId        string `json:"order_id" db:"order_id"`
+	Quantity       int    `json:"quantity" db:"quantity"`
+	RemainingQuota int    `json:"remaining_quota" db:"remaining_quota"`
+}
+
+type LiveCampaignProduct struct {
+	CampaignId     string          `json:"campaign_id" db:"campaign_id"`
+	CampaignName   string          `json:"campaign_name" db:"campaign_name"`
+	EndsAt         time.Time       `json:"ends_at" db:"ends_at"`
+	ProductId      string          `json:"product_id" db:"product_id"`
+	ShopId         string          `json:"shop_id" db:"shop_id"`
+	Name           string          `json:"name" db:"name"`
+	ImageUrl       *string         `json:"image_url" db:"image_url"`
+	Price          decimal.Decimal `json:"price" db:"price"`
+	CampaignPrice  decimal.Decimal `json:"campaign_price" db:"campaign_price"`
+	Quota          int             `json:"quota" db:"quota"`
+	RemainingQuota int             `json:"remaining_quota" db:"remaining_quota"`
+}
+
+type GetLiveCampaignProductsResponse struct {
+	Items []LiveCampaignProduct `json:"items"`
+	// CachedAt tells clients how old remaining_quota may be, claims are
+	// always checked against the database.
+	CachedAt time.Time `json:"cached_at"`
+}
+
+type Meta struct {
+	TotalData int `json:"total_data"`
+	TotalPage int `json:"total_page"`
+	Page      int `json:"page"`
+	Limit     int `json:"limit"`
+}
+
+func (m *Meta) CountTotalPage() {
+	if m.TotalData == 0 {
+		m.TotalPage = 0
+		return
+	}
+
+	m.TotalPage = m.TotalData / m.Limit
+	if m.TotalData%m.Limit > 0 {
+		m.TotalPage++
+	}
+}
@@ -0,0 +1,230 @@
+package rest
+
+import (
+	"product-service/internal/adapter"
+	m "product-service/internal/middleware"
+	"product-service/internal/module/campaign/entity"
+	"product-service/internal/module/campaign/ports"
+	"product-service/internal/module/campaign/repository"
+	"product-service/internal/module/campaign/service"
+	"product-service/pkg/errmsg"
+	"product-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type campaignHandler struct {
+	service ports.CampaignService
+}
+
+func NewCampaignHandler() *campaignHandler {
+	repo := repository.NewCampaignRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewCampaignService(repo, adapter.Adapters.Cache)
+
+	return &campaignHandler{
+		service: service,
+	}
+}
+
+func (h *campaignHandler) Register(router fiber.Router) {
+	adminOnly := m.AuthRole([]string{"admin"})
+
+	router.Get("/campaigns", h.getCampaigns)
+	router.Get("/campaigns/live", h.getLiveCampaignProducts)
+	router.Post("/campaigns", m.AuthQueryParams, m.UserRole, adminOnly, h.createCampaign)
+	router.Delete("/campaigns/:id", m.AuthQueryParams, m.UserRole, adminOnly, h.deleteCampaign)
+	router.Post("/campaigns/:id/products", m.AuthQueryParams, h.enrolProduct)
+	// claims are taken and released by the order service for its orders
+	router.Post("/campaigns/:id/products/:product_id/claim", m.AuthQueryParams, m.UserRole, adminOnly, h.claimQuota)
+	router.Post("/campaigns/:id/products/:product_id/claim/release", m.AuthQueryParams, m.UserRole, adminOnly, h.releaseClaim)
+}
+
+func (h *campaignHandler) createCampaign(c *fiber.Ctx) error {
+	var (
+		req = &entity.CreateCampaignRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateCampaign(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *campaignHandler) deleteCampaign(c *fiber.Ctx) error {
+	var (
+		req = &entity.DeleteCampaignRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.DeleteCampaign(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *campaignHandler) getCampaigns(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetCampaignsRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetCampaigns(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *campaignHandler) enrolProduct(c *fiber.Ctx) error {
+	var (
+		req = &entity.EnrolProductRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.CampaignId = c.Params("id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.EnrolProduct(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *campaignHandler) claimQuota(c *fiber.Ctx) error {
+	var (
+		req = &entity.ClaimQuotaRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.CampaignId = c.Params("id")
+	req.ProductId = c.Params("product_id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.ClaimQuota(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *campaignHandler) releaseClaim(c *fiber.Ctx) error {
+	var (
+		req = &entity.ReleaseClaimRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.CampaignId = c.Params("id")
+	req.ProductId = c.Params("product_id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.ReleaseClaim(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *campaignHandler) getLiveCampaignProducts(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	resp, err := h.service.GetLiveCampaignProducts(ctx)
+	if err != nil {
+		code, errs := errmsg.Errors[any](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	// lets a CDN in front of us absorb the burst as well
+	c.Set(fiber.HeaderCacheControl, "public, max-age=2")
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/campaign/entity"
+	"product-service/pkg/decimal"
+	"time"
+)
+
+type CampaignService interface {
+	CreateCampaign(ctx context.Context, req *entity.CreateCampaignRequest) (entity.CampaignResponse, error)
+	DeleteCampaign(ctx context.Context, req *entity.DeleteCampaignRequest) error
+	GetCampaigns(ctx context.Context, req *entity.GetCampaignsRequest) (entity.GetCampaignsResponse, error)
+	EnrolProduct(ctx context.Context, req *entity.EnrolProductRequest) (entity.CampaignProductResponse, error)
+	ClaimQuota(ctx context.Context, req *entity.ClaimQuotaRequest) (entity.ClaimQuotaResponse, error)
+	ReleaseClaim(ctx context.Context, req *entity.ReleaseClaimRequest) (entity.ClaimQuotaResponse, error)
+	GetLiveCampaignProducts(ctx context.Context) (entity.GetLiveCampaignProductsResponse, error)
+}
+
+type CampaignRepository interface {
+	CreateCampaign(ctx context.Context, req *entity.CreateCampaignRequest) (entity.CampaignResponse, error)
+	DeleteCampaign(ctx context.Context, req *entity.DeleteCampaignRequest) ([]string, error)
+	GetCampaigns(ctx context.Context, req *entity.GetCampaignsRequest) (entity.GetCampaignsResponse, error)
+	EnrolProduct(ctx context.Context, req *entity.EnrolProductRequest) (entity.CampaignProductResponse, error)
+	ClaimQuota(ctx context.Context, req *entity.ClaimQuotaRequest) (entity.ClaimQuotaResponse, error)
+	ReleaseClaim(ctx context.Context, req *entity.ReleaseClaimRequest) (entity.ClaimQuotaResponse, error)
+	GetLiveCampaignProducts(ctx context.Context) ([]entity.LiveCampaignProduct, error)
+
+	GetCampaignPeriod(ctx context.Context, campaignId string) (startsAt, endsAt time.Time, err error)
+	GetOwnedProductStock(ctx context.Context, userId, productId string) (price decimal.Decimal, stock int, err error)
+	IsProductInOverlappingCampaign(ctx context.Context, productId string, startsAt, endsAt time.Time) (bool, error)
+}
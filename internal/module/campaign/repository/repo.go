@@ -0,0 +1,588 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"product-service/internal/module/campaign/entity"
+	"product-service/internal/module/campaign/ports"
+	productRepository "product-service/internal/module/product/repository"
+	"product-service/pkg/decimal"
+	"product-service/pkg/errmsg"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+type campaignRepository struct {
+	db *sqlx.DB
+}
+
+func NewCampaignRepository(db *sqlx.DB) ports.CampaignRepository {
+	return &campaignRepository{
+		db: db,
+	}
+}
+
+func (r *campaignRepository) CreateCampaign(ctx context.Context, req *entity.CreateCampaignRequest) (entity.CampaignResponse, error) {
+	var (
+		res entity.CampaignResponse
+	)
+
+	query := `
+		INSERT INTO
+			campaigns (name, starts_at, ends_at)
+		VALUES ($1, $2, $3)
+		RETURNING
+			id,
+			name,
+			starts_at,
+			ends_at,
+			(starts_at <= NOW() AND ends_at > NOW()) AS is_live,
+			created_at,
+			updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, req.Name, req.StartsAt.UTC(), req.EndsAt.UTC()).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateCampaign failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// DeleteCampaign returns the ids of the products that were enrolled.
+func (r *campaignRepository) DeleteCampaign(ctx context.Context, req *entity.DeleteCampaignRequest) ([]string, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteCampaign failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE
+			campaigns
+		SET
+			deleted_at = NOW()
+		WHERE
+			id = $1
+			AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteCampaign failed")
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteCampaign failed")
+		return nil, err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("payload", req).Msg("repository: Campaign not found")
+		return nil, errmsg.NewCostumErrors(404, errmsg.WithMessage("Campaign not found"))
+	}
+
+	productIds := make([]string, 0)
+
+	err = tx.SelectContext(ctx, &productIds, `SELECT product_id FROM campaign_products WHERE campaign_id = $1`, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteCampaign failed to get products")
+		return nil, err
+	}
+
+	if err = productRepository.SyncOfferPrices(ctx, tx, productIds); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteCampaign failed to commit transaction")
+		return nil, err
+	}
+
+	return productIds, nil
+}
+
+func (r *campaignRepository) GetCampaigns(ctx context.Context, req *entity.GetCampaignsRequest) (entity.GetCampaignsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.CampaignResponse
+	}
+	var (
+		res  entity.GetCampaignsResponse
+		data = make([]dao, 0)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.CampaignResponse, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			id,
+			name,
+			starts_at,
+			ends_at,
+			(starts_at <= NOW() AND ends_at > NOW()) AS is_live,
+			created_at,
+			updated_at
+		FROM
+			campaigns
+		WHERE
+			deleted_at IS NULL
+			AND ends_at > NOW()
+		ORDER BY starts_at ASC
+		LIMIT $1
+		OFFSET $2
+	`
+
+	err := r.db.SelectContext(ctx, &data, query, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetCampaigns failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.CampaignResponse)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+func (r *campaignRepository) EnrolProduct(ctx context.Context, req *entity.EnrolProductRequest) (entity.CampaignProductResponse, error) {
+	var (
+		res entity.CampaignProductResponse
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: EnrolProduct failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO
+			campaign_products (campaign_id, product_id, shop_id, campaign_price, quota)
+		SELECT
+			$1, id, shop_id, $3, $4
+		FROM
+			products
+		WHERE
+			id = $2
+		RETURNING
+			id,
+			campaign_id,
+			product_id,
+			shop_id,
+			campaign_price,
+			quota,
+			sold_count,
+			created_at,
+			updated_at
+	`
+
+	err = tx.QueryRowxContext(ctx, query, req.CampaignId, req.ProductId, req.CampaignPrice, req.Quota).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: EnrolProduct failed")
+		return res, err
+	}
+
+	if err = productRepository.SyncOfferPrices(ctx, tx, []string{req.ProductId}); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: EnrolProduct failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// ClaimQuota takes the quota for an order in a single conditional UPDATE, so
+// concurrent claims serialise on the row lock and can never oversell. A claim
+// that does not fit the remaining quota affects no rows instead of going
+// negative. The claim is recorded per order, claiming again returns it.
+func (r *campaignRepository) ClaimQuota(ctx context.Context, req *entity.ClaimQuotaRequest) (entity.ClaimQuotaResponse, error) {
+	var (
+		res = entity.ClaimQuotaResponse{OrderId: req.OrderId, Quantity: req.Quantity}
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ClaimQuota failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	// a concurrent claim of the same order waits here until the first one
+	// commits or rolls back
+	query := `
+		INSERT INTO
+			campaign_claims (order_id, campaign_id, product_id, quantity)
+		VALUES
+			($1, $2, $3, $4)
+		ON CONFLICT (order_id, campaign_id, product_id) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx, query, req.OrderId, req.CampaignId, req.ProductId, req.Quantity)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ClaimQuota failed to record the claim")
+		return res, err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ClaimQuota failed")
+		return res, err
+	} else if affected == 0 {
+		return r.claimed(ctx, tx, req)
+	}
+
+	query = `
+		UPDATE
+			campaign_products cp
+		SET
+			sold_count = cp.sold_count + $3,
+			updated_at = NOW()
+		FROM
+			campaigns c
+		WHERE
+			c.id = cp.campaign_id
+			AND cp.campaign_id = $1
+			AND cp.product_id = $2
+			AND cp.sold_count + $3 <= cp.quota
+			AND c.deleted_at IS NULL
+			AND c.starts_at <= NOW()
+			AND c.ends_at > NOW()
+		RETURNING
+			cp.campaign_id,
+			cp.product_id,
+			cp.quota - cp.sold_count AS remaining_quota
+	`
+
+	err = tx.QueryRowxContext(ctx, query, req.CampaignId, req.ProductId, req.Quantity).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Campaign quota not available")
+			return res, errmsg.NewCostumErrors(409, errmsg.WithMessage("Campaign quota is not available"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: ClaimQuota failed")
+		return res, err
+	}
+
+	// the claim may have sold out the quota, which ends the campaign price
+	if err = productRepository.SyncOfferPrices(ctx, tx, []string{req.ProductId}); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ClaimQuota failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// claimed returns the claim the order already holds, a released claim is not
+// taken again.
+func (r *campaignRepository) claimed(ctx context.Context, tx *sqlx.Tx, req *entity.ClaimQuotaRequest) (entity.ClaimQuotaResponse, error) {
+	var (
+		res   entity.ClaimQuotaResponse
+		claim struct {
+			entity.ClaimQuotaResponse
+			Released bool `db:"released"`
+		}
+	)
+
+	query := `
+		SELECT
+			cc.campaign_id,
+			cc.product_id,
+			cc.order_id,
+			cc.quantity,
+			cp.quota - cp.sold_count AS remaining_quota,
+			cc.released_at IS NOT NULL AS released
+		FROM
+			campaign_claims cc
+		JOIN
+			campaign_products cp ON cp.campaign_id = cc.campaign_id AND cp.product_id = cc.product_id
+		WHERE
+			cc.order_id = $1
+			AND cc.campaign_id = $2
+			AND cc.product_id = $3
+	`
+
+	err := tx.QueryRowxContext(ctx, query, req.OrderId, req.CampaignId, req.ProductId).StructScan(&claim)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ClaimQuota failed to read the claim")
+		return res, err
+	}
+
+	if claim.Released {
+		log.Warn().Any("payload", req).Msg("repository: Campaign claim was released")
+		return res, errmsg.NewCostumErrors(409, errmsg.WithMessage("Campaign claim has been released"))
+	}
+
+	log.Debug().Any("payload", req).Msg("repository: Campaign quota is already claimed")
+	return claim.ClaimQuotaResponse, nil
+}
+
+func (r *campaignRepository) ReleaseClaim(ctx context.Context, req *entity.ReleaseClaimRequest) (entity.ClaimQuotaResponse, error) {
+	var (
+		res = entity.ClaimQuotaResponse{CampaignId: req.CampaignId, ProductId: req.ProductId, OrderId: req.OrderId}
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReleaseClaim failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	released, err := ReleaseClaims(ctx, tx, req.OrderId, req.CampaignId, []string{req.ProductId})
+	if err != nil {
+		return res, err
+	}
+
+	if len(released) > 0 {
+		res = released[0]
+	} else {
+		// releasing twice releases nothing the second time
+		query := `
+			SELECT
+				cp.quota - cp.sold_count AS remaining_quota
+			FROM
+				campaign_claims cc
+			JOIN
+				campaign_products cp ON cp.campaign_id = cc.campaign_id AND cp.product_id = cc.product_id
+			WHERE
+				cc.order_id = $1
+				AND cc.campaign_id = $2
+				AND cc.product_id = $3
+		`
+
+		err = tx.GetContext(ctx, &res.RemainingQuota, query, req.OrderId, req.CampaignId, req.ProductId)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Warn().Any("payload", req).Msg("repository: Campaign claim not found")
+				return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Campaign claim not found"))
+			}
+			log.Error().Err(err).Any("payload", req).Msg("repository: ReleaseClaim failed")
+			return res, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReleaseClaim failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// ReleaseClaims gives the quota the order still holds back to the campaigns,
+// of the given campaign and products only when they are set. It must run in
+// the transaction of the release that triggers it.
+func ReleaseClaims(ctx context.Context, tx sqlx.ExtContext, orderId, campaignId string, productIds []string) ([]entity.ClaimQuotaResponse, error) {
+	var (
+		res = make([]entity.ClaimQuotaResponse, 0)
+	)
+
+	query := `
+		WITH released AS (
+			UPDATE
+				campaign_claims
+			SET
+				released_at = NOW()
+			WHERE
+				order_id = $1
+				AND released_at IS NULL
+				AND (NULLIF($2, '') IS NULL OR campaign_id = NULLIF($2, '')::uuid)
+				AND (COALESCE(CARDINALITY($3::uuid[]), 0) = 0 OR product_id = ANY($3::uuid[]))
+			RETURNING
+				campaign_id,
+				product_id,
+				order_id,
+				quantity
+		)
+		UPDATE
+			campaign_products cp
+		SET
+			sold_count = GREATEST(cp.sold_count - released.quantity, 0),
+			updated_at = NOW()
+		FROM
+			released
+		WHERE
+			cp.campaign_id = released.campaign_id
+			AND cp.product_id = released.product_id
+		RETURNING
+			released.campaign_id,
+			released.product_id,
+			released.order_id,
+			released.quantity,
+			cp.quota - cp.sold_count AS remaining_quota
+	`
+
+	err := sqlx.SelectContext(ctx, tx, &res, query, orderId, campaignId, pq.Array(productIds))
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderId).Msg("repository: ReleaseClaims failed")
+		return res, err
+	}
+
+	released := make([]string, 0, len(res))
+	for _, claim := range res {
+		released = append(released, claim.ProductId)
+	}
+
+	if len(released) > 0 {
+		if err = productRepository.SyncOfferPrices(ctx, tx, released); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+func (r *campaignRepository) GetLiveCampaignProducts(ctx context.Context) ([]entity.LiveCampaignProduct, error) {
+	var (
+		res = make([]entity.LiveCampaignProduct, 0)
+	)
+
+	query := `
+		SELECT
+			c.id AS campaign_id,
+			c.name AS campaign_name,
+			c.ends_at,
+			p.id AS product_id,
+			p.shop_id,
+			p.name,
+			p.image_url,
+			p.price,
+			cp.campaign_price,
+			cp.quota,
+			cp.quota - cp.sold_count AS remaining_quota
+		FROM
+			campaigns c
+		JOIN
+			campaign_products cp ON cp.campaign_id = c.id
+		JOIN
+			products p ON p.id = cp.product_id
+		WHERE
+			c.deleted_at IS NULL
+			AND c.starts_at <= NOW()
+			AND c.ends_at > NOW()
+			AND p.deleted_at IS NULL
+			AND ` + productRepository.Published("p") + `
+		ORDER BY c.ends_at ASC, remaining_quota DESC, p.name ASC
+	`
+
+	err := r.db.SelectContext(ctx, &res, query)
+	if err != nil {
+		log.Error().Err(err).Msg("repository: GetLiveCampaignProducts failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *campaignRepository) GetCampaignPeriod(ctx context.Context, campaignId string) (time.Time, time.Time, error) {
+	var (
+		period struct {
+			StartsAt time.Time `db:"starts_at"`
+			EndsAt   time.Time `db:"ends_at"`
+		}
+	)
+
+	query := `SELECT starts_at, ends_at FROM campaigns WHERE id = $1 AND deleted_at IS NULL`
+
+	err := r.db.GetContext(ctx, &period, query, campaignId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("campaign_id", campaignId).Msg("repository: Campaign not found")
+			return period.StartsAt, period.EndsAt, errmsg.NewCostumErrors(404, errmsg.WithMessage("Campaign not found"))
+		}
+		log.Error().Err(err).Str("campaign_id", campaignId).Msg("repository: GetCampaignPeriod failed")
+		return period.StartsAt, period.EndsAt, err
+	}
+
+	return period.StartsAt, period.EndsAt, nil
+}
+
+// GetOwnedProductStock returns the price and stock of a product owned by the
+// user, products owned by someone else are reported as not found.
+func (r *campaignRepository) GetOwnedProductStock(ctx context.Context, userId, productId string) (decimal.Decimal, int, error) {
+	var (
+		product struct {
+			Price decimal.Decimal `db:"price"`
+			Stock int             `db:"stock"`
+		}
+		payload = struct {
+			UserId    string `json:"user_id"`
+			ProductId string `json:"product_id"`
+		}{userId, productId}
+	)
+
+	query := `
+		SELECT
+			products.price,
+			products.stock
+		FROM
+			products
+		JOIN
+			shops ON products.shop_id = shops.id
+		WHERE
+			shops.user_id = $1
+			AND products.id = $2
+			AND products.deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &product, query, userId, productId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", payload).Msg("repository: Product not found")
+			return product.Price, product.Stock, errmsg.NewCostumErrors(404, errmsg.WithMessage("Product not found"))
+		}
+		log.Error().Err(err).Any("payload", payload).Msg("repository: GetOwnedProductStock failed")
+		return product.Price, product.Stock, err
+	}
+
+	return product.Price, product.Stock, nil
+}
+
+func (r *campaignRepository) IsProductInOverlappingCampaign(ctx context.Context, productId string, startsAt, endsAt time.Time) (bool, error) {
+	var overlapping bool
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM
+					campaign_products cp
+				JOIN
+					campaigns c ON c.id = cp.campaign_id
+				WHERE
+					cp.product_id = $1
+					AND c.deleted_at IS NULL
+					AND c.starts_at < $3
+					AND c.ends_at > $2
+			)
+	`
+
+	err := r.db.GetContext(ctx, &overlapping, query, productId, startsAt.UTC(), endsAt.UTC())
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: IsProductInOverlappingCampaign failed")
+		return overlapping, err
+	}
+
+	return overlapping, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/campaign/entity"
+	"sync"
+	"time"
+)
+
+// liveCacheTTL bounds how stale remaining_quota can be on the live listing.
+// Claims always go to the database, so staleness only affects what is shown.
+const liveCacheTTL = 2 * time.Second
+
+// liveCache serves the live campaign listing from memory. When the entry
+// expires only one caller reloads it, every other caller waits for that load
+// and shares its result, so a sale starting doesn't turn into one query per
+// request.
+type liveCache struct {
+	mu        sync.RWMutex
+	loadMu    sync.Mutex
+	items     []entity.LiveCampaignProduct
+	cachedAt  time.Time
+	expiresAt time.Time
+}
+
+func newLiveCache() *liveCache {
+	return &liveCache{}
+}
+
+func (c *liveCache) get() ([]entity.LiveCampaignProduct, time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.items == nil || time.Now().After(c.expiresAt) {
+		return nil, time.Time{}, false
+	}
+
+	return c.items, c.cachedAt, true
+}
+
+func (c *liveCache) getOrLoad(ctx context.Context, load func(context.Context) ([]entity.LiveCampaignProduct, error)) ([]entity.LiveCampaignProduct, time.Time, error) {
+	if items, cachedAt, ok := c.get(); ok {
+		return items, cachedAt, nil
+	}
+
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	// another caller may have loaded it while we were waiting
+	if items, cachedAt, ok := c.get(); ok {
+		return items, cachedAt, nil
+	}
+
+	// the result is shared, so one caller going away must not fail the others
+	items, err := load(context.WithoutCancel(ctx))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	c.items = items
+	c.cachedAt = now
+	c.expiresAt = now.Add(liveCacheTTL)
+	c.mu.Unlock()
+
+	return items, now, nil
+}
+
+func (c *liveCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = nil
+}
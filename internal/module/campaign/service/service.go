@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/campaign/entity"
+	"product-service/internal/module/campaign/ports"
+	productService "product-service/internal/module/product/service"
+	"product-service/pkg/cache"
+	"product-service/pkg/errmsg"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type campaignService struct {
+	repo  ports.CampaignRepository
+	cache cache.Cache
+	live  *liveCache
+}
+
+func NewCampaignService(r ports.CampaignRepository, c cache.Cache) ports.CampaignService {
+	return &campaignService{
+		repo:  r,
+		cache: c,
+		live:  newLiveCache(),
+	}
+}
+
+func (s *campaignService) CreateCampaign(ctx context.Context, req *entity.CreateCampaignRequest) (entity.CampaignResponse, error) {
+	if !req.EndsAt.After(time.Now()) {
+		return entity.CampaignResponse{}, errmsg.NewCostumErrors(422, errmsg.WithErrors("ends_at", "ends at must be in the future."))
+	}
+
+	res, err := s.repo.CreateCampaign(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	s.live.invalidate()
+	return res, nil
+}
+
+func (s *campaignService) DeleteCampaign(ctx context.Context, req *entity.DeleteCampaignRequest) error {
+	productIds, err := s.repo.DeleteCampaign(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	s.live.invalidate()
+	if len(productIds) > 0 {
+		productService.InvalidateProducts(ctx, s.cache, productIds...)
+	}
+	return nil
+}
+
+func (s *campaignService) GetCampaigns(ctx context.Context, req *entity.GetCampaignsRequest) (entity.GetCampaignsResponse, error) {
+	return s.repo.GetCampaigns(ctx, req)
+}
+
+func (s *campaignService) EnrolProduct(ctx context.Context, req *entity.EnrolProductRequest) (entity.CampaignProductResponse, error) {
+	var res entity.CampaignProductResponse
+
+	startsAt, endsAt, err := s.repo.GetCampaignPeriod(ctx, req.CampaignId)
+	if err != nil {
+		return res, err
+	}
+
+	// prices and quotas are announced before the sale, they can't change mid-sale
+	if !startsAt.After(time.Now()) {
+		log.Warn().Any("payload", req).Msg("service: Campaign already started")
+		return res, errmsg.NewCostumErrors(422, errmsg.WithMessage("Campaign already started"))
+	}
+
+	price, stock, err := s.repo.GetOwnedProductStock(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if !req.CampaignPrice.LessThan(price) {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("campaign_price", "campaign price must be less than the product price."))
+	}
+
+	if req.Quota > stock {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("quota", "quota can't exceed the product stock."))
+	}
+
+	overlapping, err := s.repo.IsProductInOverlappingCampaign(ctx, req.ProductId, startsAt, endsAt)
+	if err != nil {
+		return res, err
+	}
+
+	if overlapping {
+		log.Warn().Any("payload", req).Msg("service: Product enrolled in an overlapping campaign")
+		return res, errmsg.NewCostumErrors(409, errmsg.WithErrors("product_id", "product is already enrolled in a campaign in this period."))
+	}
+
+	res, err = s.repo.EnrolProduct(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	productService.InvalidateProducts(ctx, s.cache, req.ProductId)
+	return res, nil
+}
+
+// ClaimQuota and ReleaseClaim move the quota left, a sold out campaign
+// stops giving the product its price.
+func (s *campaignService) ClaimQuota(ctx context.Context, req *entity.ClaimQuotaRequest) (entity.ClaimQuotaResponse, error) {
+	res, err := s.repo.ClaimQuota(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	productService.InvalidateProducts(ctx, s.cache, req.ProductId)
+	return res, nil
+}
+
+func (s *campaignService) ReleaseClaim(ctx context.Context, req *entity.ReleaseClaimRequest) (entity.ClaimQuotaResponse, error) {
+	res, err := s.repo.ReleaseClaim(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	productService.InvalidateProducts(ctx, s.cache, req.ProductId)
+	return res, nil
+}
+
+func (s *campaignService) GetLiveCampaignProducts(ctx context.Context) (entity.GetLiveCampaignProductsResponse, error) {
+	var res entity.GetLiveCampaignProductsResponse
+
+	items, cachedAt, err := s.live.getOrLoad(ctx, s.repo.GetLiveCampaignProducts)
+	if err != nil {
+		return res, err
+	}
+
+	res.Items = items
+	res.CachedAt = cachedAt
+	return res, nil
+}
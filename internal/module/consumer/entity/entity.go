@@ -0,0 +1,31 @@
+package entity
+
+// Events published by the user and order services.
+const (
+	SubjectUserDeleted     = "user.deleted"
+	SubjectUserRoleChanged = "user.role_changed"
+	SubjectOrderCancelled  = "order.cancelled"
+)
+
+// InboxMessage is an inbound event as the inbox records it.
+type InboxMessage struct {
+	Id      string
+	Subject string
+	Payload []byte
+}
+
+type UserDeletedEvent struct {
+	UserId string `json:"user_id" validate:"required,uuid"`
+}
+
+type UserRoleChangedEvent struct {
+	UserId string `json:"user_id" validate:"required,uuid"`
+	Role   string `json:"role" validate:"required,max=50"`
+}
+
+// OrderCancelledEvent releases what the order reserved and claimed. The
+// quantities come from the recorded reservations and campaign claims, not
+// from the event, so it releases nothing more than the order took.
+type OrderCancelledEvent struct {
+	OrderId string `json:"order_id" validate:"required,max=255"`
+}
@@ -0,0 +1,109 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"product-service/internal/adapter"
+	"product-service/internal/module/consumer/entity"
+	"product-service/internal/module/consumer/ports"
+	"product-service/internal/module/consumer/repository"
+	"product-service/internal/module/consumer/service"
+	"product-service/pkg/broker"
+
+	"github.com/rs/zerolog/log"
+)
+
+type consumerSubscriber struct {
+	service ports.ConsumerService
+}
+
+func NewConsumerSubscriber(maxAttempts int) *consumerSubscriber {
+	repo := repository.NewConsumerRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewConsumerService(repo, adapter.Adapters.Cache, maxAttempts)
+
+	return &consumerSubscriber{
+		service: service,
+	}
+}
+
+// Subscribe starts consuming every handled subject until ctx is cancelled.
+func (s *consumerSubscriber) Subscribe(ctx context.Context) error {
+	handlers := map[string]broker.Handler{
+		entity.SubjectUserDeleted:     s.userDeleted,
+		entity.SubjectUserRoleChanged: s.userRoleChanged,
+		entity.SubjectOrderCancelled:  s.orderCancelled,
+	}
+
+	for subject, handler := range handlers {
+		if err := adapter.Adapters.Broker.Subscribe(ctx, subject, handler); err != nil {
+			log.Error().Err(err).Str("subject", subject).Msg("subscriber: Failed to subscribe")
+			return err
+		}
+		log.Info().Str("subject", subject).Msg("subscriber: Subscribed")
+	}
+
+	return nil
+}
+
+func (s *consumerSubscriber) userDeleted(ctx context.Context, msg broker.Message) error {
+	var (
+		req   = &entity.UserDeletedEvent{}
+		inbox = inboxMessage(msg)
+	)
+
+	if err := decode(msg, req); err != nil {
+		return s.service.Reject(ctx, inbox, err)
+	}
+
+	return s.service.DeleteUser(ctx, inbox, req)
+}
+
+func (s *consumerSubscriber) userRoleChanged(ctx context.Context, msg broker.Message) error {
+	var (
+		req   = &entity.UserRoleChangedEvent{}
+		inbox = inboxMessage(msg)
+	)
+
+	if err := decode(msg, req); err != nil {
+		return s.service.Reject(ctx, inbox, err)
+	}
+
+	return s.service.ChangeUserRole(ctx, inbox, req)
+}
+
+func (s *consumerSubscriber) orderCancelled(ctx context.Context, msg broker.Message) error {
+	var (
+		req   = &entity.OrderCancelledEvent{}
+		inbox = inboxMessage(msg)
+	)
+
+	if err := decode(msg, req); err != nil {
+		return s.service.Reject(ctx, inbox, err)
+	}
+
+	return s.service.ReleaseOrderStock(ctx, inbox, req)
+}
+
+// decode parses and validates the payload, a payload failing here fails on
+// every delivery.
+func decode(msg broker.Message, req any) error {
+	if err := json.Unmarshal(msg.Payload, req); err != nil {
+		log.Warn().Err(err).Str("message_id", msg.Id).Str("subject", msg.Subject).Msg("subscriber: Failed to parse payload")
+		return err
+	}
+
+	if err := adapter.Adapters.Validator.Validate(req); err != nil {
+		log.Warn().Err(err).Str("message_id", msg.Id).Any("payload", req).Msg("subscriber: Invalid payload")
+		return err
+	}
+
+	return nil
+}
+
+func inboxMessage(msg broker.Message) entity.InboxMessage {
+	return entity.InboxMessage{
+		Id:      msg.Id,
+		Subject: msg.Subject,
+		Payload: msg.Payload,
+	}
+}
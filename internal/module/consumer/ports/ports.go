@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/consumer/entity"
+)
+
+type ConsumerService interface {
+	DeleteUser(ctx context.Context, msg entity.InboxMessage, req *entity.UserDeletedEvent) error
+	ChangeUserRole(ctx context.Context, msg entity.InboxMessage, req *entity.UserRoleChangedEvent) error
+	ReleaseOrderStock(ctx context.Context, msg entity.InboxMessage, req *entity.OrderCancelledEvent) error
+
+	// Reject dead-letters an event that can never be handled, such as one
+	// with a malformed payload.
+	Reject(ctx context.Context, msg entity.InboxMessage, cause error) error
+}
+
+type ConsumerRepository interface {
+	DeleteUser(ctx context.Context, msg entity.InboxMessage, userId string) ([]string, error)
+	ChangeUserRole(ctx context.Context, msg entity.InboxMessage, userId, role string) error
+	ReleaseStock(ctx context.Context, msg entity.InboxMessage, orderId string) ([]string, error)
+
+	RecordFailure(ctx context.Context, msg entity.InboxMessage, cause error, maxAttempts int) (bool, error)
+}
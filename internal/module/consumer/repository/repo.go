@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	campaignRepository "product-service/internal/module/campaign/repository"
+	"product-service/internal/module/consumer/entity"
+	"product-service/internal/module/consumer/ports"
+	outboxEntity "product-service/internal/module/outbox/entity"
+	outboxRepository "product-service/internal/module/outbox/repository"
+	productEntity "product-service/internal/module/product/entity"
+	productRepository "product-service/internal/module/product/repository"
+	shopEntity "product-service/internal/module/shop/entity"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type consumerRepository struct {
+	db *sqlx.DB
+}
+
+func NewConsumerRepository(db *sqlx.DB) ports.ConsumerRepository {
+	return &consumerRepository{
+		db: db,
+	}
+}
+
+// DeleteUser soft-deletes the user with their shops and products, and
+// announces the deleted shops and products. It returns the ids of the deleted
+// products.
+func (r *consumerRepository) DeleteUser(ctx context.Context, msg entity.InboxMessage, userId string) ([]string, error) {
+	productIds := make([]string, 0)
+
+	err := r.process(ctx, msg, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, `UPDATE users SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, userId)
+		if err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repository: DeleteUser failed to delete user")
+			return err
+		}
+
+		products := make([]productEntity.ProductDeletedEvent, 0)
+
+		query := `
+			UPDATE
+				products
+			SET
+				deleted_at = NOW()
+			WHERE
+				shop_id IN (SELECT id FROM shops WHERE user_id = $1)
+				AND deleted_at IS NULL
+			RETURNING
+				id AS product_id, shop_id, deleted_at
+		`
+
+		if err = tx.SelectContext(ctx, &products, query, userId); err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repository: DeleteUser failed to delete products")
+			return err
+		}
+
+		for _, product := range products {
+			productIds = append(productIds, product.ProductId)
+
+			err = outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+				AggregateType: outboxEntity.AggregateProduct,
+				AggregateId:   product.ProductId,
+				EventType:     outboxEntity.EventProductDeleted,
+				ShopId:        product.ShopId,
+				Payload:       product,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		shops := make([]shopEntity.ShopEvent, 0)
+
+		query = `
+			UPDATE
+				shops
+			SET
+				deleted_at = NOW()
+			WHERE
+				user_id = $1
+				AND deleted_at IS NULL
+			RETURNING
+				id AS shop_id, user_id, name
+		`
+
+		if err = tx.SelectContext(ctx, &shops, query, userId); err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repository: DeleteUser failed to delete shops")
+			return err
+		}
+
+		for _, shop := range shops {
+			err = outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+				AggregateType: outboxEntity.AggregateShop,
+				AggregateId:   shop.ShopId,
+				EventType:     outboxEntity.EventShopDeleted,
+				Payload:       shop,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return productIds, err
+}
+
+func (r *consumerRepository) ChangeUserRole(ctx context.Context, msg entity.InboxMessage, userId, role string) error {
+	return r.process(ctx, msg, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, `UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1`, userId, role)
+		if err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repository: ChangeUserRole failed")
+			return err
+		}
+
+		return nil
+	})
+}
+
+// ReleaseStock puts what a cancelled order reserved back in stock and gives
+// the campaigns back the quota it claimed, through the same release as the
+// stock and campaign endpoints, so an order released there first releases
+// nothing here. It returns the ids of the products released.
+func (r *consumerRepository) ReleaseStock(ctx context.Context, msg entity.InboxMessage, orderId string) ([]string, error) {
+	productIds := make([]string, 0)
+
+	err := r.process(ctx, msg, func(tx *sqlx.Tx) error {
+		released, err := productRepository.ReleaseOrderStock(ctx, tx, orderId, nil)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range released {
+			productIds = append(productIds, item.ProductId)
+		}
+
+		claims, err := campaignRepository.ReleaseClaims(ctx, tx, orderId, "", nil)
+		if err != nil {
+			return err
+		}
+
+		for _, claim := range claims {
+			productIds = append(productIds, claim.ProductId)
+		}
+
+		return nil
+	})
+
+	return productIds, err
+}
+
+// RecordFailure counts a failed attempt at the message and dead-letters it
+// once maxAttempts is reached, reporting whether it did.
+func (r *consumerRepository) RecordFailure(ctx context.Context, msg entity.InboxMessage, cause error, maxAttempts int) (bool, error) {
+	var dead bool
+
+	query := `
+		INSERT INTO
+			inbox (message_id, subject, payload, attempts, last_error, dead_lettered_at)
+		VALUES
+			($1, $2, $3, 1, $4, CASE WHEN 1 >= $5 THEN NOW() END)
+		ON CONFLICT (message_id) DO UPDATE
+		SET
+			attempts = inbox.attempts + 1,
+			last_error = EXCLUDED.last_error,
+			dead_lettered_at = CASE WHEN inbox.attempts + 1 >= $5 THEN NOW() END
+		RETURNING
+			dead_lettered_at IS NOT NULL
+	`
+
+	err := r.db.GetContext(ctx, &dead, query, msg.Id, msg.Subject, string(msg.Payload), cause.Error(), maxAttempts)
+	if err != nil {
+		log.Error().Err(err).Str("message_id", msg.Id).Msg("repository: RecordFailure failed")
+		return false, err
+	}
+
+	return dead, nil
+}
+
+// process applies the message in one transaction with its inbox entry, so a
+// message is applied at most once however often it is delivered. Concurrent
+// deliveries of a message wait on the inbox row.
+func (r *consumerRepository) process(ctx context.Context, msg entity.InboxMessage, apply func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Str("message_id", msg.Id).Msg("repository: Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO
+			inbox (message_id, subject, payload)
+		VALUES
+			($1, $2, $3)
+		ON CONFLICT (message_id) DO NOTHING
+	`
+
+	if _, err = tx.ExecContext(ctx, query, msg.Id, msg.Subject, string(msg.Payload)); err != nil {
+		log.Error().Err(err).Str("message_id", msg.Id).Msg("repository: Failed to record inbox message")
+		return err
+	}
+
+	var done bool
+
+	query = `SELECT processed_at IS NOT NULL OR dead_lettered_at IS NOT NULL FROM inbox WHERE message_id = $1 FOR UPDATE`
+
+	if err = tx.GetContext(ctx, &done, query, msg.Id); err != nil {
+		log.Error().Err(err).Str("message_id", msg.Id).Msg("repository: Failed to lock inbox message")
+		return err
+	}
+
+	if done {
+		log.Debug().Str("message_id", msg.Id).Str("subject", msg.Subject).Msg("repository: Skipped a handled message")
+		return nil
+	}
+
+	if err = apply(tx); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE inbox SET processed_at = NOW(), last_error = NULL WHERE message_id = $1`, msg.Id); err != nil {
+		log.Error().Err(err).Str("message_id", msg.Id).Msg("repository: Failed to mark inbox message processed")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Str("message_id", msg.Id).Msg("repository: Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
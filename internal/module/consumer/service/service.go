@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/consumer/entity"
+	"product-service/internal/module/consumer/ports"
+	productService "product-service/internal/module/product/service"
+	"product-service/pkg/cache"
+
+	"github.com/rs/zerolog/log"
+)
+
+type consumerService struct {
+	repo        ports.ConsumerRepository
+	cache       cache.Cache
+	maxAttempts int
+}
+
+func NewConsumerService(r ports.ConsumerRepository, c cache.Cache, maxAttempts int) ports.ConsumerService {
+	return &consumerService{
+		repo:        r,
+		cache:       c,
+		maxAttempts: maxAttempts,
+	}
+}
+
+func (s *consumerService) DeleteUser(ctx context.Context, msg entity.InboxMessage, req *entity.UserDeletedEvent) error {
+	productIds, err := s.repo.DeleteUser(ctx, msg, req.UserId)
+	if err == nil && len(productIds) > 0 {
+		productService.InvalidateProducts(ctx, s.cache, productIds...)
+	}
+
+	return s.settle(ctx, msg, err, s.maxAttempts)
+}
+
+func (s *consumerService) ChangeUserRole(ctx context.Context, msg entity.InboxMessage, req *entity.UserRoleChangedEvent) error {
+	return s.settle(ctx, msg, s.repo.ChangeUserRole(ctx, msg, req.UserId, req.Role), s.maxAttempts)
+}
+
+func (s *consumerService) ReleaseOrderStock(ctx context.Context, msg entity.InboxMessage, req *entity.OrderCancelledEvent) error {
+	productIds, err := s.repo.ReleaseStock(ctx, msg, req.OrderId)
+	if err == nil && len(productIds) > 0 {
+		productService.InvalidateProducts(ctx, s.cache, productIds...)
+	}
+
+	return s.settle(ctx, msg, err, s.maxAttempts)
+}
+
+func (s *consumerService) Reject(ctx context.Context, msg entity.InboxMessage, cause error) error {
+	return s.settle(ctx, msg, cause, 1)
+}
+
+// settle decides what the broker does with the message. A failure is
+// returned for a redelivery until the message runs out of attempts, then it
+// is dead-lettered and acknowledged so it stops blocking the subject.
+func (s *consumerService) settle(ctx context.Context, msg entity.InboxMessage, err error, maxAttempts int) error {
+	if err == nil {
+		return nil
+	}
+
+	dead, ferr := s.repo.RecordFailure(ctx, msg, err, maxAttempts)
+	if ferr != nil {
+		return ferr
+	}
+
+	if dead {
+		log.Error().Err(err).Str("message_id", msg.Id).Str("subject", msg.Subject).Msg("service: Message dead-lettered")
+		return nil
+	}
+
+	log.Warn().Err(err).Str("message_id", msg.Id).Str("subject", msg.Subject).Msg("service: Message failed, waiting for redelivery")
+	return err
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/module/consumer/entity"
+	"product-service/pkg/cache"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeInbox is an inbox row as the repository keeps it.
+type fakeInbox struct {
+	attempts  int
+	processed bool
+	dead      bool
+	lastError string
+}
+
+// fakeRepository keeps the inbox in memory with the rules of the SQL one:
+// a handled message is skipped, a failed one counts an attempt.
+type fakeRepository struct {
+	inbox   map[string]*fakeInbox
+	applied []string
+	fail    error
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{inbox: make(map[string]*fakeInbox)}
+}
+
+func (r *fakeRepository) process(msg entity.InboxMessage) error {
+	if row, ok := r.inbox[msg.Id]; ok && (row.processed || row.dead) {
+		return nil
+	}
+
+	if r.fail != nil {
+		return r.fail
+	}
+
+	r.applied = append(r.applied, msg.Id)
+
+	row, ok := r.inbox[msg.Id]
+	if !ok {
+		row = &fakeInbox{}
+		r.inbox[msg.Id] = row
+	}
+	row.processed = true
+	row.lastError = ""
+
+	return nil
+}
+
+func (r *fakeRepository) DeleteUser(ctx context.Context, msg entity.InboxMessage, userId string) ([]string, error) {
+	return nil, r.process(msg)
+}
+
+func (r *fakeRepository) ChangeUserRole(ctx context.Context, msg entity.InboxMessage, userId, role string) error {
+	return r.process(msg)
+}
+
+func (r *fakeRepository) ReleaseStock(ctx context.Context, msg entity.InboxMessage, orderId string) ([]string, error) {
+	return nil, r.process(msg)
+}
+
+func (r *fakeRepository) RecordFailure(ctx context.Context, msg entity.InboxMessage, cause error, maxAttempts int) (bool, error) {
+	row, ok := r.inbox[msg.Id]
+	if !ok {
+		row = &fakeInbox{}
+		r.inbox[msg.Id] = row
+	}
+
+	row.attempts++
+	row.lastError = cause.Error()
+	row.dead = row.attempts >= maxAttempts
+
+	return row.dead, nil
+}
+
+func message(id string) entity.InboxMessage {
+	return entity.InboxMessage{Id: id, Subject: entity.SubjectUserDeleted, Payload: []byte(`{}`)}
+}
+
+func TestSettleSuccess(t *testing.T) {
+	var (
+		repo = newFakeRepository()
+		s    = NewConsumerService(repo, cache.NewMemory(100), 3)
+		ctx  = context.Background()
+	)
+
+	assert.NoError(t, s.DeleteUser(ctx, message("1"), &entity.UserDeletedEvent{}))
+	assert.Equal(t, []string{"1"}, repo.applied)
+	assert.True(t, repo.inbox["1"].processed)
+	assert.Zero(t, repo.inbox["1"].attempts)
+}
+
+func TestSettleRetriesUntilDeadLettered(t *testing.T) {
+	var (
+		repo = newFakeRepository()
+		s    = NewConsumerService(repo, cache.NewMemory(100), 3)
+		ctx  = context.Background()
+		bad  = errors.New("database is down")
+	)
+	repo.fail = bad
+
+	// failures ask for a redelivery until the last attempt
+	for attempt := 1; attempt < 3; attempt++ {
+		assert.ErrorIs(t, s.ChangeUserRole(ctx, message("1"), &entity.UserRoleChangedEvent{}), bad)
+		assert.Equal(t, attempt, repo.inbox["1"].attempts)
+		assert.False(t, repo.inbox["1"].dead)
+	}
+
+	// the last one is dead-lettered and acknowledged
+	assert.NoError(t, s.ChangeUserRole(ctx, message("1"), &entity.UserRoleChangedEvent{}))
+	assert.Equal(t, 3, repo.inbox["1"].attempts)
+	assert.True(t, repo.inbox["1"].dead)
+	assert.Equal(t, bad.Error(), repo.inbox["1"].lastError)
+	assert.Empty(t, repo.applied)
+}
+
+func TestSettleRecoversBeforeMaxAttempts(t *testing.T) {
+	var (
+		repo = newFakeRepository()
+		s    = NewConsumerService(repo, cache.NewMemory(100), 3)
+		ctx  = context.Background()
+		bad  = errors.New("lock timeout")
+	)
+	repo.fail = bad
+
+	assert.ErrorIs(t, s.DeleteUser(ctx, message("1"), &entity.UserDeletedEvent{}), bad)
+
+	repo.fail = nil
+	assert.NoError(t, s.DeleteUser(ctx, message("1"), &entity.UserDeletedEvent{}))
+	assert.Equal(t, 1, repo.inbox["1"].attempts)
+	assert.True(t, repo.inbox["1"].processed)
+	assert.Empty(t, repo.inbox["1"].lastError)
+}
+
+func TestRejectDeadLettersAtOnce(t *testing.T) {
+	var (
+		repo = newFakeRepository()
+		s    = NewConsumerService(repo, cache.NewMemory(100), 5)
+		ctx  = context.Background()
+	)
+
+	assert.NoError(t, s.Reject(ctx, message("1"), errors.New("malformed payload")))
+	assert.Equal(t, 1, repo.inbox["1"].attempts)
+	assert.True(t, repo.inbox["1"].dead)
+}
+
+func TestInboxSkipsHandledMessages(t *testing.T) {
+	var (
+		repo = newFakeRepository()
+		s    = NewConsumerService(repo, cache.NewMemory(100), 3)
+		ctx  = context.Background()
+	)
+
+	// a redelivery of a processed message is acknowledged without applying it
+	assert.NoError(t, s.DeleteUser(ctx, message("1"), &entity.UserDeletedEvent{}))
+	assert.NoError(t, s.DeleteUser(ctx, message("1"), &entity.UserDeletedEvent{}))
+	assert.Equal(t, []string{"1"}, repo.applied)
+
+	// so is a redelivery of a dead-lettered one
+	assert.NoError(t, s.Reject(ctx, message("2"), errors.New("malformed payload")))
+	assert.NoError(t, s.ReleaseOrderStock(ctx, message("2"), &entity.OrderCancelledEvent{}))
+	assert.Equal(t, []string{"1"}, repo.applied)
+	assert.Equal(t, 1, repo.inbox["2"].attempts)
+}
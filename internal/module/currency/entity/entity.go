@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"product-service/pkg/decimal"
+	"time"
+)
+
+type CurrencyResponse struct {
+	Code  string `json:"code" db:"code"`
+	Name  string `json:"name" db:"name"`
+	Scale int    `json:"scale" db:"scale"`
+}
+
+type GetCurrenciesResponse struct {
+	Items []CurrencyResponse `json:"items"`
+}
+
+type ExchangeRate struct {
+	FromCurrency string       `json:"from_currency" db:"from_currency" validate:"required,iso4217"`
+	ToCurrency   string       `json:"to_currency" db:"to_currency" validate:"required,iso4217,nefield=FromCurrency"`
+	Rate         decimal.Rate `json:"rate" db:"rate" validate:"required,gt=0"`
+}
+
+// UpsertExchangeRatesRequest sets the given rates, pairs that aren't listed
+// keep their current rate.
+type UpsertExchangeRatesRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	Rates []ExchangeRate `json:"rates" validate:"required,min=1,max=500,dive"`
+}
+
+type ExchangeRateResponse struct {
+	FromCurrency string       `json:"from_currency" db:"from_currency"`
+	ToCurrency   string       `json:"to_currency" db:"to_currency"`
+	Rate         decimal.Rate `json:"rate" db:"rate"`
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+type GetExchangeRatesResponse struct {
+	Items []ExchangeRateResponse `json:"items"`
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"product-service/internal/adapter"
+	m "product-service/internal/middleware"
+	"product-service/internal/module/currency/entity"
+	"product-service/internal/module/currency/ports"
+	"product-service/internal/module/currency/repository"
+	"product-service/internal/module/currency/service"
+	"product-service/pkg/errmsg"
+	"product-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type currencyHandler struct {
+	service ports.CurrencyService
+}
+
+func NewCurrencyHandler() *currencyHandler {
+	repo := repository.NewCurrencyRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewCurrencyService(repo, adapter.Adapters.Cache)
+
+	return &currencyHandler{
+		service: service,
+	}
+}
+
+func (h *currencyHandler) Register(router fiber.Router) {
+	adminOnly := m.AuthRole([]string{"admin"})
+
+	router.Get("/currencies", h.getCurrencies)
+	router.Get("/exchange-rates", h.getExchangeRates)
+	router.Put("/exchange-rates", m.AuthQueryParams, m.UserRole, adminOnly, h.upsertExchangeRates)
+}
+
+func (h *currencyHandler) getCurrencies(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	resp, err := h.service.GetCurrencies(ctx)
+	if err != nil {
+		code, errs := errmsg.Errors[any](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *currencyHandler) getExchangeRates(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	resp, err := h.service.GetExchangeRates(ctx)
+	if err != nil {
+		code, errs := errmsg.Errors[any](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *currencyHandler) upsertExchangeRates(c *fiber.Ctx) error {
+	var (
+		req = &entity.UpsertExchangeRatesRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpsertExchangeRates(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
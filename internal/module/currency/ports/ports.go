@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/currency/entity"
+)
+
+type CurrencyService interface {
+	GetCurrencies(ctx context.Context) (entity.GetCurrenciesResponse, error)
+	GetExchangeRates(ctx context.Context) (entity.GetExchangeRatesResponse, error)
+	UpsertExchangeRates(ctx context.Context, req *entity.UpsertExchangeRatesRequest) (entity.GetExchangeRatesResponse, error)
+}
+
+type CurrencyRepository interface {
+	GetCurrencies(ctx context.Context) (entity.GetCurrenciesResponse, error)
+	GetExchangeRates(ctx context.Context) (entity.GetExchangeRatesResponse, error)
+	UpsertExchangeRates(ctx context.Context, rates []entity.ExchangeRate) error
+}
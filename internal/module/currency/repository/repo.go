@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"product-service/internal/module/currency/entity"
+	"product-service/internal/module/currency/ports"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type currencyRepository struct {
+	db *sqlx.DB
+}
+
+func NewCurrencyRepository(db *sqlx.DB) ports.CurrencyRepository {
+	return &currencyRepository{
+		db: db,
+	}
+}
+
+func (r *currencyRepository) GetCurrencies(ctx context.Context) (entity.GetCurrenciesResponse, error) {
+	var (
+		res entity.GetCurrenciesResponse
+	)
+	res.Items = make([]entity.CurrencyResponse, 0)
+
+	query := `SELECT code, name, scale FROM currencies ORDER BY code ASC`
+
+	err := r.db.SelectContext(ctx, &res.Items, query)
+	if err != nil {
+		log.Error().Err(err).Msg("repository: GetCurrencies failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *currencyRepository) GetExchangeRates(ctx context.Context) (entity.GetExchangeRatesResponse, error) {
+	var (
+		res entity.GetExchangeRatesResponse
+	)
+	res.Items = make([]entity.ExchangeRateResponse, 0)
+
+	query := `
+		SELECT
+			from_currency,
+			to_currency,
+			rate,
+			updated_at
+		FROM
+			exchange_rates
+		ORDER BY from_currency ASC, to_currency ASC
+	`
+
+	err := r.db.SelectContext(ctx, &res.Items, query)
+	if err != nil {
+		log.Error().Err(err).Msg("repository: GetExchangeRates failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// UpsertExchangeRates writes all rates in one transaction, a bad pair in the
+// batch leaves every rate as it was.
+func (r *currencyRepository) UpsertExchangeRates(ctx context.Context, rates []entity.ExchangeRate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository: UpsertExchangeRates failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO
+			exchange_rates (from_currency, to_currency, rate)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (from_currency, to_currency) DO UPDATE
+		SET
+			rate = EXCLUDED.rate,
+			updated_at = NOW()
+	`
+
+	for _, rate := range rates {
+		_, err = tx.ExecContext(ctx, query, rate.FromCurrency, rate.ToCurrency, rate.Rate)
+		if err != nil {
+			log.Error().Err(err).Any("payload", rate).Msg("repository: UpsertExchangeRates failed")
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository: UpsertExchangeRates failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/currency/entity"
+	"product-service/internal/module/currency/ports"
+	productService "product-service/internal/module/product/service"
+	"product-service/pkg/cache"
+)
+
+type currencyService struct {
+	repo  ports.CurrencyRepository
+	cache cache.Cache
+}
+
+func NewCurrencyService(r ports.CurrencyRepository, c cache.Cache) ports.CurrencyService {
+	return &currencyService{
+		repo:  r,
+		cache: c,
+	}
+}
+
+func (s *currencyService) GetCurrencies(ctx context.Context) (entity.GetCurrenciesResponse, error) {
+	return s.repo.GetCurrencies(ctx)
+}
+
+func (s *currencyService) GetExchangeRates(ctx context.Context) (entity.GetExchangeRatesResponse, error) {
+	return s.repo.GetExchangeRates(ctx)
+}
+
+func (s *currencyService) UpsertExchangeRates(ctx context.Context, req *entity.UpsertExchangeRatesRequest) (entity.GetExchangeRatesResponse, error) {
+	if err := s.repo.UpsertExchangeRates(ctx, req.Rates); err != nil {
+		return entity.GetExchangeRatesResponse{}, err
+	}
+
+	// converted prices of every cached product and listing changed
+	productService.InvalidateRates(ctx, s.cache)
+
+	return s.repo.GetExchangeRates(ctx)
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"product-service/pkg/decimal"
+	"time"
+)
+
+const (
+	TypePercentage = "percentage"
+	TypeFixed      = "fixed"
+)
+
+type CreateDiscountRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId string          `params:"id" validate:"required,uuid"`
+	Type      string          `json:"type" validate:"required,oneof=percentage fixed"`
+	Value     decimal.Decimal `json:"value" validate:"required,gt=0"`
+	StartsAt  time.Time       `json:"starts_at" validate:"required"`
+	EndsAt    time.Time       `json:"ends_at" validate:"required,gtfield=StartsAt"`
+	StockCap  *int            `json:"stock_cap" validate:"omitempty,min=1"`
+}
+
+type DeleteDiscountRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId  string `params:"id" validate:"required,uuid"`
+	DiscountId string `params:"discount_id" validate:"required,uuid"`
+}
+
+type GetDiscountsRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId string `params:"id" validate:"required,uuid"`
+}
+
+type DiscountResponse struct {
+	Id        string          `json:"id" db:"id"`
+	ProductId string          `json:"product_id" db:"product_id"`
+	Type      string          `json:"type" db:"type"`
+	Value     decimal.Decimal `json:"value" db:"value"`
+	StartsAt  time.Time       `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time       `json:"ends_at" db:"ends_at"`
+	StockCap  *int            `json:"stock_cap" db:"stock_cap"`
+	SoldCount int             `json:"sold_count" db:"sold_count"`
+	IsActive  bool            `json:"is_active" db:"is_active"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+type GetDiscountsResponse struct {
+	Items []DiscountResponse `json:"items"`
+}
@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"product-service/internal/adapter"
+	m "product-service/internal/middleware"
+	"product-service/internal/module/discount/entity"
+	"product-service/internal/module/discount/ports"
+	"product-service/internal/module/discount/repository"
+	"product-service/internal/module/discount/service"
+	"product-service/pkg/errmsg"
+	"product-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type discountHandler struct {
+	service ports.DiscountService
+}
+
+func NewDiscountHandler() *discountHandler {
+	repo := repository.NewDiscountRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewDiscountService(repo, adapter.Adapters.Cache)
+
+	return &discountHandler{
+		service: service,
+	}
+}
+
+func (h *discountHandler) Register(router fiber.Router) {
+	router.Get("/products/:id/discounts", m.AuthQueryParams, h.getDiscounts)
+	router.Post("/products/:id/discounts", m.AuthQueryParams, h.createDiscount)
+	router.Delete("/products/:id/discounts/:discount_id", m.AuthQueryParams, h.deleteDiscount)
+}
+
+func (h *discountHandler) createDiscount(c *fiber.Ctx) error {
+	var (
+		req = &entity.CreateDiscountRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ProductId = c.Params("id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateDiscount(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *discountHandler) deleteDiscount(c *fiber.Ctx) error {
+	var (
+		req = &entity.DeleteDiscountRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ProductId = c.Params("id")
+	req.DiscountId = c.Params("discount_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.DeleteDiscount(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *discountHandler) getDiscounts(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetDiscountsRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ProductId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetDiscounts(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
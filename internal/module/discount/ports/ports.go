@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/discount/entity"
+	"product-service/pkg/decimal"
+	"time"
+)
+
+type DiscountService interface {
+	CreateDiscount(ctx context.Context, req *entity.CreateDiscountRequest) (entity.DiscountResponse, error)
+	DeleteDiscount(ctx context.Context, req *entity.DeleteDiscountRequest) error
+	GetDiscounts(ctx context.Context, req *entity.GetDiscountsRequest) (entity.GetDiscountsResponse, error)
+}
+
+type DiscountRepository interface {
+	CreateDiscount(ctx context.Context, req *entity.CreateDiscountRequest) (entity.DiscountResponse, error)
+	DeleteDiscount(ctx context.Context, req *entity.DeleteDiscountRequest) error
+	GetDiscounts(ctx context.Context, req *entity.GetDiscountsRequest) (entity.GetDiscountsResponse, error)
+
+	IsProductOwner(ctx context.Context, userId, productId string) (bool, error)
+	GetProductPrice(ctx context.Context, productId string) (decimal.Decimal, error)
+	IsPeriodOverlapping(ctx context.Context, productId string, startsAt, endsAt time.Time) (bool, error)
+}
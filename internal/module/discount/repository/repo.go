@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"product-service/internal/module/discount/entity"
+	"product-service/internal/module/discount/ports"
+	productRepository "product-service/internal/module/product/repository"
+	"product-service/pkg/decimal"
+	"product-service/pkg/errmsg"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type discountRepository struct {
+	db *sqlx.DB
+}
+
+func NewDiscountRepository(db *sqlx.DB) ports.DiscountRepository {
+	return &discountRepository{
+		db: db,
+	}
+}
+
+func (r *discountRepository) CreateDiscount(ctx context.Context, req *entity.CreateDiscountRequest) (entity.DiscountResponse, error) {
+	var (
+		res entity.DiscountResponse
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateDiscount failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO
+			product_discounts (product_id, type, value, starts_at, ends_at, stock_cap)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING
+			id,
+			product_id,
+			type,
+			value,
+			starts_at,
+			ends_at,
+			stock_cap,
+			sold_count,
+			(starts_at <= NOW() AND ends_at > NOW()) AS is_active,
+			created_at,
+			updated_at
+	`
+
+	err = tx.QueryRowxContext(ctx, query,
+		req.ProductId,
+		req.Type,
+		req.Value,
+		req.StartsAt.UTC(),
+		req.EndsAt.UTC(),
+		req.StockCap,
+	).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateDiscount failed")
+		return res, err
+	}
+
+	if err = productRepository.SyncOfferPrices(ctx, tx, []string{req.ProductId}); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateDiscount failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *discountRepository) DeleteDiscount(ctx context.Context, req *entity.DeleteDiscountRequest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteDiscount failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE
+			product_discounts
+		SET
+			deleted_at = NOW()
+		WHERE
+			id = $1
+			AND product_id = $2
+			AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, req.DiscountId, req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteDiscount failed")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteDiscount failed")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("payload", req).Msg("repository: Discount not found")
+		return errmsg.NewCostumErrors(404, errmsg.WithMessage("Discount not found"))
+	}
+
+	if err = productRepository.SyncOfferPrices(ctx, tx, []string{req.ProductId}); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteDiscount failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *discountRepository) GetDiscounts(ctx context.Context, req *entity.GetDiscountsRequest) (entity.GetDiscountsResponse, error) {
+	var (
+		res entity.GetDiscountsResponse
+	)
+	res.Items = make([]entity.DiscountResponse, 0)
+
+	query := `
+		SELECT
+			id,
+			product_id,
+			type,
+			value,
+			starts_at,
+			ends_at,
+			stock_cap,
+			sold_count,
+			(
+				starts_at <= NOW()
+				AND ends_at > NOW()
+				AND (stock_cap IS NULL OR sold_count < stock_cap)
+			) AS is_active,
+			created_at,
+			updated_at
+		FROM
+			product_discounts
+		WHERE
+			product_id = $1
+			AND deleted_at IS NULL
+		ORDER BY starts_at DESC
+	`
+
+	err := r.db.SelectContext(ctx, &res.Items, query, req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetDiscounts failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *discountRepository) IsProductOwner(ctx context.Context, userId, productId string) (bool, error) {
+	var (
+		isOwner bool
+		payload = struct {
+			UserId    string `json:"user_id"`
+			ProductId string `json:"product_id"`
+		}{userId, productId}
+	)
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM
+					products
+				JOIN
+					shops ON products.shop_id = shops.id
+				WHERE
+					shops.user_id = $1
+					AND products.id = $2
+					AND products.deleted_at IS NULL
+			)
+	`
+
+	err := r.db.GetContext(ctx, &isOwner, query, userId, productId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", payload).Msg("repository: IsProductOwner failed")
+		return isOwner, err
+	}
+
+	return isOwner, nil
+}
+
+func (r *discountRepository) GetProductPrice(ctx context.Context, productId string) (decimal.Decimal, error) {
+	var price decimal.Decimal
+
+	query := `SELECT price FROM products WHERE id = $1 AND deleted_at IS NULL`
+
+	err := r.db.GetContext(ctx, &price, query, productId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("product_id", productId).Msg("repository: Product not found")
+			return price, errmsg.NewCostumErrors(404, errmsg.WithMessage("Product not found"))
+		}
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: GetProductPrice failed")
+		return price, err
+	}
+
+	return price, nil
+}
+
+func (r *discountRepository) IsPeriodOverlapping(ctx context.Context, productId string, startsAt, endsAt time.Time) (bool, error) {
+	var overlapping bool
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM
+					product_discounts
+				WHERE
+					product_id = $1
+					AND deleted_at IS NULL
+					AND starts_at < $3
+					AND ends_at > $2
+			)
+	`
+
+	err := r.db.GetContext(ctx, &overlapping, query, productId, startsAt.UTC(), endsAt.UTC())
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: IsPeriodOverlapping failed")
+		return overlapping, err
+	}
+
+	return overlapping, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/discount/entity"
+	"product-service/internal/module/discount/ports"
+	productService "product-service/internal/module/product/service"
+	"product-service/pkg/cache"
+	"product-service/pkg/decimal"
+	"product-service/pkg/errmsg"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type discountService struct {
+	repo  ports.DiscountRepository
+	cache cache.Cache
+}
+
+func NewDiscountService(r ports.DiscountRepository, c cache.Cache) ports.DiscountService {
+	return &discountService{
+		repo:  r,
+		cache: c,
+	}
+}
+
+func (s *discountService) CreateDiscount(ctx context.Context, req *entity.CreateDiscountRequest) (entity.DiscountResponse, error) {
+	var res entity.DiscountResponse
+
+	isOwner, err := s.repo.IsProductOwner(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if !isOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not product owner")
+		return res, errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not product owner"))
+	}
+
+	if !req.EndsAt.After(time.Now()) {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("ends_at", "ends at must be in the future."))
+	}
+
+	price, err := s.repo.GetProductPrice(ctx, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	switch req.Type {
+	case entity.TypePercentage:
+		if !req.Value.LessThan(decimal.NewFromInt(100)) {
+			return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("value", "value must be less than 100 for percentage discounts."))
+		}
+	case entity.TypeFixed:
+		if !req.Value.LessThan(price) {
+			return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("value", "value must be less than the product price."))
+		}
+	}
+
+	// only one discount may apply at a time, otherwise the final price is ambiguous
+	overlapping, err := s.repo.IsPeriodOverlapping(ctx, req.ProductId, req.StartsAt, req.EndsAt)
+	if err != nil {
+		return res, err
+	}
+
+	if overlapping {
+		log.Warn().Any("payload", req).Msg("service: Discount period overlaps")
+		return res, errmsg.NewCostumErrors(409, errmsg.WithErrors("starts_at", "discount period overlaps another discount."))
+	}
+
+	res, err = s.repo.CreateDiscount(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	productService.InvalidateProducts(ctx, s.cache, req.ProductId)
+	return res, nil
+}
+
+func (s *discountService) DeleteDiscount(ctx context.Context, req *entity.DeleteDiscountRequest) error {
+	isOwner, err := s.repo.IsProductOwner(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return err
+	}
+
+	if !isOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not product owner")
+		return errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not product owner"))
+	}
+
+	if err = s.repo.DeleteDiscount(ctx, req); err != nil {
+		return err
+	}
+
+	productService.InvalidateProducts(ctx, s.cache, req.ProductId)
+	return nil
+}
+
+func (s *discountService) GetDiscounts(ctx context.Context, req *entity.GetDiscountsRequest) (entity.GetDiscountsResponse, error) {
+	var res entity.GetDiscountsResponse
+
+	isOwner, err := s.repo.IsProductOwner(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if !isOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not product owner")
+		return res, errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not product owner"))
+	}
+
+	return s.repo.GetDiscounts(ctx, req)
+}
@@ -0,0 +1,102 @@
+package entity
+
+import (
+	productEntity "product-service/internal/module/product/entity"
+	"time"
+)
+
+type CreateInquiryRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId string `params:"id" validate:"required,uuid"`
+	Question  string `json:"question" validate:"required,min=3,max=1000"`
+}
+
+type CreateAnswerRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	InquiryId string `params:"id" validate:"required,uuid"`
+	Answer    string `json:"answer" validate:"required,min=1,max=1000"`
+}
+
+type AcceptAnswerRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	InquiryId string `params:"id" validate:"required,uuid"`
+	AnswerId  string `params:"answer_id" validate:"required,uuid"`
+}
+
+type GetInquiriesRequest struct {
+	ProductId string `params:"id" validate:"required,uuid"`
+
+	Page  int `query:"page" validate:"required,min=1"`
+	Limit int `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetInquiriesRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type GetUnansweredInquiriesRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId string `params:"id" validate:"required,uuid"`
+
+	Page  int `query:"page" validate:"required,min=1"`
+	Limit int `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetUnansweredInquiriesRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type InquiryResponse struct {
+	Id        string    `json:"id" db:"id"`
+	ProductId string    `json:"product_id" db:"product_id"`
+	UserId    string    `json:"user_id" db:"user_id"`
+	Question  string    `json:"question" db:"question"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type AnswerResponse struct {
+	Id         string    `json:"id" db:"id"`
+	InquiryId  string    `json:"inquiry_id" db:"inquiry_id"`
+	UserId     string    `json:"user_id" db:"user_id"`
+	Answer     string    `json:"answer" db:"answer"`
+	IsAccepted bool      `json:"is_accepted" db:"is_accepted"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type GetInquiriesResponse struct {
+	Items []Inquiry          `json:"items"`
+	Meta  productEntity.Meta `json:"meta"`
+}
+
+type Inquiry struct {
+	InquiryResponse
+	Answers []AnswerResponse `json:"answers"`
+}
+
+type GetUnansweredInquiriesResponse struct {
+	Items []UnansweredInquiry `json:"items"`
+	Meta  productEntity.Meta  `json:"meta"`
+}
+
+type UnansweredInquiry struct {
+	InquiryResponse
+	ProductName string `json:"product_name" db:"product_name"`
+}
@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/inquiry/entity"
+)
+
+type InquiryService interface {
+	CreateInquiry(ctx context.Context, req *entity.CreateInquiryRequest) (entity.InquiryResponse, error)
+	GetInquiries(ctx context.Context, req *entity.GetInquiriesRequest) (entity.GetInquiriesResponse, error)
+	CreateAnswer(ctx context.Context, req *entity.CreateAnswerRequest) (entity.AnswerResponse, error)
+	AcceptAnswer(ctx context.Context, req *entity.AcceptAnswerRequest) (entity.AnswerResponse, error)
+	GetUnansweredInquiries(ctx context.Context, req *entity.GetUnansweredInquiriesRequest) (entity.GetUnansweredInquiriesResponse, error)
+}
+
+type InquiryRepository interface {
+	CreateInquiry(ctx context.Context, req *entity.CreateInquiryRequest) (entity.InquiryResponse, error)
+	GetInquiries(ctx context.Context, req *entity.GetInquiriesRequest) (entity.GetInquiriesResponse, error)
+	CreateAnswer(ctx context.Context, req *entity.CreateAnswerRequest) (entity.AnswerResponse, error)
+	AcceptAnswer(ctx context.Context, req *entity.AcceptAnswerRequest) (entity.AnswerResponse, error)
+	GetUnansweredInquiries(ctx context.Context, req *entity.GetUnansweredInquiriesRequest) (entity.GetUnansweredInquiriesResponse, error)
+
+	IsProductExist(ctx context.Context, productId string) (bool, error)
+	IsShopOwner(ctx context.Context, userId, shopId string) (bool, error)
+	IsInquiryShopOwner(ctx context.Context, userId, inquiryId string) (bool, error)
+	IsInquiryAuthor(ctx context.Context, userId, inquiryId string) (bool, error)
+}
@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"product-service/internal/module/inquiry/entity"
+	"product-service/internal/module/inquiry/ports"
+	"product-service/pkg/errmsg"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+type inquiryRepository struct {
+	db *sqlx.DB
+}
+
+func NewInquiryRepository(db *sqlx.DB) ports.InquiryRepository {
+	return &inquiryRepository{
+		db: db,
+	}
+}
+
+func (r *inquiryRepository) CreateInquiry(ctx context.Context, req *entity.CreateInquiryRequest) (entity.InquiryResponse, error) {
+	var (
+		res entity.InquiryResponse
+	)
+
+	query := `
+		INSERT INTO
+			product_inquiries (product_id, user_id, question)
+		VALUES ($1, $2, $3)
+		RETURNING
+			id, product_id, user_id, question, created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, req.ProductId, req.UserId, req.Question).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateInquiry failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *inquiryRepository) GetInquiries(ctx context.Context, req *entity.GetInquiriesRequest) (entity.GetInquiriesResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.InquiryResponse
+	}
+	var (
+		res     entity.GetInquiriesResponse
+		data    = make([]dao, 0)
+		answers = make([]entity.AnswerResponse, 0)
+		ids     = make([]string, 0)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.Inquiry, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			id,
+			product_id,
+			user_id,
+			question,
+			created_at,
+			updated_at
+		FROM
+			product_inquiries
+		WHERE
+			product_id = $1
+			AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2
+		OFFSET $3
+	`
+
+	err := r.db.SelectContext(ctx, &data, query, req.ProductId, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetInquiries failed")
+		return res, err
+	}
+
+	if len(data) == 0 {
+		return res, nil
+	}
+
+	for _, d := range data {
+		ids = append(ids, d.Id)
+	}
+
+	answersQuery := `
+		SELECT
+			id,
+			inquiry_id,
+			user_id,
+			answer,
+			is_accepted,
+			created_at,
+			updated_at
+		FROM
+			product_inquiry_answers
+		WHERE
+			inquiry_id = ANY($1)
+			AND deleted_at IS NULL
+		ORDER BY is_accepted DESC, created_at ASC
+	`
+
+	err = r.db.SelectContext(ctx, &answers, answersQuery, pq.Array(ids))
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetInquiries answers failed")
+		return res, err
+	}
+
+	answersByInquiry := make(map[string][]entity.AnswerResponse)
+	for _, a := range answers {
+		answersByInquiry[a.InquiryId] = append(answersByInquiry[a.InquiryId], a)
+	}
+
+	for _, d := range data {
+		item := entity.Inquiry{
+			InquiryResponse: d.InquiryResponse,
+			Answers:         answersByInquiry[d.Id],
+		}
+		if item.Answers == nil {
+			item.Answers = make([]entity.AnswerResponse, 0)
+		}
+		res.Items = append(res.Items, item)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+func (r *inquiryRepository) CreateAnswer(ctx context.Context, req *entity.CreateAnswerRequest) (entity.AnswerResponse, error) {
+	var (
+		res entity.AnswerResponse
+	)
+
+	query := `
+		INSERT INTO
+			product_inquiry_answers (inquiry_id, user_id, answer)
+		VALUES ($1, $2, $3)
+		RETURNING
+			id, inquiry_id, user_id, answer, is_accepted, created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, req.InquiryId, req.UserId, req.Answer).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateAnswer failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *inquiryRepository) AcceptAnswer(ctx context.Context, req *entity.AcceptAnswerRequest) (entity.AnswerResponse, error) {
+	var (
+		res entity.AnswerResponse
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: AcceptAnswer failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	// clear the previously accepted answer first, the partial unique index
+	// only allows one accepted answer per inquiry
+	resetQuery := `
+		UPDATE
+			product_inquiry_answers
+		SET
+			is_accepted = FALSE,
+			updated_at = NOW()
+		WHERE
+			inquiry_id = $1
+			AND id <> $2
+			AND is_accepted
+	`
+
+	_, err = tx.ExecContext(ctx, resetQuery, req.InquiryId, req.AnswerId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: AcceptAnswer failed")
+		return res, err
+	}
+
+	query := `
+		UPDATE
+			product_inquiry_answers
+		SET
+			is_accepted = TRUE,
+			updated_at = NOW()
+		WHERE
+			id = $1
+			AND inquiry_id = $2
+			AND deleted_at IS NULL
+		RETURNING
+			id, inquiry_id, user_id, answer, is_accepted, created_at, updated_at
+	`
+
+	err = tx.QueryRowxContext(ctx, query, req.AnswerId, req.InquiryId).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Answer not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Answer not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: AcceptAnswer failed")
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: AcceptAnswer failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *inquiryRepository) GetUnansweredInquiries(ctx context.Context, req *entity.GetUnansweredInquiriesRequest) (entity.GetUnansweredInquiriesResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.UnansweredInquiry
+	}
+	var (
+		res  entity.GetUnansweredInquiriesResponse
+		data = make([]dao, 0)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.UnansweredInquiry, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			pi.id,
+			pi.product_id,
+			pi.user_id,
+			pi.question,
+			pi.created_at,
+			pi.updated_at,
+			p.name AS product_name
+		FROM
+			product_inquiries pi
+		JOIN
+			products p ON p.id = pi.product_id
+		WHERE
+			p.shop_id = $1
+			AND p.deleted_at IS NULL
+			AND pi.deleted_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1
+				FROM
+					product_inquiry_answers pia
+				WHERE
+					pia.inquiry_id = pi.id
+					AND pia.deleted_at IS NULL
+			)
+		ORDER BY pi.created_at ASC
+		LIMIT $2
+		OFFSET $3
+	`
+
+	err := r.db.SelectContext(ctx, &data, query, req.ShopId, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetUnansweredInquiries failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.UnansweredInquiry)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+func (r *inquiryRepository) IsProductExist(ctx context.Context, productId string) (bool, error) {
+	var exist bool
+
+	query := `SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)`
+
+	err := r.db.GetContext(ctx, &exist, query, productId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", productId).Msg("repository: IsProductExist failed")
+		return exist, err
+	}
+
+	return exist, nil
+}
+
+func (r *inquiryRepository) IsShopOwner(ctx context.Context, userId, shopId string) (bool, error) {
+	var (
+		isOwner bool
+		payload = struct {
+			UserId string `json:"user_id"`
+			ShopId string `json:"shop_id"`
+		}{userId, shopId}
+	)
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM
+					shops
+				WHERE
+					user_id = $1
+					AND id = $2
+					AND deleted_at IS NULL
+			)
+	`
+
+	err := r.db.GetContext(ctx, &isOwner, query, userId, shopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", payload).Msg("repository: IsShopOwner failed")
+		return isOwner, err
+	}
+
+	return isOwner, nil
+}
+
+func (r *inquiryRepository) IsInquiryShopOwner(ctx context.Context, userId, inquiryId string) (bool, error) {
+	var (
+		isOwner bool
+		payload = struct {
+			UserId    string `json:"user_id"`
+			InquiryId string `json:"inquiry_id"`
+		}{userId, inquiryId}
+	)
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM
+					product_inquiries pi
+				JOIN
+					products p ON p.id = pi.product_id
+				JOIN
+					shops s ON s.id = p.shop_id
+				WHERE
+					s.user_id = $1
+					AND pi.id = $2
+					AND pi.deleted_at IS NULL
+					AND s.deleted_at IS NULL
+			)
+	`
+
+	err := r.db.GetContext(ctx, &isOwner, query, userId, inquiryId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", payload).Msg("repository: IsInquiryShopOwner failed")
+		return isOwner, err
+	}
+
+	return isOwner, nil
+}
+
+func (r *inquiryRepository) IsInquiryAuthor(ctx context.Context, userId, inquiryId string) (bool, error) {
+	var (
+		isAuthor bool
+		payload  = struct {
+			UserId    string `json:"user_id"`
+			InquiryId string `json:"inquiry_id"`
+		}{userId, inquiryId}
+	)
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM
+					product_inquiries
+				WHERE
+					user_id = $1
+					AND id = $2
+					AND deleted_at IS NULL
+			)
+	`
+
+	err := r.db.GetContext(ctx, &isAuthor, query, userId, inquiryId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", payload).Msg("repository: IsInquiryAuthor failed")
+		return isAuthor, err
+	}
+
+	return isAuthor, nil
+}
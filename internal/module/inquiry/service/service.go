@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/inquiry/entity"
+	"product-service/internal/module/inquiry/ports"
+	"product-service/pkg/errmsg"
+
+	"github.com/rs/zerolog/log"
+)
+
+type inquiryService struct {
+	repo ports.InquiryRepository
+}
+
+func NewInquiryService(r ports.InquiryRepository) ports.InquiryService {
+	return &inquiryService{
+		repo: r,
+	}
+}
+
+func (s *inquiryService) CreateInquiry(ctx context.Context, req *entity.CreateInquiryRequest) (entity.InquiryResponse, error) {
+	var res entity.InquiryResponse
+
+	exist, err := s.repo.IsProductExist(ctx, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if !exist {
+		log.Warn().Any("payload", req).Msg("service: Product not found")
+		return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Product not found"))
+	}
+
+	return s.repo.CreateInquiry(ctx, req)
+}
+
+func (s *inquiryService) GetInquiries(ctx context.Context, req *entity.GetInquiriesRequest) (entity.GetInquiriesResponse, error) {
+	return s.repo.GetInquiries(ctx, req)
+}
+
+func (s *inquiryService) CreateAnswer(ctx context.Context, req *entity.CreateAnswerRequest) (entity.AnswerResponse, error) {
+	var res entity.AnswerResponse
+
+	isShopOwner, err := s.repo.IsInquiryShopOwner(ctx, req.UserId, req.InquiryId)
+	if err != nil {
+		return res, err
+	}
+
+	if !isShopOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not shop owner")
+		return res, errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not shop owner"))
+	}
+
+	return s.repo.CreateAnswer(ctx, req)
+}
+
+func (s *inquiryService) AcceptAnswer(ctx context.Context, req *entity.AcceptAnswerRequest) (entity.AnswerResponse, error) {
+	var res entity.AnswerResponse
+
+	isAuthor, err := s.repo.IsInquiryAuthor(ctx, req.UserId, req.InquiryId)
+	if err != nil {
+		return res, err
+	}
+
+	if !isAuthor {
+		log.Warn().Any("payload", req).Msg("service: User is not inquiry author")
+		return res, errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not inquiry author"))
+	}
+
+	return s.repo.AcceptAnswer(ctx, req)
+}
+
+func (s *inquiryService) GetUnansweredInquiries(ctx context.Context, req *entity.GetUnansweredInquiriesRequest) (entity.GetUnansweredInquiriesResponse, error) {
+	var res entity.GetUnansweredInquiriesResponse
+
+	isShopOwner, err := s.repo.IsShopOwner(ctx, req.UserId, req.ShopId)
+	if err != nil {
+		return res, err
+	}
+
+	if !isShopOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not shop owner")
+		return res, errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not shop owner"))
+	}
+
+	return s.repo.GetUnansweredInquiries(ctx, req)
+}
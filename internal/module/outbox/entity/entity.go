@@ -0,0 +1,57 @@
+package entity
+
+import "time"
+
+const (
+	AggregateProduct = "product"
+	AggregateShop    = "shop"
+
+	EventProductCreated      = "product.created"
+	EventProductUpdated      = "product.updated"
+	EventProductDeleted      = "product.deleted"
+	EventProductStockChanged = "product.stock_changed"
+	EventProductLowStock     = "product.low_stock"
+
+	EventProductSubmitted = "product.submitted"
+	EventProductApproved  = "product.approved"
+	EventProductRejected  = "product.rejected"
+
+	EventProductPublished   = "product.published"
+	EventProductUnpublished = "product.unpublished"
+
+	EventShopCreated = "shop.created"
+	EventShopUpdated = "shop.updated"
+	EventShopDeleted = "shop.deleted"
+)
+
+// Event is a message to publish once the transaction writing it commits.
+// Payload is marshalled to JSON. ShopId is the shop whose webhooks receive
+// the event, events without one are only published to the broker.
+type Event struct {
+	AggregateType string
+	AggregateId   string
+	EventType     string
+	ShopId        string
+	Payload       any
+}
+
+// Record is an outbox row waiting to be relayed.
+type Record struct {
+	Id            string    `db:"id"`
+	Seq           int64     `db:"seq"`
+	AggregateType string    `db:"aggregate_type"`
+	AggregateId   string    `db:"aggregate_id"`
+	EventType     string    `db:"event_type"`
+	Payload       []byte    `db:"payload"`
+	Attempts      int       `db:"attempts"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// RelayRun counts what one relay batch did. Failed events are retried later
+// unless they ran out of attempts, which Dead counts.
+type RelayRun struct {
+	Published int
+	Failed    int
+	Dead      int
+	Claimed   int
+}
@@ -0,0 +1,66 @@
+package relay
+
+import (
+	"context"
+	"product-service/internal/adapter"
+	"product-service/internal/module/outbox/ports"
+	"product-service/internal/module/outbox/repository"
+	"product-service/internal/module/outbox/service"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// outboxRelay periodically publishes the outbox to the broker. Replicas may
+// run one each, the repository lets a single relay work at a time.
+type outboxRelay struct {
+	service  ports.OutboxService
+	interval time.Duration
+}
+
+func NewOutboxRelay(interval time.Duration, batchSize, maxAttempts int) *outboxRelay {
+	repo := repository.NewOutboxRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewOutboxService(repo, adapter.Adapters.Broker, batchSize, maxAttempts)
+
+	return &outboxRelay{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is cancelled, a batch in progress is rolled back and
+// its events are published again by the next run.
+func (r *outboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", r.interval).Msg("relay: Outbox relay started")
+
+	for {
+		if err := r.Once(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("relay: Outbox relay run failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("relay: Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Once publishes the events due now.
+func (r *outboxRelay) Once(ctx context.Context) error {
+	run, err := r.service.Relay(ctx)
+
+	if run.Published > 0 || run.Failed > 0 {
+		log.Info().Int("published", run.Published).Int("failed", run.Failed).Msg("relay: Relayed outbox events")
+	}
+
+	if run.Dead > 0 {
+		log.Error().Int("dead", run.Dead).Msg("relay: Outbox events ran out of attempts")
+	}
+
+	return err
+}
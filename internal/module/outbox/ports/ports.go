@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/outbox/entity"
+)
+
+type OutboxService interface {
+	Relay(ctx context.Context) (entity.RelayRun, error)
+}
+
+// PublishFunc hands a record to the broker, an error leaves it for a retry.
+type PublishFunc func(ctx context.Context, record entity.Record) error
+
+type OutboxRepository interface {
+	RelayEvents(ctx context.Context, limit, maxAttempts int, publish PublishFunc) (entity.RelayRun, error)
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"product-service/internal/module/outbox/entity"
+	"product-service/internal/module/outbox/ports"
+	webhookEntity "product-service/internal/module/webhook/entity"
+	webhookRepository "product-service/internal/module/webhook/repository"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// retryBase and retryMax bound the exponential backoff between attempts
+	retryBase = 2 * time.Second
+	retryMax  = 10 * time.Minute
+)
+
+type outboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) ports.OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+// Enqueue writes the event to the outbox. It must run in the transaction of
+// the change it announces, so the event exists if and only if the change does.
+func Enqueue(ctx context.Context, tx sqlx.ExtContext, event entity.Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		log.Error().Err(err).Str("event_type", event.EventType).Msg("repository: Enqueue failed to marshal payload")
+		return err
+	}
+
+	query := `
+		INSERT INTO
+			outbox (aggregate_type, aggregate_id, event_type, payload)
+		VALUES
+			($1, $2, $3, $4)
+		RETURNING
+			id,
+			created_at
+	`
+
+	var queued struct {
+		Id        string    `db:"id"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+
+	err = sqlx.GetContext(ctx, tx, &queued, query, event.AggregateType, event.AggregateId, event.EventType, payload)
+	if err != nil {
+		log.Error().Err(err).Str("event_type", event.EventType).Str("aggregate_id", event.AggregateId).Msg("repository: Enqueue failed")
+		return err
+	}
+
+	if event.ShopId == "" {
+		return nil
+	}
+
+	// the outbox id doubles as the webhook event id, receivers use it to
+	// drop the duplicates retries can cause
+	return webhookRepository.Fanout(ctx, tx, webhookEntity.Envelope{
+		Id:        queued.Id,
+		Type:      event.EventType,
+		ShopId:    event.ShopId,
+		CreatedAt: queued.CreatedAt,
+		Data:      payload,
+	})
+}
+
+// RelayEvents publishes a batch of due events in outbox order. Only one relay
+// works at a time, the others find the advisory lock taken and return an
+// empty run, which keeps the events of an aggregate in order across
+// replicas. An aggregate whose event is waiting for a retry is held back, so
+// its later events are never published first. Events out of attempts are
+// marked failed and stop holding their aggregate back.
+func (r *outboxRepository) RelayEvents(ctx context.Context, limit, maxAttempts int, publish ports.PublishFunc) (entity.RelayRun, error) {
+	var run entity.RelayRun
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository: RelayEvents failed to begin transaction")
+		return run, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err = tx.GetContext(ctx, &locked, `SELECT pg_try_advisory_xact_lock(hashtext('outbox-relay'))`); err != nil {
+		log.Error().Err(err).Msg("repository: RelayEvents failed to take the relay lock")
+		return run, err
+	}
+
+	if !locked {
+		return run, nil
+	}
+
+	records := make([]entity.Record, 0)
+
+	query := `
+		SELECT
+			o.id,
+			o.seq,
+			o.aggregate_type,
+			o.aggregate_id,
+			o.event_type,
+			o.payload,
+			o.attempts,
+			o.created_at
+		FROM
+			outbox o
+		WHERE
+			o.published_at IS NULL
+			AND o.failed_at IS NULL
+			AND o.next_attempt_at <= NOW()
+			AND NOT EXISTS (
+				SELECT 1
+				FROM
+					outbox e
+				WHERE
+					e.aggregate_id = o.aggregate_id
+					AND e.seq < o.seq
+					AND e.published_at IS NULL
+					AND e.failed_at IS NULL
+					AND e.next_attempt_at > NOW()
+			)
+		ORDER BY o.seq ASC
+		LIMIT $1
+	`
+
+	if err = tx.SelectContext(ctx, &records, query, limit); err != nil {
+		log.Error().Err(err).Msg("repository: RelayEvents failed to claim events")
+		return run, err
+	}
+	run.Claimed = len(records)
+
+	// aggregates with an event failing in this batch, their later events wait
+	held := make(map[string]bool)
+
+	for _, record := range records {
+		if held[record.AggregateId] {
+			continue
+		}
+
+		if err = publish(ctx, record); err != nil {
+			held[record.AggregateId] = true
+
+			dead, err := r.markFailed(ctx, tx, record, maxAttempts, err)
+			if err != nil {
+				return run, err
+			}
+
+			run.Failed++
+			if dead {
+				run.Dead++
+			}
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE outbox SET published_at = NOW(), last_error = NULL WHERE id = $1`, record.Id)
+		if err != nil {
+			log.Error().Err(err).Str("id", record.Id).Msg("repository: RelayEvents failed to mark event published")
+			return run, err
+		}
+		run.Published++
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository: RelayEvents failed to commit transaction")
+		return run, err
+	}
+
+	return run, nil
+}
+
+// markFailed schedules the next attempt of the record, or gives up on it
+// once maxAttempts is reached and reports it as dead.
+func (r *outboxRepository) markFailed(ctx context.Context, tx *sqlx.Tx, record entity.Record, maxAttempts int, cause error) (bool, error) {
+	var (
+		attempts = record.Attempts + 1
+		dead     = attempts >= maxAttempts
+	)
+
+	query := `
+		UPDATE
+			outbox
+		SET
+			attempts = $2,
+			last_error = $3,
+			next_attempt_at = NOW() + make_interval(secs => $4),
+			failed_at = CASE WHEN $5 THEN NOW() END
+		WHERE
+			id = $1
+	`
+
+	_, err := tx.ExecContext(ctx, query, record.Id, attempts, cause.Error(), backoff(attempts).Seconds(), dead)
+	if err != nil {
+		log.Error().Err(err).Str("id", record.Id).Msg("repository: RelayEvents failed to mark event failed")
+		return false, err
+	}
+
+	return dead, nil
+}
+
+// backoff doubles the wait after every failed attempt up to retryMax.
+func backoff(attempts int) time.Duration {
+	wait := retryBase
+	for i := 1; i < attempts && wait < retryMax; i++ {
+		wait *= 2
+	}
+
+	return min(wait, retryMax)
+}
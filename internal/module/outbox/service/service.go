@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/outbox/entity"
+	"product-service/internal/module/outbox/ports"
+	"product-service/pkg/broker"
+
+	"github.com/rs/zerolog/log"
+)
+
+type outboxService struct {
+	repo        ports.OutboxRepository
+	publisher   broker.Publisher
+	batchSize   int
+	maxAttempts int
+}
+
+func NewOutboxService(r ports.OutboxRepository, publisher broker.Publisher, batchSize, maxAttempts int) ports.OutboxService {
+	return &outboxService{
+		repo:        r,
+		publisher:   publisher,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Relay publishes every due event, a run claiming a full batch is followed
+// by another straight away.
+func (s *outboxService) Relay(ctx context.Context) (entity.RelayRun, error) {
+	var total entity.RelayRun
+
+	for {
+		run, err := s.repo.RelayEvents(ctx, s.batchSize, s.maxAttempts, s.publish)
+		if err != nil {
+			return total, err
+		}
+
+		total.Claimed += run.Claimed
+		total.Published += run.Published
+		total.Failed += run.Failed
+		total.Dead += run.Dead
+
+		if run.Claimed < s.batchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
+
+func (s *outboxService) publish(ctx context.Context, record entity.Record) error {
+	err := s.publisher.Publish(ctx, broker.Message{
+		Id:        record.Id,
+		Subject:   record.EventType,
+		Key:       record.AggregateId,
+		Payload:   record.Payload,
+		CreatedAt: record.CreatedAt,
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("id", record.Id).Str("event_type", record.EventType).Int("attempts", record.Attempts+1).Msg("service: Failed to publish event")
+		return err
+	}
+
+	log.Debug().Str("id", record.Id).Str("event_type", record.EventType).Str("aggregate_id", record.AggregateId).Msg("service: Event published")
+	return nil
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+const (
+	AttributeTypeText    = "text"
+	AttributeTypeNumber  = "number"
+	AttributeTypeEnum    = "enum"
+	AttributeTypeBoolean = "boolean"
+)
+
+// Attributes holds product attribute values keyed by category attribute code,
+// stored in the products.attributes JSONB column.
+type Attributes map[string]any
+
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func (a *Attributes) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = Attributes{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return errors.New("entity: unsupported attributes type")
+	}
+}
+
+// AttributeDefinition is the subset of category_attributes needed to
+// validate product attribute values.
+type AttributeDefinition struct {
+	Code       string         `db:"code"`
+	Type       string         `db:"type"`
+	Options    pq.StringArray `db:"options"`
+	IsRequired bool           `db:"is_required"`
+}
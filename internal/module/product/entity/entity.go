@@ -1,45 +1,92 @@
 package entity
 
 import (
+	"errors"
+	"product-service/pkg"
+	"product-service/pkg/decimal"
 	"strconv"
+	"strings"
 	"time"
 )
 
+const (
+	FacetCategory     = "category"
+	FacetBrand        = "brand"
+	FacetPrice        = "price"
+	FacetAvailability = "availability"
+)
+
 type CreateProductRequest struct {
 	UserId string `query:"user_id" validate:"required,uuid"`
 
-	ShopId      string  `json:"shop_id" validate:"required,uuid"`
-	CategoryId  string  `json:"category_id" validate:"required,uuid"`
-	Name        string  `json:"name" validate:"required,max=255,min=3"`
-	Description *string `json:"description" validate:"omitempty,max=255,min=3"`
-	ImageUrl    *string `json:"image_url" validate:"omitempty,url"`
-	Price       float64 `json:"price" validate:"required,numeric"`
-	Stock       int64   `json:"stock" validate:"required,numeric"`
+	ShopId      string          `json:"shop_id" validate:"required,uuid"`
+	CategoryId  string          `json:"category_id" validate:"required,uuid"`
+	BrandId     *string         `json:"brand_id" validate:"omitempty,uuid"`
+	Name        string          `json:"name" validate:"required,max=255,min=3"`
+	Description *string         `json:"description" validate:"omitempty,max=255,min=3"`
+	ImageUrl    *string         `json:"image_url" validate:"omitempty,url"`
+	Price       decimal.Decimal `json:"price" validate:"required,gt=0"`
+	Currency    string          `json:"currency" validate:"omitempty,iso4217"`
+	Stock       int64           `json:"stock" validate:"required,numeric"`
+
+	// sellers are alerted once stock falls to it, nil turns alerts off
+	LowStockThreshold *int `json:"low_stock_threshold" validate:"omitempty,min=0"`
+
+	// the product is only live between these, both optional
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+
+	Attributes Attributes `json:"attributes" validate:"omitempty,max=50"`
 }
 
 type UpdateProductRequest struct {
 	UserId string `query:"user_id" validate:"required,uuid"`
 
-	Id          string  `params:"id" validate:"required,uuid"`
-	CategoryId  string  `json:"category_id" validate:"omitempty,uuid"`
-	Name        string  `json:"name" validate:"required,max=255,min=3"`
-	Description *string `json:"description" validate:"omitempty,max=255,min=3"`
-	ImageUrl    *string `json:"image_url" validate:"omitempty,url"`
-	Price       float64 `json:"price" validate:"required,numeric"`
-	Stock       int64   `json:"stock" validate:"required,numeric"`
+	Id          string          `params:"id" validate:"required,uuid"`
+	CategoryId  string          `json:"category_id" validate:"omitempty,uuid"`
+	BrandId     *string         `json:"brand_id" validate:"omitempty,uuid"` // nil keeps the brand
+	ClearBrand  bool            `json:"clear_brand" validate:"excluded_with=BrandId"`
+	Name        string          `json:"name" validate:"required,max=255,min=3"`
+	Description *string         `json:"description" validate:"omitempty,max=255,min=3"`
+	ImageUrl    *string         `json:"image_url" validate:"omitempty,url"`
+	Price       decimal.Decimal `json:"price" validate:"required,gt=0"`
+	Currency    string          `json:"currency" validate:"omitempty,iso4217"` // must be the product's, it can't change
+	Stock       int64           `json:"stock" validate:"required,numeric"`
+
+	// sellers are alerted once stock falls to it, nil keeps the stored one
+	// and clear_low_stock_threshold turns alerts off
+	LowStockThreshold      *int `json:"low_stock_threshold" validate:"omitempty,min=0"`
+	ClearLowStockThreshold bool `json:"clear_low_stock_threshold" validate:"excluded_with=LowStockThreshold"`
+
+	// the product is only live between these, nil keeps the stored bound
+	// and clear_schedule drops the stored ones, ones sent along are set
+	PublishAt     *time.Time `json:"publish_at"`
+	UnpublishAt   *time.Time `json:"unpublish_at"`
+	ClearSchedule bool       `json:"clear_schedule"`
+
+	Attributes Attributes `json:"attributes" validate:"omitempty,max=50"`
 }
 
 type UpsertProductResponse struct {
-	Id          string    `json:"id" db:"id"`
-	UserId      string    `json:"user_id" db:"user_id"`
-	ShopId      string    `json:"shop_id" db:"shop_id"`
-	Name        string    `json:"name" db:"name"`
-	Description *string   `json:"description" db:"description"`
-	ImageUrl    *string   `json:"image_url" db:"image_url"`
-	Price       float64   `json:"price" db:"price"`
-	Stock       int       `json:"stock" db:"stock"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Id                string          `json:"id" db:"id"`
+	UserId            string          `json:"user_id" db:"user_id"`
+	ShopId            string          `json:"shop_id" db:"shop_id"`
+	CategoryId        string          `json:"category_id" db:"category_id"`
+	BrandId           *string         `json:"brand_id" db:"brand_id"`
+	Brand             string          `json:"brand" db:"brand"`
+	Name              string          `json:"name" db:"name"`
+	Description       *string         `json:"description" db:"description"`
+	ImageUrl          *string         `json:"image_url" db:"image_url"`
+	Price             decimal.Decimal `json:"price" db:"price"`
+	Currency          string          `json:"currency" db:"currency"`
+	Stock             int             `json:"stock" db:"stock"`
+	LowStockThreshold *int            `json:"low_stock_threshold" db:"low_stock_threshold"`
+	Status            string          `json:"status" db:"status"`
+	PublishAt         *time.Time      `json:"publish_at" db:"publish_at"`
+	UnpublishAt       *time.Time      `json:"unpublish_at" db:"unpublish_at"`
+	Attributes        Attributes      `json:"attributes" db:"attributes"`
+	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at" db:"updated_at"`
 }
 
 type DeleteProductRequest struct {
@@ -48,40 +95,112 @@ type DeleteProductRequest struct {
 }
 
 type GetProductsRequest struct {
+	UserId string `query:"user_id" validate:"omitempty,uuid"` // optional, marks items in the caller's wishlist
+
 	ShopId      string `query:"shop_id" validate:"omitempty,uuid"`
 	CategoryId  string `query:"category_id" validate:"omitempty,uuid"`
 	Name        string `query:"name" validate:"omitempty,max=255,min=3"`
+	Sort        string `query:"sort" validate:"omitempty,oneof=newest price_asc price_desc"`
 	PriceMinStr string `query:"price_min" validate:"omitempty,numeric,gte=0"`
 	PriceMaxStr string `query:"price_max" validate:"omitempty,numeric,gte=0"`
 	IsAvailable bool   `query:"is_available"`
+	Currency    string `query:"currency" validate:"omitempty,iso4217"` // converts prices, price_min/max are read in it
+
+	Page  int `query:"page" validate:"required,min=1"`
+	Limit int `query:"limit" validate:"required,min=1,max=100"`
+
+	PriceMin decimal.Decimal
+	PriceMax decimal.Decimal
+
+	Brand     string `query:"brand" validate:"omitempty,max=255"`
+	BrandId   string `query:"brand_id" validate:"omitempty,uuid"`
+	BrandSlug string `query:"brand_slug" validate:"omitempty,max=255"`
+
+	AttributeFilters map[string]string // ex: attr[ram]=8GB, attr[screen_min]=6, filled by the handler
+
+	Facets          string `query:"facets"`        // ex: facets=category,brand,price
+	PriceBucketsStr string `query:"price_buckets"` // ex: price_buckets=0,100000,500000
+
+	FacetList    []string
+	PriceBuckets []decimal.Decimal
+}
+
+type GetRecommendedProductsRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
 
 	Page  int `query:"page" validate:"required,min=1"`
 	Limit int `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetRecommendedProductsRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
 
-	PriceMin float64
-	PriceMax float64
+type GetRelatedProductsRequest struct {
+	ProductId string `params:"id" validate:"required,uuid"`
 
-	Brand string `query:"brand"`
+	Limit int `query:"limit" validate:"required,min=1,max=50"`
+}
+
+func (r *GetRelatedProductsRequest) SetDefaults() {
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type GetRelatedProductsResponse struct {
+	Items []Product `json:"items"`
 }
 
 type GetProductRequestById struct {
 	ProductId string `params:"product_id" validate:"required,uuid"`
+	Currency  string `query:"currency" validate:"omitempty,iso4217"` // converts prices when set
+	UserId    string `query:"user_id" validate:"omitempty,uuid"`     // owners also see their unpublished products
 }
 
 type GetProductResponseById struct {
-	Id          string     `json:"id" db:"id"`
-	CategoryId  string     `json:"category_id" db:"category_id"`
-	ShopId      string     `json:"shop_id" db:"shop_id"`
-	Category    string     `json:"category" db:"category"`
-	Name        string     `json:"name" db:"name"`
-	Description *string    `json:"description" db:"description"`
-	ImageUrl    *string    `json:"image_url" db:"image_url"`
-	Price       float64    `json:"price" db:"price"`
-	Stock       int        `json:"stock" db:"stock"`
-	Brand       string     `json:"brand" db:"brand"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
-	DeleteAt    *time.Time `json:"deleted_at" db:"deleted_at"`
+	Id              string           `json:"id" db:"id"`
+	CategoryId      string           `json:"category_id" db:"category_id"`
+	ShopId          string           `json:"shop_id" db:"shop_id"`
+	Category        string           `json:"category" db:"category"`
+	Name            string           `json:"name" db:"name"`
+	Description     *string          `json:"description" db:"description"`
+	ImageUrl        *string          `json:"image_url" db:"image_url"`
+	Price           decimal.Decimal  `json:"price" db:"price"`
+	Currency        string           `json:"currency" db:"currency"`
+	OriginalPrice   decimal.Decimal  `json:"original_price" db:"original_price"`
+	FinalPrice      decimal.Decimal  `json:"final_price" db:"final_price"`
+	DiscountPct     decimal.Decimal  `json:"discount_percent" db:"discount_percent"`
+	LowestPrice30d  *decimal.Decimal `json:"lowest_price_30d" db:"-"`
+	Stock           int              `json:"stock" db:"stock"`
+	Status          string           `json:"status" db:"status"`
+	RejectionReason *string          `json:"rejection_reason" db:"rejection_reason"`
+	PublishAt       *time.Time       `json:"publish_at" db:"publish_at"`
+	UnpublishAt     *time.Time       `json:"unpublish_at" db:"unpublish_at"`
+	BrandId         *string          `json:"brand_id" db:"brand_id"`
+	Brand           string           `json:"brand" db:"brand"`
+	FavouriteCount  int              `json:"favourite_count" db:"favourite_count"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+	ModifiedAt      *time.Time       `json:"modified_at,omitempty" db:"modified_at"`
+	Attributes      Attributes       `json:"attributes" db:"attributes"`
+	DeleteAt        *time.Time       `json:"deleted_at" db:"deleted_at"`
+}
+
+type BatchGetProductsRequest struct {
+	ProductIds []string `json:"product_ids" validate:"required,min=1,max=100,dive,uuid"`
+	Currency   string   `json:"currency" validate:"omitempty,iso4217"`
+}
+
+type BatchGetProductsResponse struct {
+	Items      []GetProductResponseById `json:"items"`
+	MissingIds []string                 `json:"missing_ids"`
 }
 
 func (r *GetProductsRequest) SetDefaults() {
@@ -96,26 +215,59 @@ func (r *GetProductsRequest) SetDefaults() {
 
 func (r *GetProductsRequest) CostumValidation() (int, map[string][]string) {
 	var (
-		errors   = make(map[string][]string)
-		err      error
-		priceMin float64
-		priceMax float64
+		errors = make(map[string][]string)
+		err    error
 	)
 
 	if r.PriceMinStr != "" {
-		priceMin, err = strconv.ParseFloat(r.PriceMinStr, 64)
+		r.PriceMin, err = decimal.Parse(r.PriceMinStr)
 		if err != nil {
-			errors["price_min"] = append(errors["price_min"], "price_min must be a number.")
+			errors["price_min"] = append(errors["price_min"], "price_min must be a number with at most 4 decimal places.")
 		}
-		r.PriceMin = priceMin
 	}
 
 	if r.PriceMaxStr != "" {
-		priceMax, err = strconv.ParseFloat(r.PriceMaxStr, 64)
+		r.PriceMax, err = decimal.Parse(r.PriceMaxStr)
+		if err != nil {
+			errors["price_max"] = append(errors["price_max"], "price_max must be a number with at most 4 decimal places.")
+		}
+	}
+
+	if r.Facets != "" {
+		r.FacetList = r.FacetList[:0]
+		for _, facet := range strings.Split(r.Facets, ",") {
+			facet = strings.TrimSpace(facet)
+			switch facet {
+			case FacetCategory, FacetBrand, FacetPrice, FacetAvailability:
+				r.FacetList = append(r.FacetList, facet)
+			default:
+				errors["facets"] = append(errors["facets"], "facets must be one of category, brand, price, availability.")
+			}
+		}
+	}
+
+	if len(r.AttributeFilters) > 20 {
+		errors["attr"] = append(errors["attr"], "attr must not have more than 20 filters.")
+	}
+
+	for code, value := range r.AttributeFilters {
+		if !pkg.IsAttributeCode(code) {
+			errors["attr"] = append(errors["attr"], "attr["+code+"] is not a valid attribute code.")
+			continue
+		}
+
+		if strings.HasSuffix(code, "_min") || strings.HasSuffix(code, "_max") {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				errors["attr"] = append(errors["attr"], "attr["+code+"] must be a number.")
+			}
+		}
+	}
+
+	if r.PriceBucketsStr != "" {
+		r.PriceBuckets, err = ParsePriceBuckets(r.PriceBucketsStr)
 		if err != nil {
-			errors["price_max"] = append(errors["price_max"], "price_max must be a number.")
+			errors["price_buckets"] = append(errors["price_buckets"], err.Error())
 		}
-		r.PriceMax = priceMax
 	}
 
 	if len(errors) > 0 {
@@ -126,21 +278,94 @@ func (r *GetProductsRequest) CostumValidation() (int, map[string][]string) {
 	return 0, errors
 }
 
+// ParsePriceBuckets parses comma separated, strictly ascending bucket bounds.
+func ParsePriceBuckets(s string) ([]decimal.Decimal, error) {
+	var (
+		parts   = strings.Split(s, ",")
+		buckets = make([]decimal.Decimal, 0, len(parts))
+	)
+
+	if len(parts) > 20 {
+		return nil, errors.New("price_buckets must not have more than 20 items.")
+	}
+
+	for _, part := range parts {
+		bound, err := decimal.Parse(part)
+		if err != nil || bound.Sign() < 0 {
+			return nil, errors.New("price_buckets must be a list of positive numbers.")
+		}
+		buckets = append(buckets, bound)
+	}
+
+	for i := 1; i < len(buckets); i++ {
+		switch buckets[i].Cmp(buckets[i-1]) {
+		case -1:
+			return nil, errors.New("price_buckets must be in ascending order.")
+		case 0:
+			return nil, errors.New("price_buckets must be unique.")
+		}
+	}
+
+	return buckets, nil
+}
+
 type GetProductsResponse struct {
-	Items []Product `json:"items"`
-	Meta  Meta      `json:"meta"`
+	Items  []Product `json:"items"`
+	Meta   Meta      `json:"meta"`
+	Facets *Facets   `json:"facets,omitempty"`
+}
+
+// Facets are counted over the same filtered set as the listing, each facet
+// ignoring its own filter.
+type Facets struct {
+	Categories   []FacetBucket      `json:"categories,omitempty"`
+	Brands       []FacetBucket      `json:"brands,omitempty"`
+	Prices       []PriceFacetBucket `json:"prices,omitempty"`
+	Availability *AvailabilityFacet `json:"availability,omitempty"`
+}
+
+type FacetBucket struct {
+	Id    string `json:"id" db:"id"`
+	Name  string `json:"name" db:"name"`
+	Count int    `json:"count" db:"count"`
+}
+
+// PriceFacetBucket covers [Min, Max), a nil bound is open ended.
+type PriceFacetBucket struct {
+	Min   *decimal.Decimal `json:"min"`
+	Max   *decimal.Decimal `json:"max"`
+	Count int              `json:"count"`
+}
+
+type AvailabilityFacet struct {
+	InStock    int `json:"in_stock" db:"in_stock"`
+	OutOfStock int `json:"out_of_stock" db:"out_of_stock"`
 }
 
 type Product struct {
-	Id         string    `json:"id" db:"id"`
-	CategoryId string    `json:"category_id" db:"category_id"`
-	ShopId     string    `json:"shop_id" db:"shop_id"`
-	Name       string    `json:"name" db:"name"`
-	ImageUrl   *string   `json:"image_url" db:"image_url"`
-	Price      float64   `json:"price" db:"price"`
-	Brand      string    `json:"brand" db:"brand"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	Id             string          `json:"id" db:"id"`
+	CategoryId     string          `json:"category_id" db:"category_id"`
+	ShopId         string          `json:"shop_id" db:"shop_id"`
+	Name           string          `json:"name" db:"name"`
+	ImageUrl       *string         `json:"image_url" db:"image_url"`
+	Price          decimal.Decimal `json:"price" db:"price"`
+	Currency       string          `json:"currency" db:"currency"`
+	OriginalPrice  decimal.Decimal `json:"original_price" db:"original_price"`
+	FinalPrice     decimal.Decimal `json:"final_price" db:"final_price"`
+	DiscountPct    decimal.Decimal `json:"discount_percent" db:"discount_percent"`
+	BrandId        *string         `json:"brand_id" db:"brand_id"`
+	Brand          string          `json:"brand" db:"brand"`
+	FavouriteCount int             `json:"favourite_count" db:"favourite_count"`
+	InWishlist     bool            `json:"in_wishlist" db:"in_wishlist"`
+	Status         string          `json:"status" db:"status"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+	ModifiedAt     *time.Time      `json:"modified_at,omitempty" db:"modified_at"`
+}
+
+type Category struct {
+	Id   string `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
 }
 
 type Meta struct {
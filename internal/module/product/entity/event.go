@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"product-service/pkg/decimal"
+	"time"
+)
+
+// ProductEvent is the outbox payload of product.created and product.updated,
+// a snapshot of the product as the write left it.
+type ProductEvent struct {
+	ProductId   string          `json:"product_id"`
+	ShopId      string          `json:"shop_id"`
+	CategoryId  string          `json:"category_id"`
+	BrandId     *string         `json:"brand_id"`
+	Name        string          `json:"name"`
+	Description *string         `json:"description"`
+	ImageUrl    *string         `json:"image_url"`
+	Price       decimal.Decimal `json:"price"`
+	Currency    string          `json:"currency"`
+	Stock       int             `json:"stock"`
+	Status      string          `json:"status"`
+	PublishAt   *time.Time      `json:"publish_at"`
+	UnpublishAt *time.Time      `json:"unpublish_at"`
+	Attributes  Attributes      `json:"attributes"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func NewProductEvent(res UpsertProductResponse) ProductEvent {
+	return ProductEvent{
+		ProductId:   res.Id,
+		ShopId:      res.ShopId,
+		CategoryId:  res.CategoryId,
+		BrandId:     res.BrandId,
+		Name:        res.Name,
+		Description: res.Description,
+		ImageUrl:    res.ImageUrl,
+		Price:       res.Price,
+		Currency:    res.Currency,
+		Stock:       res.Stock,
+		Status:      res.Status,
+		PublishAt:   res.PublishAt,
+		UnpublishAt: res.UnpublishAt,
+		Attributes:  res.Attributes,
+		UpdatedAt:   res.UpdatedAt,
+	}
+}
+
+// ProductDeletedEvent is the outbox payload of product.deleted.
+type ProductDeletedEvent struct {
+	ProductId string    `json:"product_id" db:"product_id"`
+	ShopId    string    `json:"shop_id" db:"shop_id"`
+	DeletedAt time.Time `json:"deleted_at" db:"deleted_at"`
+}
+
+// StockChangedEvent is the outbox payload of product.stock_changed.
+type StockChangedEvent struct {
+	ProductId     string    `json:"product_id" db:"product_id"`
+	ShopId        string    `json:"shop_id" db:"shop_id"`
+	PreviousStock int       `json:"previous_stock" db:"previous_stock"`
+	Stock         int       `json:"stock" db:"stock"`
+	ChangedAt     time.Time `json:"changed_at" db:"changed_at"`
+}
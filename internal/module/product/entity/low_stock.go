@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+type GetLowStockRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId string `params:"id" validate:"required,uuid"`
+	Page   int    `query:"page" validate:"required,min=1"`
+	Limit  int    `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetLowStockRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type LowStockItem struct {
+	Id                string     `json:"id" db:"id"`
+	Name              string     `json:"name" db:"name"`
+	ImageUrl          *string    `json:"image_url" db:"image_url"`
+	Status            string     `json:"status" db:"status"`
+	Stock             int        `json:"stock" db:"stock"`
+	LowStockThreshold int        `json:"low_stock_threshold" db:"low_stock_threshold"`
+	AlertedAt         *time.Time `json:"alerted_at" db:"low_stock_alerted_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type GetLowStockResponse struct {
+	Items []LowStockItem `json:"items"`
+	Meta  Meta           `json:"meta"`
+}
+
+// LowStockEvent is the outbox payload telling the seller a product fell to
+// its low stock threshold.
+type LowStockEvent struct {
+	ProductId string    `json:"product_id" db:"product_id"`
+	ShopId    string    `json:"shop_id" db:"shop_id"`
+	SellerId  string    `json:"seller_id" db:"seller_id"`
+	Name      string    `json:"name" db:"name"`
+	Stock     int       `json:"stock" db:"stock"`
+	Threshold int       `json:"threshold" db:"threshold"`
+	AlertedAt time.Time `json:"alerted_at" db:"alerted_at"`
+}
@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"product-service/pkg/decimal"
+	"time"
+)
+
+// Products start as drafts, sellers submit them for review and only
+// published products are shown to the public. Rejected products can be
+// edited and submitted again. Editing the content of a published or pending
+// product sends it back to review.
+const (
+	StatusDraft         = "draft"
+	StatusPendingReview = "pending_review"
+	StatusPublished     = "published"
+	StatusRejected      = "rejected"
+)
+
+type SubmitProductRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId string `params:"id" validate:"required,uuid"`
+}
+
+type GetModerationQueueRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	Page  int `query:"page" validate:"required,min=1"`
+	Limit int `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetModerationQueueRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type ModerationQueueItem struct {
+	Id          string          `json:"id" db:"id"`
+	ShopId      string          `json:"shop_id" db:"shop_id"`
+	SellerId    string          `json:"seller_id" db:"seller_id"`
+	CategoryId  string          `json:"category_id" db:"category_id"`
+	Name        string          `json:"name" db:"name"`
+	Description *string         `json:"description" db:"description"`
+	ImageUrl    *string         `json:"image_url" db:"image_url"`
+	Price       decimal.Decimal `json:"price" db:"price"`
+	Currency    string          `json:"currency" db:"currency"`
+	Brand       string          `json:"brand" db:"brand"`
+	Attributes  Attributes      `json:"attributes" db:"attributes"`
+	SubmittedAt time.Time       `json:"submitted_at" db:"submitted_at"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+}
+
+type GetModerationQueueResponse struct {
+	Items []ModerationQueueItem `json:"items"`
+	Meta  Meta                  `json:"meta"`
+}
+
+type ApproveProductRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId string `params:"id" validate:"required,uuid"`
+}
+
+type RejectProductRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId string `params:"id" validate:"required,uuid"`
+	Reason    string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+// ReviewProduct is an approval or rejection as the repository applies it.
+type ReviewProduct struct {
+	ProductId  string
+	ReviewerId string
+	Status     string
+	Reason     *string
+}
+
+type ModerationResponse struct {
+	Id              string     `json:"id" db:"id"`
+	ShopId          string     `json:"shop_id" db:"shop_id"`
+	SellerId        string     `json:"seller_id" db:"seller_id"`
+	Name            string     `json:"name" db:"name"`
+	Status          string     `json:"status" db:"status"`
+	RejectionReason *string    `json:"rejection_reason" db:"rejection_reason"`
+	SubmittedAt     *time.Time `json:"submitted_at" db:"submitted_at"`
+	ReviewedBy      *string    `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt      *time.Time `json:"reviewed_at" db:"reviewed_at"`
+}
+
+// ModerationEvent is the outbox payload telling the seller how their
+// product was reviewed.
+type ModerationEvent struct {
+	ProductId  string    `json:"product_id"`
+	ShopId     string    `json:"shop_id"`
+	SellerId   string    `json:"seller_id"`
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	Reason     *string   `json:"reason"`
+	ReviewedAt time.Time `json:"reviewed_at"`
+}
+
+// SubmissionEvent is the outbox payload of a product entering the review
+// queue, PreviousStatus is published when an edit took it off the shelves.
+type SubmissionEvent struct {
+	ProductId      string    `json:"product_id"`
+	ShopId         string    `json:"shop_id"`
+	SellerId       string    `json:"seller_id"`
+	Name           string    `json:"name"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status"`
+	SubmittedAt    time.Time `json:"submitted_at"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"product-service/pkg/decimal"
+	"time"
+)
+
+const (
+	PriceSourceProduct  = "product"
+	PriceSourceDiscount = "discount"
+	PriceSourceCampaign = "campaign"
+)
+
+type GetPriceHistoryRequest struct {
+	ProductId string `params:"id" validate:"required,uuid"`
+
+	Page  int `query:"page" validate:"required,min=1"`
+	Limit int `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetPriceHistoryRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 20
+	}
+}
+
+type PriceHistoryEntry struct {
+	Price         decimal.Decimal `json:"price" db:"price"`
+	Source        string          `json:"source" db:"source"`
+	ReferenceId   *string         `json:"reference_id" db:"reference_id"`
+	EffectiveFrom time.Time       `json:"effective_from" db:"effective_from"`
+	EffectiveTo   *time.Time      `json:"effective_to" db:"effective_to"`
+}
+
+type GetPriceHistoryResponse struct {
+	Items []PriceHistoryEntry `json:"items"`
+	Meta  Meta                `json:"meta"`
+}
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"product-service/pkg/decimal"
+	"time"
+)
+
+const (
+	OfferSourceDiscount = "discount"
+	OfferSourceCampaign = "campaign"
+
+	UnavailableNotFound          = "not_found"
+	UnavailableOutOfStock        = "out_of_stock"
+	UnavailableInsufficientStock = "insufficient_stock"
+)
+
+type QuoteItem struct {
+	ProductId string `json:"product_id" validate:"required,uuid"`
+	Quantity  int    `json:"quantity" validate:"required,min=1,max=100000"`
+}
+
+// QuoteRequest prices a cart. The catalogue has no variants yet, so every
+// item refers to a product. Products priced in different currencies are only
+// quoted together in the currency they are converted to.
+type QuoteRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	Currency string      `json:"currency" validate:"omitempty,iso4217"`
+	Items    []QuoteItem `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// QuoteProduct is a product row read in the quote snapshot, with the offer
+// giving its final price.
+type QuoteProduct struct {
+	Id              string          `db:"id"`
+	ShopId          string          `db:"shop_id"`
+	Name            string          `db:"name"`
+	Stock           int             `db:"stock"`
+	Price           decimal.Decimal `db:"price"`
+	Currency        string          `db:"currency"`
+	OriginalPrice   decimal.Decimal `db:"original_price"`
+	FinalPrice      decimal.Decimal `db:"final_price"`
+	DiscountPercent decimal.Decimal `db:"discount_percent"`
+	OfferSource     *string         `db:"offer_source"`
+	OfferId         *string         `db:"offer_id"`
+	OfferEndsAt     *time.Time      `db:"offer_ends_at"`
+}
+
+// QuoteOffer is the discount or campaign a product is sold at.
+type QuoteOffer struct {
+	Source    string          `json:"source"`
+	Id        string          `json:"id"`
+	UnitPrice decimal.Decimal `json:"unit_price"`
+	EndsAt    time.Time       `json:"ends_at"`
+}
+
+type QuoteSnapshot struct {
+	Products []QuoteProduct
+	At       time.Time
+}
+
+type QuoteLine struct {
+	ProductId       string          `json:"product_id"`
+	ShopId          string          `json:"shop_id"`
+	Name            string          `json:"name"`
+	Quantity        int             `json:"quantity"`
+	Currency        string          `json:"currency"`
+	OriginalPrice   decimal.Decimal `json:"original_price"`
+	UnitPrice       decimal.Decimal `json:"unit_price"`
+	DiscountPercent decimal.Decimal `json:"discount_percent"`
+	Offer           *QuoteOffer     `json:"offer"`
+	LineDiscount    decimal.Decimal `json:"line_discount"`
+	LineTotal       decimal.Decimal `json:"line_total"`
+}
+
+type QuoteShopSubtotal struct {
+	ShopId   string          `json:"shop_id"`
+	Items    int             `json:"items"`
+	Discount decimal.Decimal `json:"discount"`
+	Subtotal decimal.Decimal `json:"subtotal"`
+}
+
+type QuoteUnavailableItem struct {
+	ProductId      string `json:"product_id"`
+	Quantity       int    `json:"quantity"`
+	AvailableStock int    `json:"available_stock"`
+	Reason         string `json:"reason"`
+}
+
+type QuoteResponse struct {
+	Currency    string                 `json:"currency"`
+	Items       []QuoteLine            `json:"items"`
+	Shops       []QuoteShopSubtotal    `json:"shops"`
+	Unavailable []QuoteUnavailableItem `json:"unavailable"`
+	Discount    decimal.Decimal        `json:"discount"`
+	Total       decimal.Decimal        `json:"total"`
+	QuotedAt    time.Time              `json:"quoted_at"`
+}
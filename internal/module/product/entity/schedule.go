@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// ScheduleEvent is the outbox payload announcing a product going live or
+// offline on its schedule.
+type ScheduleEvent struct {
+	ProductId string    `json:"product_id" db:"product_id"`
+	ShopId    string    `json:"shop_id" db:"shop_id"`
+	SellerId  string    `json:"seller_id" db:"seller_id"`
+	Name      string    `json:"name" db:"name"`
+	At        time.Time `json:"at" db:"at"`
+}
+
+// ScheduleRun counts the products a scheduler run announced, ProductIds
+// lists them for the cache.
+type ScheduleRun struct {
+	Published   int
+	Unpublished int
+	ProductIds  []string
+}
@@ -0,0 +1,31 @@
+package entity
+
+type StockItem struct {
+	ProductId string `json:"product_id" validate:"required,uuid"`
+	Quantity  int    `json:"quantity" validate:"required,min=1,max=100000"`
+}
+
+// ReserveStockRequest takes stock out for an order. Items of the same
+// product are reserved together.
+type ReserveStockRequest struct {
+	OrderId string      `json:"order_id" validate:"required,max=255"`
+	Items   []StockItem `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// ReleaseStockRequest puts the order's reserved stock back, of the listed
+// products only when ProductIds is set.
+type ReleaseStockRequest struct {
+	OrderId    string   `json:"order_id" validate:"required,max=255"`
+	ProductIds []string `json:"product_ids" validate:"omitempty,max=100,dive,uuid"`
+}
+
+type StockLevel struct {
+	ProductId string `json:"product_id" db:"product_id"`
+	Quantity  int    `json:"quantity" db:"quantity"`
+	Stock     int    `json:"stock" db:"stock"`
+}
+
+type StockResponse struct {
+	OrderId string       `json:"order_id"`
+	Items   []StockLevel `json:"items"`
+}
@@ -0,0 +1,166 @@
+package grpc
+
+import (
+	"context"
+	productv1 "product-service/api/proto/product/v1"
+	"product-service/internal/adapter"
+	"product-service/internal/module/product/entity"
+	"product-service/internal/module/product/ports"
+	"product-service/internal/module/product/repository"
+	"product-service/internal/module/product/service"
+	"product-service/pkg/errmsg"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// producthandler serves the product reads and stock reservations other
+// services need over gRPC. There is no user auth, the server listens on
+// APP_GRPC_HOST and callers authenticate with APP_GRPC_TOKEN.
+type producthandler struct {
+	productv1.UnimplementedProductServiceServer
+
+	service ports.ProductService
+}
+
+func NewProductHandler() *producthandler {
+	repo := repository.NewProductRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewProductService(repo, adapter.Adapters.Cache)
+
+	return &producthandler{
+		service: service,
+	}
+}
+
+func (h *producthandler) Register(srv *grpc.Server) {
+	productv1.RegisterProductServiceServer(srv, h)
+}
+
+func (h *producthandler) GetProduct(ctx context.Context, in *productv1.GetProductRequest) (*productv1.Product, error) {
+	var (
+		req = &entity.GetProductRequestById{ProductId: in.GetProductId(), Currency: in.GetCurrency()}
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request")
+		return nil, errmsg.Status(err, req).Err()
+	}
+
+	resp, err := h.service.GetProductById(ctx, req)
+	if err != nil {
+		return nil, errmsg.Status(err, req).Err()
+	}
+
+	return toProduct(resp), nil
+}
+
+func (h *producthandler) BatchGetProducts(ctx context.Context, in *productv1.BatchGetProductsRequest) (*productv1.BatchGetProductsResponse, error) {
+	var (
+		req = &entity.BatchGetProductsRequest{ProductIds: in.GetProductIds(), Currency: in.GetCurrency()}
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request")
+		return nil, errmsg.Status(err, req).Err()
+	}
+
+	resp, err := h.service.BatchGetProducts(ctx, req)
+	if err != nil {
+		return nil, errmsg.Status(err, req).Err()
+	}
+
+	products := make([]*productv1.Product, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		products = append(products, toProduct(item))
+	}
+
+	return &productv1.BatchGetProductsResponse{Products: products, MissingIds: resp.MissingIds}, nil
+}
+
+func (h *producthandler) ReserveStock(ctx context.Context, in *productv1.ReserveStockRequest) (*productv1.StockResponse, error) {
+	var (
+		req = &entity.ReserveStockRequest{OrderId: in.GetOrderId()}
+		v   = adapter.Adapters.Validator
+	)
+
+	for _, item := range in.GetItems() {
+		req.Items = append(req.Items, entity.StockItem{ProductId: item.GetProductId(), Quantity: int(item.GetQuantity())})
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request")
+		return nil, errmsg.Status(err, req).Err()
+	}
+
+	resp, err := h.service.ReserveStock(ctx, req)
+	if err != nil {
+		return nil, errmsg.Status(err, req).Err()
+	}
+
+	return toStockResponse(resp), nil
+}
+
+func (h *producthandler) ReleaseStock(ctx context.Context, in *productv1.ReleaseStockRequest) (*productv1.StockResponse, error) {
+	var (
+		req = &entity.ReleaseStockRequest{OrderId: in.GetOrderId(), ProductIds: in.GetProductIds()}
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request")
+		return nil, errmsg.Status(err, req).Err()
+	}
+
+	resp, err := h.service.ReleaseStock(ctx, req)
+	if err != nil {
+		return nil, errmsg.Status(err, req).Err()
+	}
+
+	return toStockResponse(resp), nil
+}
+
+func toProduct(p entity.GetProductResponseById) *productv1.Product {
+	product := &productv1.Product{
+		Id:              p.Id,
+		ShopId:          p.ShopId,
+		CategoryId:      p.CategoryId,
+		Category:        p.Category,
+		Name:            p.Name,
+		Description:     p.Description,
+		ImageUrl:        p.ImageUrl,
+		Price:           p.Price.String(),
+		Currency:        p.Currency,
+		OriginalPrice:   p.OriginalPrice.String(),
+		FinalPrice:      p.FinalPrice.String(),
+		DiscountPercent: p.DiscountPct.String(),
+		Stock:           int64(p.Stock),
+		Status:          p.Status,
+		BrandId:         p.BrandId,
+		Brand:           p.Brand,
+		CreatedAt:       timestamppb.New(p.CreatedAt),
+		UpdatedAt:       timestamppb.New(p.UpdatedAt),
+	}
+
+	if p.LowestPrice30d != nil {
+		lowest := p.LowestPrice30d.String()
+		product.LowestPrice_30D = &lowest
+	}
+
+	return product
+}
+
+func toStockResponse(res entity.StockResponse) *productv1.StockResponse {
+	items := make([]*productv1.StockLevel, 0, len(res.Items))
+	for _, item := range res.Items {
+		items = append(items, &productv1.StockLevel{
+			ProductId: item.ProductId,
+			Quantity:  int32(item.Quantity),
+			Stock:     int64(item.Stock),
+		})
+	}
+
+	return &productv1.StockResponse{OrderId: res.OrderId, Items: items}
+}
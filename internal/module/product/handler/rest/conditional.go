@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"encoding/json"
+	"product-service/internal/module/product/entity"
+	"product-service/pkg/httpcache"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// productNotModified tags the product by its encoded response, Last-Modified
+// is its modified_at.
+func productNotModified(c *fiber.Ctx, p entity.GetProductResponseById) bool {
+	return httpcache.NotModified(c, responseETag(p), modifiedAt(p.UpdatedAt, p.ModifiedAt))
+}
+
+// listingNotModified tags a page of products, Last-Modified is the newest
+// product on it. extra holds what else the response shows, like its facets.
+func listingNotModified(c *fiber.Ctx, items []entity.Product, extra ...any) bool {
+	var lastModified time.Time
+
+	for _, p := range items {
+		if at := modifiedAt(p.UpdatedAt, p.ModifiedAt); at.After(lastModified) {
+			lastModified = at
+		}
+	}
+
+	return httpcache.NotModified(c, responseETag(append([]any{items}, extra...)...), lastModified)
+}
+
+// modifiedAt is modified_at, or updated_at for a response read without it.
+func modifiedAt(updatedAt time.Time, modifiedAt *time.Time) time.Time {
+	if modifiedAt == nil {
+		return updatedAt
+	}
+
+	return *modifiedAt
+}
+
+// responseETag tags the values the way they are encoded in the response, it
+// is empty when one can't be encoded and the request is answered in full.
+func responseETag(values ...any) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		parts = append(parts, string(encoded))
+	}
+
+	return httpcache.ETag(parts...)
+}
@@ -9,6 +9,7 @@ import (
 	"product-service/internal/module/product/service"
 	"product-service/pkg/errmsg"
 	"product-service/pkg/response"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
@@ -20,7 +21,7 @@ type producthandler struct {
 
 func NewProductHandler() *producthandler {
 	repo := repository.NewProductRepository(adapter.Adapters.ShopeefunProductPostgres)
-	service := service.NewProductService(repo)
+	service := service.NewProductService(repo, adapter.Adapters.Cache)
 
 	return &producthandler{
 		service: service,
@@ -28,11 +29,23 @@ func NewProductHandler() *producthandler {
 }
 
 func (h *producthandler) Register(router fiber.Router) {
+	adminOnly := m.AuthRole([]string{"admin"})
+
 	router.Get("/products", h.getProducts)
+	router.Get("/products/recommended", m.AuthQueryParams, h.getRecommendedProducts)
+	router.Get("/products/moderation", m.AuthQueryParams, m.UserRole, adminOnly, h.getModerationQueue)
 	router.Post("/products", m.AuthQueryParams, h.createProduct)
+	router.Post("/products/quote", m.AuthQueryParams, h.quote)
+	router.Post("/products/batch", h.batchGetProducts)
 	router.Patch("/products/:id", m.AuthQueryParams, h.updateProduct)
 	router.Delete("/products/:id", m.AuthQueryParams, h.deleteProduct)
 	router.Get("/products/:id", h.getProductsById)
+	router.Get("/products/:id/related", h.getRelatedProducts)
+	router.Get("/products/:id/price-history", h.getPriceHistory)
+	router.Post("/products/:id/submit", m.AuthQueryParams, h.submitProduct)
+	router.Post("/products/:id/approve", m.AuthQueryParams, m.UserRole, adminOnly, h.approveProduct)
+	router.Post("/products/:id/reject", m.AuthQueryParams, m.UserRole, adminOnly, h.rejectProduct)
+	router.Get("/shops/:id/low-stock", m.AuthQueryParams, h.getLowStock)
 }
 
 func (h *producthandler) createProduct(c *fiber.Ctx) error {
@@ -132,6 +145,7 @@ func (h *producthandler) getProducts(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
+	req.AttributeFilters = attributeFilters(c)
 	req.SetDefaults()
 
 	if code, errs := req.CostumValidation(); code != 0 {
@@ -150,6 +164,39 @@ func (h *producthandler) getProducts(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	if listingNotModified(c, resp.Items, resp.Meta, resp.Facets) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) getRecommendedProducts(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetRecommendedProductsRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetRecommendedProducts(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
 
@@ -161,6 +208,8 @@ func (h *producthandler) getProductsById(c *fiber.Ctx) error {
 	)
 
 	req.ProductId = c.Params("id")
+	req.Currency = c.Query("currency")
+	req.UserId = c.Query("user_id")
 
 	if err := v.Validate(req); err != nil {
 		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
@@ -174,5 +223,283 @@ func (h *producthandler) getProductsById(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	if productNotModified(c, resp) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) getRelatedProducts(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetRelatedProductsRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.ProductId = c.Params("id")
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetRelatedProducts(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if listingNotModified(c, resp.Items) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) getPriceHistory(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetPriceHistoryRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.ProductId = c.Params("id")
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetPriceHistory(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+// attributeFilters collects attr[code]=value query params, QueryParser
+// doesn't map them onto the request struct.
+func attributeFilters(c *fiber.Ctx) map[string]string {
+	filters := make(map[string]string)
+
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		k := string(key)
+		if strings.HasPrefix(k, "attr[") && strings.HasSuffix(k, "]") {
+			filters[k[len("attr["):len(k)-1]] = string(value)
+		}
+	})
+
+	return filters
+}
+
+func (h *producthandler) quote(c *fiber.Ctx) error {
+	var (
+		req = &entity.QuoteRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.Quote(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) batchGetProducts(c *fiber.Ctx) error {
+	var (
+		req = &entity.BatchGetProductsRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.BatchGetProducts(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) submitProduct(c *fiber.Ctx) error {
+	var (
+		req = &entity.SubmitProductRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ProductId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.SubmitProduct(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) getModerationQueue(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetModerationQueueRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetModerationQueue(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) approveProduct(c *fiber.Ctx) error {
+	var (
+		req = &entity.ApproveProductRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ProductId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.ApproveProduct(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) rejectProduct(c *fiber.Ctx) error {
+	var (
+		req = &entity.RejectProductRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = c.Query("user_id")
+	req.ProductId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.RejectProduct(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) getLowStock(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetLowStockRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.ShopId = c.Params("id")
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetLowStock(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"product-service/internal/adapter"
+	"product-service/internal/module/product/ports"
+	"product-service/internal/module/product/repository"
+	"product-service/internal/module/product/service"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// publishScheduler periodically announces products going live or offline on
+// their publish_at / unpublish_at. Every replica runs one, the repository
+// makes sure each product is announced once.
+type publishScheduler struct {
+	service  ports.ProductService
+	interval time.Duration
+}
+
+func NewPublishScheduler(interval time.Duration) *publishScheduler {
+	repo := repository.NewProductRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewProductService(repo, adapter.Adapters.Cache)
+
+	return &publishScheduler{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is cancelled, a run in progress is rolled back.
+func (s *publishScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", s.interval).Msg("scheduler: Publish scheduler started")
+
+	for {
+		run, err := s.service.RunSchedule(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Error().Err(err).Msg("scheduler: Publish schedule run failed")
+		case run.Published > 0 || run.Unpublished > 0:
+			log.Info().Int("published", run.Published).Int("unpublished", run.Unpublished).Msg("scheduler: Announced scheduled products")
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("scheduler: Publish scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
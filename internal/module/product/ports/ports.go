@@ -3,24 +3,66 @@ package ports
 import (
 	"context"
 	"product-service/internal/module/product/entity"
+	"product-service/pkg/decimal"
 )
 
 type ProductService interface {
 	CreateProduct(ctx context.Context, req *entity.CreateProductRequest) (entity.UpsertProductResponse, error)
 	GetProducts(ctx context.Context, req *entity.GetProductsRequest) (entity.GetProductsResponse, error)
+	GetShopsProducts(ctx context.Context, shopIds []string, req *entity.GetProductsRequest) (map[string]entity.GetProductsResponse, error)
+	GetRecommendedProducts(ctx context.Context, req *entity.GetRecommendedProductsRequest) (entity.GetProductsResponse, error)
 	UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (entity.UpsertProductResponse, error)
 	DeleteProduct(ctx context.Context, req *entity.DeleteProductRequest) error
 	GetProductById(ctx context.Context, req *entity.GetProductRequestById) (entity.GetProductResponseById, error)
+	BatchGetProducts(ctx context.Context, req *entity.BatchGetProductsRequest) (entity.BatchGetProductsResponse, error)
+	GetCategoriesByIds(ctx context.Context, ids []string) ([]entity.Category, error)
+	GetRelatedProducts(ctx context.Context, req *entity.GetRelatedProductsRequest) (entity.GetRelatedProductsResponse, error)
+	Quote(ctx context.Context, req *entity.QuoteRequest) (entity.QuoteResponse, error)
+	GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (entity.GetPriceHistoryResponse, error)
+	GetLowStock(ctx context.Context, req *entity.GetLowStockRequest) (entity.GetLowStockResponse, error)
+
+	SubmitProduct(ctx context.Context, req *entity.SubmitProductRequest) (entity.ModerationResponse, error)
+	GetModerationQueue(ctx context.Context, req *entity.GetModerationQueueRequest) (entity.GetModerationQueueResponse, error)
+	ApproveProduct(ctx context.Context, req *entity.ApproveProductRequest) (entity.ModerationResponse, error)
+	RejectProduct(ctx context.Context, req *entity.RejectProductRequest) (entity.ModerationResponse, error)
+
+	ReserveStock(ctx context.Context, req *entity.ReserveStockRequest) (entity.StockResponse, error)
+	ReleaseStock(ctx context.Context, req *entity.ReleaseStockRequest) (entity.StockResponse, error)
+
+	RunSchedule(ctx context.Context) (entity.ScheduleRun, error)
 }
 
 type ProductRepository interface {
 	CreateProduct(ctx context.Context, req *entity.CreateProductRequest) (entity.UpsertProductResponse, error)
 	GetProducts(ctx context.Context, req *entity.GetProductsRequest) (entity.GetProductsResponse, error)
+	GetShopsProducts(ctx context.Context, shopIds []string, req *entity.GetProductsRequest) (map[string]entity.GetProductsResponse, error)
+	GetProductFacets(ctx context.Context, req *entity.GetProductsRequest) (entity.Facets, error)
+	GetRecommendedProducts(ctx context.Context, req *entity.GetRecommendedProductsRequest) (entity.GetProductsResponse, error)
 	UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (entity.UpsertProductResponse, error)
 	DeleteProduct(ctx context.Context, req *entity.DeleteProductRequest) error
 
 	IsShopOwner(ctx context.Context, userId, shopId string) (bool, error)
 	IsProductOwner(ctx context.Context, userId, productId string) (bool, error)
+	IsBrandExist(ctx context.Context, brandId string) (bool, error)
+	IsCurrencyExist(ctx context.Context, currency string) (bool, error)
+	GetUnconvertibleCurrencies(ctx context.Context, currency string, ids []string) ([]string, error)
+	GetAttributeDefinitions(ctx context.Context, categoryId string) ([]entity.AttributeDefinition, error)
 
 	GetProductById(ctx context.Context, req *entity.GetProductRequestById) (entity.GetProductResponseById, error)
+	GetProductsByIds(ctx context.Context, ids []string, currency string) ([]entity.GetProductResponseById, error)
+	GetCategoriesByIds(ctx context.Context, ids []string) ([]entity.Category, error)
+	GetRelatedProducts(ctx context.Context, req *entity.GetRelatedProductsRequest) (entity.GetRelatedProductsResponse, error)
+	GetQuoteSnapshot(ctx context.Context, productIds []string, currency string) (entity.QuoteSnapshot, error)
+	GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (entity.GetPriceHistoryResponse, error)
+	GetLowestPrice30d(ctx context.Context, productId, currency string) (*decimal.Decimal, error)
+	GetLowStock(ctx context.Context, req *entity.GetLowStockRequest) (entity.GetLowStockResponse, error)
+
+	SubmitProduct(ctx context.Context, req *entity.SubmitProductRequest) (entity.ModerationResponse, error)
+	ReviewProduct(ctx context.Context, req *entity.ReviewProduct) (entity.ModerationResponse, error)
+	GetModerationQueue(ctx context.Context, req *entity.GetModerationQueueRequest) (entity.GetModerationQueueResponse, error)
+
+	ReserveStock(ctx context.Context, req *entity.ReserveStockRequest) (entity.StockResponse, error)
+	ReleaseStock(ctx context.Context, req *entity.ReleaseStockRequest) (entity.StockResponse, error)
+
+	EmitScheduledEvents(ctx context.Context, limit int) (entity.ScheduleRun, error)
 }
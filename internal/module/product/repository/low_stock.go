@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	outboxEntity "product-service/internal/module/outbox/entity"
+	outboxRepository "product-service/internal/module/outbox/repository"
+	"product-service/internal/module/product/entity"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// lowStockState is a product as SyncLowStock decides on its alert.
+type lowStockState struct {
+	Id        string `db:"id"`
+	Stock     int    `db:"stock"`
+	Threshold *int   `db:"low_stock_threshold"`
+	Alerted   bool   `db:"alerted"`
+}
+
+// nextLowStock returns whether the product's alert is raised after the
+// change and whether raising it must be announced. A product without a
+// threshold is never low.
+func nextLowStock(state lowStockState, rearm bool) (alerted, announce bool) {
+	low := state.Threshold != nil && state.Stock <= *state.Threshold
+
+	alerted = state.Alerted
+	if rearm && !low {
+		alerted = false
+	}
+
+	if low && !alerted {
+		return true, true
+	}
+
+	return alerted, false
+}
+
+// SyncLowStock raises a low stock alert for the products whose stock is at or
+// below their threshold and queues product.low_stock for each, in the
+// transaction changing the stock. An alert is raised once and stays raised
+// until the seller restocks above the threshold, which rearm clears, so
+// orders and cancellations moving the stock around the threshold do not
+// raise it again. Only the seller's own stock updates should rearm.
+func SyncLowStock(ctx context.Context, tx sqlx.ExtContext, productIds []string, rearm bool) error {
+	return syncLowStock(ctx, txLowStock{tx: tx}, productIds, rearm)
+}
+
+// lowStockStore reads and writes the alerts of SyncLowStock, txLowStock in
+// the transaction changing the stock.
+type lowStockStore interface {
+	lock(ctx context.Context, productIds []string) ([]lowStockState, error)
+	clear(ctx context.Context, productIds []string) error
+	raise(ctx context.Context, productIds []string) ([]entity.LowStockEvent, error)
+	enqueue(ctx context.Context, event outboxEntity.Event) error
+}
+
+func syncLowStock(ctx context.Context, store lowStockStore, productIds []string, rearm bool) error {
+	states, err := store.lock(ctx, productIds)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cleared = make([]string, 0)
+		raised  = make([]string, 0)
+	)
+
+	for _, state := range states {
+		alerted, announce := nextLowStock(state, rearm)
+		switch {
+		case announce:
+			raised = append(raised, state.Id)
+		case state.Alerted && !alerted:
+			cleared = append(cleared, state.Id)
+		}
+	}
+
+	if len(cleared) > 0 {
+		if err := store.clear(ctx, cleared); err != nil {
+			return err
+		}
+	}
+
+	if len(raised) == 0 {
+		return nil
+	}
+
+	events, err := store.raise(ctx, raised)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		err := store.enqueue(ctx, outboxEntity.Event{
+			AggregateType: outboxEntity.AggregateProduct,
+			AggregateId:   event.ProductId,
+			EventType:     outboxEntity.EventProductLowStock,
+			ShopId:        event.ShopId,
+			Payload:       event,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type txLowStock struct {
+	tx sqlx.ExtContext
+}
+
+// lock reads the products' alerts. The row lock makes a concurrent writer
+// wait and read the alert this one raised, so a crossing is announced by
+// exactly one of them.
+func (s txLowStock) lock(ctx context.Context, productIds []string) ([]lowStockState, error) {
+	query := `
+		SELECT
+			id,
+			stock,
+			low_stock_threshold,
+			low_stock_alerted_at IS NOT NULL AS alerted
+		FROM
+			products
+		WHERE
+			id = ANY($1::uuid[])
+			AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`
+
+	states := make([]lowStockState, 0, len(productIds))
+
+	if err := sqlx.SelectContext(ctx, s.tx, &states, query, pq.Array(productIds)); err != nil {
+		log.Error().Err(err).Any("payload", productIds).Msg("repository: SyncLowStock failed to lock products")
+		return nil, err
+	}
+
+	return states, nil
+}
+
+func (s txLowStock) clear(ctx context.Context, productIds []string) error {
+	query := `UPDATE products SET low_stock_alerted_at = NULL WHERE id = ANY($1::uuid[])`
+
+	if _, err := s.tx.ExecContext(ctx, query, pq.Array(productIds)); err != nil {
+		log.Error().Err(err).Any("payload", productIds).Msg("repository: SyncLowStock failed to clear alerts")
+		return err
+	}
+
+	return nil
+}
+
+func (s txLowStock) raise(ctx context.Context, productIds []string) ([]entity.LowStockEvent, error) {
+	query := `
+		UPDATE
+			products p
+		SET
+			low_stock_alerted_at = NOW()
+		FROM
+			shops s
+		WHERE
+			p.id = ANY($1::uuid[])
+			AND s.id = p.shop_id
+		RETURNING
+			p.id AS product_id,
+			p.shop_id,
+			s.user_id AS seller_id,
+			p.name,
+			p.stock,
+			p.low_stock_threshold AS threshold,
+			p.low_stock_alerted_at AS alerted_at
+	`
+
+	events := make([]entity.LowStockEvent, 0, len(productIds))
+
+	if err := sqlx.SelectContext(ctx, s.tx, &events, query, pq.Array(productIds)); err != nil {
+		log.Error().Err(err).Any("payload", productIds).Msg("repository: SyncLowStock failed to raise alerts")
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s txLowStock) enqueue(ctx context.Context, event outboxEntity.Event) error {
+	return outboxRepository.Enqueue(ctx, s.tx, event)
+}
+
+// GetLowStock lists the shop's products at or below their threshold, the
+// emptiest first.
+func (p *productRepository) GetLowStock(ctx context.Context, req *entity.GetLowStockRequest) (entity.GetLowStockResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.LowStockItem
+	}
+	var (
+		res  entity.GetLowStockResponse
+		data = make([]dao, 0)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.LowStockItem, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			id,
+			name,
+			image_url,
+			status,
+			stock,
+			low_stock_threshold,
+			low_stock_alerted_at,
+			updated_at
+		FROM
+			products
+		WHERE
+			shop_id = $1
+			AND low_stock_threshold IS NOT NULL
+			AND stock <= low_stock_threshold
+			AND deleted_at IS NULL
+		ORDER BY stock ASC, name ASC
+		LIMIT $2
+		OFFSET $3
+	`
+
+	err := p.db.SelectContext(ctx, &data, query, req.ShopId, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetLowStock failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.LowStockItem)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	outboxEntity "product-service/internal/module/outbox/entity"
+	"product-service/internal/module/product/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stockChange is a write moving the stock, rearm is set for the seller's own
+// stock updates.
+type stockChange struct {
+	stock int
+	rearm bool
+}
+
+// announcements applies the changes in order like SyncLowStock and returns
+// the stock at each announced alert.
+func announcements(threshold *int, changes []stockChange) []int {
+	var (
+		state = lowStockState{Id: "p", Threshold: threshold}
+		res   = make([]int, 0)
+	)
+
+	for _, change := range changes {
+		state.Stock = change.stock
+
+		alerted, announce := nextLowStock(state, change.rearm)
+		if announce {
+			res = append(res, change.stock)
+		}
+		state.Alerted = alerted
+	}
+
+	return res
+}
+
+func TestLowStockAnnouncedOncePerCrossing(t *testing.T) {
+	threshold := 5
+
+	// orders and cancellations moving around the threshold don't repeat it
+	changes := []stockChange{
+		{stock: 8}, {stock: 5}, {stock: 3}, {stock: 6}, {stock: 4}, {stock: 0}, {stock: 7},
+	}
+
+	assert.Equal(t, []int{5}, announcements(&threshold, changes))
+}
+
+func TestLowStockRearmedBySellerRestock(t *testing.T) {
+	threshold := 5
+
+	changes := []stockChange{
+		{stock: 4},
+		// a restock still at the threshold keeps the alert
+		{stock: 5, rearm: true},
+		// one above it rearms, the next crossing is announced again
+		{stock: 20, rearm: true},
+		{stock: 12},
+		{stock: 2},
+		{stock: 1},
+	}
+
+	assert.Equal(t, []int{4, 2}, announcements(&threshold, changes))
+}
+
+func TestLowStockRearmIgnoredForOrders(t *testing.T) {
+	threshold := 5
+
+	// a cancellation lifting the stock isn't a restock and doesn't rearm
+	changes := []stockChange{
+		{stock: 3}, {stock: 9}, {stock: 2},
+	}
+
+	assert.Equal(t, []int{3}, announcements(&threshold, changes))
+}
+
+func TestLowStockWithoutThreshold(t *testing.T) {
+	assert.Empty(t, announcements(nil, []stockChange{{stock: 0}, {stock: 0, rearm: true}}))
+
+	// dropping the threshold clears a raised alert on the next restock
+	alerted, announce := nextLowStock(lowStockState{Stock: 0, Alerted: true}, true)
+	assert.False(t, alerted)
+	assert.False(t, announce)
+}
+
+// fakeProduct is a products row as SyncLowStock reads and writes it.
+type fakeProduct struct {
+	stock     int
+	threshold *int
+	alerted   bool
+	deleted   bool
+}
+
+// fakeLowStock keeps the products and the outbox in memory with the rules of
+// the SQL store: deleted products aren't locked, raising an alert returns the
+// payload of its event.
+type fakeLowStock struct {
+	products map[string]*fakeProduct
+	outbox   []outboxEntity.Event
+	fail     error
+}
+
+func (f *fakeLowStock) lock(ctx context.Context, productIds []string) ([]lowStockState, error) {
+	states := make([]lowStockState, 0, len(productIds))
+	for _, id := range productIds {
+		if p, ok := f.products[id]; ok && !p.deleted {
+			states = append(states, lowStockState{Id: id, Stock: p.stock, Threshold: p.threshold, Alerted: p.alerted})
+		}
+	}
+	return states, nil
+}
+
+func (f *fakeLowStock) clear(ctx context.Context, productIds []string) error {
+	for _, id := range productIds {
+		f.products[id].alerted = false
+	}
+	return nil
+}
+
+func (f *fakeLowStock) raise(ctx context.Context, productIds []string) ([]entity.LowStockEvent, error) {
+	events := make([]entity.LowStockEvent, 0, len(productIds))
+	for _, id := range productIds {
+		p := f.products[id]
+		p.alerted = true
+		events = append(events, entity.LowStockEvent{ProductId: id, ShopId: "shop", Stock: p.stock, Threshold: *p.threshold})
+	}
+	return events, nil
+}
+
+func (f *fakeLowStock) enqueue(ctx context.Context, event outboxEntity.Event) error {
+	if f.fail != nil {
+		return f.fail
+	}
+
+	f.outbox = append(f.outbox, event)
+	return nil
+}
+
+// set moves the stock of the product and syncs it like the write would.
+func (f *fakeLowStock) set(t *testing.T, id string, stock int, rearm bool) {
+	t.Helper()
+
+	f.products[id].stock = stock
+	assert.NoError(t, syncLowStock(context.Background(), f, []string{id}, rearm))
+}
+
+// queued returns the payloads of the queued events.
+func (f *fakeLowStock) queued() []entity.LowStockEvent {
+	res := make([]entity.LowStockEvent, 0, len(f.outbox))
+	for _, event := range f.outbox {
+		res = append(res, event.Payload.(entity.LowStockEvent))
+	}
+	return res
+}
+
+func TestSyncLowStock(t *testing.T) {
+	threshold := 5
+
+	t.Run("queues one event per crossing while the stock hovers", func(t *testing.T) {
+		f := &fakeLowStock{products: map[string]*fakeProduct{"p": {stock: 10, threshold: &threshold}}}
+
+		f.set(t, "p", 4, false)
+		f.set(t, "p", 6, false)
+		f.set(t, "p", 3, false)
+		f.set(t, "p", 0, false)
+
+		assert.Len(t, f.outbox, 1)
+		assert.Equal(t, outboxEntity.AggregateProduct, f.outbox[0].AggregateType)
+		assert.Equal(t, outboxEntity.EventProductLowStock, f.outbox[0].EventType)
+		assert.Equal(t, "p", f.outbox[0].AggregateId)
+		assert.Equal(t, "shop", f.outbox[0].ShopId)
+		assert.Equal(t, 4, f.queued()[0].Stock)
+		assert.True(t, f.products["p"].alerted)
+	})
+
+	t.Run("queues the next crossing after a seller restock", func(t *testing.T) {
+		f := &fakeLowStock{products: map[string]*fakeProduct{"p": {stock: 10, threshold: &threshold}}}
+
+		f.set(t, "p", 2, false)
+		f.set(t, "p", 20, true)
+		assert.False(t, f.products["p"].alerted)
+
+		f.set(t, "p", 5, false)
+
+		assert.Len(t, f.outbox, 2)
+		assert.Equal(t, []int{2, 5}, []int{f.queued()[0].Stock, f.queued()[1].Stock})
+	})
+
+	t.Run("announces only the products of a reservation that crossed", func(t *testing.T) {
+		f := &fakeLowStock{products: map[string]*fakeProduct{
+			"low":     {stock: 1, threshold: &threshold},
+			"plenty":  {stock: 50, threshold: &threshold},
+			"alerted": {stock: 2, threshold: &threshold, alerted: true},
+			"none":    {stock: 0},
+			"deleted": {stock: 0, threshold: &threshold, deleted: true},
+		}}
+
+		err := syncLowStock(context.Background(), f, []string{"low", "plenty", "alerted", "none", "deleted"}, false)
+		assert.NoError(t, err)
+
+		assert.Len(t, f.outbox, 1)
+		assert.Equal(t, "low", f.outbox[0].AggregateId)
+		assert.False(t, f.products["deleted"].alerted)
+	})
+
+	t.Run("fails the write when the event can't be queued", func(t *testing.T) {
+		failure := errors.New("outbox is down")
+		f := &fakeLowStock{products: map[string]*fakeProduct{"p": {stock: 0, threshold: &threshold}}, fail: failure}
+
+		err := syncLowStock(context.Background(), f, []string{"p"}, false)
+		assert.ErrorIs(t, err, failure)
+	})
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	outboxEntity "product-service/internal/module/outbox/entity"
+	outboxRepository "product-service/internal/module/outbox/repository"
+	"product-service/internal/module/product/entity"
+	"product-service/pkg/errmsg"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// Published is the condition for the product aliased as table to be shown
+// to the public: approved and inside its publishing schedule.
+func Published(table string) string {
+	return fmt.Sprintf(`(
+		%[1]s.status = 'published'
+		AND (%[1]s.publish_at IS NULL OR %[1]s.publish_at <= NOW())
+		AND (%[1]s.unpublish_at IS NULL OR %[1]s.unpublish_at > NOW())
+	)`, table)
+}
+
+const moderationColumns = `
+	products.id,
+	products.shop_id,
+	shops.user_id AS seller_id,
+	products.name,
+	products.status,
+	products.rejection_reason,
+	products.submitted_at,
+	products.reviewed_by,
+	products.reviewed_at
+`
+
+// SubmitProduct queues a draft or rejected product for review and queues the
+// submission event in the same transaction.
+func (p *productRepository) SubmitProduct(ctx context.Context, req *entity.SubmitProductRequest) (entity.ModerationResponse, error) {
+	var res entity.ModerationResponse
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: SubmitProduct failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	var previousStatus string
+
+	err = tx.GetContext(ctx, &previousStatus, `SELECT status FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, req.ProductId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error().Err(err).Any("payload", req).Msg("repository: SubmitProduct failed")
+		return res, err
+	}
+
+	query := `
+		UPDATE
+			products
+		SET
+			status = 'pending_review',
+			submitted_at = NOW(),
+			updated_at = NOW()
+		FROM
+			shops
+		WHERE
+			products.id = $1
+			AND shops.id = products.shop_id
+			AND products.deleted_at IS NULL
+			AND products.status IN ('draft', 'rejected')
+		RETURNING
+	` + moderationColumns
+
+	err = tx.QueryRowxContext(ctx, query, req.ProductId).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Product is not a draft or rejected")
+			return res, errmsg.NewCostumErrors(409, errmsg.WithMessage("Only draft or rejected products can be submitted for review"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: SubmitProduct failed")
+		return res, err
+	}
+
+	err = enqueueSubmission(ctx, tx, entity.SubmissionEvent{
+		ProductId:      res.Id,
+		ShopId:         res.ShopId,
+		SellerId:       res.SellerId,
+		Name:           res.Name,
+		Status:         res.Status,
+		PreviousStatus: previousStatus,
+		SubmittedAt:    *res.SubmittedAt,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: SubmitProduct failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// enqueueSubmission queues the event of a product entering the review queue.
+func enqueueSubmission(ctx context.Context, tx sqlx.ExtContext, event entity.SubmissionEvent) error {
+	return outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+		AggregateType: outboxEntity.AggregateProduct,
+		AggregateId:   event.ProductId,
+		EventType:     outboxEntity.EventProductSubmitted,
+		ShopId:        event.ShopId,
+		Payload:       event,
+	})
+}
+
+// ReviewProduct publishes or rejects a product waiting for review and queues
+// the event notifying the seller in the same transaction.
+func (p *productRepository) ReviewProduct(ctx context.Context, req *entity.ReviewProduct) (entity.ModerationResponse, error) {
+	var res entity.ModerationResponse
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReviewProduct failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE
+			products
+		SET
+			status = $2,
+			rejection_reason = $3,
+			reviewed_by = $4,
+			reviewed_at = NOW(),
+			updated_at = NOW()
+		FROM
+			shops
+		WHERE
+			products.id = $1
+			AND shops.id = products.shop_id
+			AND products.deleted_at IS NULL
+			AND products.status = 'pending_review'
+		RETURNING
+	` + moderationColumns
+
+	err = tx.QueryRowxContext(ctx, query, req.ProductId, req.Status, req.Reason, req.ReviewerId).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, p.reviewNotFound(ctx, req)
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReviewProduct failed")
+		return res, err
+	}
+
+	eventType := outboxEntity.EventProductApproved
+	if res.Status == entity.StatusRejected {
+		eventType = outboxEntity.EventProductRejected
+	}
+
+	err = outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+		AggregateType: outboxEntity.AggregateProduct,
+		AggregateId:   res.Id,
+		EventType:     eventType,
+		ShopId:        res.ShopId,
+		Payload: entity.ModerationEvent{
+			ProductId:  res.Id,
+			ShopId:     res.ShopId,
+			SellerId:   res.SellerId,
+			Name:       res.Name,
+			Status:     res.Status,
+			Reason:     res.RejectionReason,
+			ReviewedAt: *res.ReviewedAt,
+		},
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReviewProduct failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// reviewNotFound tells a missing product apart from one that isn't waiting
+// for review.
+func (p *productRepository) reviewNotFound(ctx context.Context, req *entity.ReviewProduct) error {
+	var exist bool
+
+	query := `SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)`
+
+	if err := p.db.GetContext(ctx, &exist, query, req.ProductId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReviewProduct failed")
+		return err
+	}
+
+	if !exist {
+		log.Warn().Any("payload", req).Msg("repository: Product not found")
+		return errmsg.NewCostumErrors(404, errmsg.WithMessage("Product not found"))
+	}
+
+	log.Warn().Any("payload", req).Msg("repository: Product is not pending review")
+	return errmsg.NewCostumErrors(409, errmsg.WithMessage("Product is not pending review"))
+}
+
+// GetModerationQueue lists products waiting for review, longest waiting first.
+func (p *productRepository) GetModerationQueue(ctx context.Context, req *entity.GetModerationQueueRequest) (entity.GetModerationQueueResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.ModerationQueueItem
+	}
+	var (
+		res  entity.GetModerationQueueResponse
+		data = make([]dao, 0)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.ModerationQueueItem, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			p.id,
+			p.shop_id,
+			s.user_id AS seller_id,
+			p.category_id,
+			p.name,
+			p.description,
+			p.image_url,
+			p.price,
+			p.currency,
+			p.brand,
+			p.attributes,
+			p.submitted_at,
+			p.created_at
+		FROM
+			products p
+		JOIN
+			shops s ON s.id = p.shop_id
+		WHERE
+			p.status = 'pending_review'
+			AND p.deleted_at IS NULL
+		ORDER BY p.submitted_at ASC
+		LIMIT $1
+		OFFSET $2
+	`
+
+	err := p.db.SelectContext(ctx, &data, query, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetModerationQueue failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.ModerationQueueItem)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
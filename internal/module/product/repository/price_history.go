@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"product-service/internal/module/product/entity"
+	"product-service/pkg/decimal"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// offersCTE lists the offers that apply to the products in $1 from now on,
+// each with the span it gives the price in. The span is derived from
+// DiscountJoin evaluated at now and at every start and end of a discount or
+// campaign ahead, so an offer sold out, outbid or shadowed by a later
+// discount is only listed for the time it actually applies.
+var offersCTE = `
+	WITH instants AS (
+		SELECT id AS product_id, NOW() AS at FROM products WHERE id = ANY($1::uuid[])
+		UNION
+		SELECT pd.product_id, edge.at
+		FROM
+			product_discounts pd
+		CROSS JOIN LATERAL (VALUES (pd.starts_at), (pd.ends_at)) edge(at)
+		WHERE
+			pd.product_id = ANY($1::uuid[])
+			AND pd.deleted_at IS NULL
+			AND edge.at > NOW()
+		UNION
+		SELECT cp.product_id, edge.at
+		FROM
+			campaign_products cp
+		JOIN
+			campaigns c ON c.id = cp.campaign_id
+		CROSS JOIN LATERAL (VALUES (c.starts_at), (c.ends_at)) edge(at)
+		WHERE
+			cp.product_id = ANY($1::uuid[])
+			AND c.deleted_at IS NULL
+			AND edge.at > NOW()
+	),
+	segments AS (
+		SELECT
+			instants.product_id,
+			instants.at,
+			LEAD(instants.at) OVER (PARTITION BY instants.product_id ORDER BY instants.at) AS next_at,
+			discount.final_price,
+			discount.offer_source,
+			discount.offer_id
+		FROM
+			instants
+		JOIN
+			products ON products.id = instants.product_id
+		` + offerJoinAt("products", "instants.at") + `
+	),
+	islands AS (
+		SELECT
+			*,
+			COUNT(*) FILTER (WHERE changed) OVER (PARTITION BY product_id ORDER BY at) AS island
+		FROM (
+			SELECT
+				*,
+				(offer_id, final_price) IS DISTINCT FROM (LAG(offer_id) OVER w, LAG(final_price) OVER w) AS changed
+			FROM
+				segments
+			WINDOW w AS (PARTITION BY product_id ORDER BY at)
+		) marked
+	),
+	offers AS (
+		SELECT
+			offer_source AS source,
+			offer_id AS reference_id,
+			product_id,
+			final_price AS price,
+			MIN(at) AS effective_from,
+			MAX(next_at) AS effective_to
+		FROM
+			islands
+		WHERE
+			offer_id IS NOT NULL
+		GROUP BY product_id, island, offer_source, offer_id, final_price
+	)
+`
+
+// RecordRegularPrice closes the open regular price of the product and opens
+// one at its current price. Nothing is written when the price didn't change.
+// It must run in the transaction that changed the price.
+func RecordRegularPrice(ctx context.Context, tx sqlx.ExtContext, productId string) error {
+	closeQuery := `
+		UPDATE
+			price_history ph
+		SET
+			effective_to = NOW()
+		FROM
+			products p
+		WHERE
+			p.id = $1
+			AND ph.product_id = p.id
+			AND ph.source = 'product'
+			AND ph.effective_to IS NULL
+			AND ph.price <> p.price
+	`
+
+	_, err := tx.ExecContext(ctx, closeQuery, productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: RecordRegularPrice failed to close price")
+		return err
+	}
+
+	openQuery := `
+		INSERT INTO
+			price_history (product_id, price, source, effective_from)
+		SELECT
+			id,
+			price,
+			'product',
+			NOW()
+		FROM
+			products
+		WHERE
+			id = $1
+			AND NOT EXISTS (
+				SELECT 1
+				FROM
+					price_history
+				WHERE
+					product_id = $1
+					AND source = 'product'
+					AND effective_to IS NULL
+			)
+	`
+
+	_, err = tx.ExecContext(ctx, openQuery, productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: RecordRegularPrice failed to open price")
+		return err
+	}
+
+	return nil
+}
+
+// SyncOfferPrices brings the promotional prices in the history in line with
+// the discounts and campaigns of the products. Offers that were removed or
+// repriced are closed now, new or repriced ones are opened from the later of
+// their start and now. It must run in the transaction that made the change.
+func SyncOfferPrices(ctx context.Context, tx sqlx.ExtContext, productIds []string) error {
+	closeQuery := offersCTE + `
+		UPDATE
+			price_history ph
+		SET
+			effective_to = GREATEST(ph.effective_from, NOW())
+		WHERE
+			ph.product_id = ANY($1::uuid[])
+			AND ph.source IN ('discount', 'campaign')
+			AND ph.effective_to > NOW()
+			AND NOT EXISTS (
+				SELECT 1
+				FROM
+					offers o
+				WHERE
+					o.source = ph.source
+					AND o.reference_id = ph.reference_id
+					AND o.product_id = ph.product_id
+					AND o.price = ph.price
+					AND o.effective_to = ph.effective_to
+					AND (o.effective_from <= NOW() OR o.effective_from = ph.effective_from)
+			)
+	`
+
+	_, err := tx.ExecContext(ctx, closeQuery, pq.Array(productIds))
+	if err != nil {
+		log.Error().Err(err).Any("payload", productIds).Msg("repository: SyncOfferPrices failed to close offers")
+		return err
+	}
+
+	openQuery := offersCTE + `
+		INSERT INTO
+			price_history (product_id, price, source, reference_id, effective_from, effective_to)
+		SELECT
+			o.product_id,
+			o.price,
+			o.source,
+			o.reference_id,
+			GREATEST(o.effective_from, NOW()),
+			o.effective_to
+		FROM
+			offers o
+		WHERE
+			NOT EXISTS (
+				SELECT 1
+				FROM
+					price_history ph
+				WHERE
+					ph.source = o.source
+					AND ph.reference_id = o.reference_id
+					AND ph.product_id = o.product_id
+					AND ph.price = o.price
+					AND ph.effective_to = o.effective_to
+					AND (o.effective_from <= NOW() OR o.effective_from = ph.effective_from)
+			)
+	`
+
+	_, err = tx.ExecContext(ctx, openQuery, pq.Array(productIds))
+	if err != nil {
+		log.Error().Err(err).Any("payload", productIds).Msg("repository: SyncOfferPrices failed to open offers")
+		return err
+	}
+
+	return nil
+}
+
+func (p *productRepository) GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (entity.GetPriceHistoryResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.PriceHistoryEntry
+	}
+	var (
+		res  entity.GetPriceHistoryResponse
+		data = make([]dao, 0)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.PriceHistoryEntry, 0)
+
+	// zero length entries are offers withdrawn before they started
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			price,
+			source,
+			reference_id,
+			effective_from,
+			effective_to
+		FROM
+			price_history
+		WHERE
+			product_id = $1
+			AND effective_from <= NOW()
+			AND (effective_to IS NULL OR effective_to > effective_from)
+		ORDER BY effective_from DESC, created_at DESC
+		LIMIT $2
+		OFFSET $3
+	`
+
+	err := p.db.SelectContext(ctx, &data, query, req.ProductId, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetPriceHistory failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.PriceHistoryEntry)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+// LowestPriceJoin joins the lowest price of the product aliased as table in
+// the 30 days before its running promotion started, or before now when
+// nothing is on promotion. It is NULL for products without a history.
+func LowestPriceJoin(table string) string {
+	return fmt.Sprintf(`
+		LEFT JOIN LATERAL (
+			SELECT
+				MIN(ph.price) AS price
+			FROM
+				price_history ph
+			CROSS JOIN (
+				SELECT
+					COALESCE(MIN(effective_from), NOW()) AS at
+				FROM
+					price_history
+				WHERE
+					product_id = %[1]s.id
+					AND source IN ('discount', 'campaign')
+					AND effective_from <= NOW()
+					AND effective_to > NOW()
+			) anchor
+			WHERE
+				ph.product_id = %[1]s.id
+				AND ph.effective_from < anchor.at
+				AND (ph.effective_to IS NULL OR ph.effective_to > anchor.at - INTERVAL '30 days')
+				AND (ph.effective_to IS NULL OR ph.effective_to > ph.effective_from)
+		) lowest ON TRUE
+	`, table)
+}
+
+// GetLowestPrice30d returns the lowest price of the 30 days before the
+// running promotion started, or before now when nothing is on promotion.
+// The promotion itself is left out, that's the price being compared against.
+// The price is converted like ConvertedPriceColumns converts it.
+func (p *productRepository) GetLowestPrice30d(ctx context.Context, productId, currency string) (*decimal.Decimal, error) {
+	var lowest *decimal.Decimal
+
+	query := `
+		SELECT
+			COALESCE(ROUND(lowest.price * fx.rate, fx.scale), lowest.price)
+		FROM
+			products
+		` + LowestPriceJoin("products") + FxJoin("products", "$2") + `
+		WHERE
+			products.id = $1
+	`
+
+	err := p.db.GetContext(ctx, &lowest, query, productId, currency)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: GetLowestPrice30d failed")
+		return lowest, err
+	}
+
+	return lowest, nil
+}
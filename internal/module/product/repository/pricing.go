@@ -0,0 +1,167 @@
+package repository
+
+import "fmt"
+
+// EffectivePrice is the price a buyer pays right now, queries using it must
+// include DiscountJoin("products").
+const EffectivePrice = "COALESCE(discount.final_price, products.price)"
+
+// ConvertedEffectivePrice is EffectivePrice in the currency FxJoin converts
+// to, or in the product's own currency when there is nothing to convert to.
+// It is left unrounded for filtering and sorting.
+const ConvertedEffectivePrice = "COALESCE(" + EffectivePrice + " * fx.rate, " + EffectivePrice + ")"
+
+// DiscountJoin joins the lowest price currently offered for the product
+// aliased as table: its active discount (inside its schedule and until its
+// stock cap is sold out) or a live flash sale campaign with quota left. The
+// offer giving the price is joined along with it as offer_source, offer_id
+// and offer_ends_at, all NULL when nothing is offered.
+func DiscountJoin(table string) string {
+	return offerJoinAt(table, "NOW()")
+}
+
+// offerJoinAt is DiscountJoin as it prices the product at the instant at,
+// with stock caps and quotas as they stand now.
+func offerJoinAt(table, at string) string {
+	return fmt.Sprintf(`
+		LEFT JOIN LATERAL (
+			SELECT
+				offers.final_price,
+				offers.source AS offer_source,
+				offers.id AS offer_id,
+				offers.ends_at AS offer_ends_at
+			FROM (
+				(
+					SELECT
+						CASE
+							WHEN pd.type = 'percentage' THEN ROUND(%[1]s.price * (100 - pd.value) / 100, 4)
+							ELSE GREATEST(%[1]s.price - pd.value, 0)
+						END AS final_price,
+						'discount' AS source,
+						pd.id,
+						pd.ends_at
+					FROM
+						product_discounts pd
+					WHERE
+						pd.product_id = %[1]s.id
+						AND pd.deleted_at IS NULL
+						AND pd.starts_at <= %[2]s
+						AND pd.ends_at > %[2]s
+						AND (pd.stock_cap IS NULL OR pd.sold_count < pd.stock_cap)
+					ORDER BY pd.starts_at DESC
+					LIMIT 1
+				)
+				UNION ALL
+				(
+					SELECT
+						cp.campaign_price AS final_price,
+						'campaign' AS source,
+						c.id,
+						c.ends_at
+					FROM
+						campaign_products cp
+					JOIN
+						campaigns c ON c.id = cp.campaign_id
+					WHERE
+						cp.product_id = %[1]s.id
+						AND cp.sold_count < cp.quota
+						AND c.deleted_at IS NULL
+						AND c.starts_at <= %[2]s
+						AND c.ends_at > %[2]s
+				)
+			) offers
+			ORDER BY offers.final_price, offers.source
+			LIMIT 1
+		) discount ON TRUE
+	`, table, at)
+}
+
+// PriceColumns selects original_price, final_price and discount_percent of
+// the product aliased as table, computed from DiscountJoin.
+func PriceColumns(table string) string {
+	return fmt.Sprintf(`
+		%[1]s.price AS original_price,
+		COALESCE(discount.final_price, %[1]s.price) AS final_price,
+		CASE
+			WHEN %[1]s.price > 0 THEN ROUND((%[1]s.price - COALESCE(discount.final_price, %[1]s.price)) / %[1]s.price * 100, 2)
+			ELSE 0
+		END AS discount_percent
+	`, table)
+}
+
+// FxJoin joins the rate converting the product aliased as table into the
+// currency bound to param, along with that currency's code and scale and
+// the time the rate was set. Pairs
+// are looked up in either direction, when the currency is empty, unknown or
+// has no rate against the product's currency every column is NULL.
+func FxJoin(table, param string) string {
+	return fmt.Sprintf(`
+		LEFT JOIN LATERAL (
+			SELECT
+				c.code AS currency,
+				c.scale,
+				r.rate,
+				r.updated_at
+			FROM
+				currencies c
+			CROSS JOIN LATERAL (
+				SELECT rate, updated_at FROM (
+					SELECT 1 AS priority, CAST(1 AS NUMERIC) AS rate, CAST(NULL AS TIMESTAMP) AS updated_at WHERE c.code = %[1]s.currency
+					UNION ALL
+					SELECT 2, er.rate, er.updated_at FROM exchange_rates er WHERE er.from_currency = %[1]s.currency AND er.to_currency = c.code
+					UNION ALL
+					SELECT 3, 1 / er.rate, er.updated_at FROM exchange_rates er WHERE er.from_currency = c.code AND er.to_currency = %[1]s.currency
+				) rates
+				ORDER BY priority
+				LIMIT 1
+			) r
+			WHERE
+				c.code = %[2]s
+		) fx ON TRUE
+	`, table, param)
+}
+
+// ConvertedPriceColumns selects price, currency, original_price, final_price
+// and discount_percent of the product aliased as table in the currency of
+// FxJoin. Converted prices are rounded by Postgres on NUMERIC to the scale of
+// the currency, so no float rounding is involved.
+func ConvertedPriceColumns(table string) string {
+	return fmt.Sprintf(`
+		COALESCE(ROUND(%[1]s.price * fx.rate, fx.scale), %[1]s.price) AS price,
+		COALESCE(fx.currency, %[1]s.currency) AS currency,
+		COALESCE(ROUND(%[1]s.price * fx.rate, fx.scale), %[1]s.price) AS original_price,
+		COALESCE(
+			ROUND(COALESCE(discount.final_price, %[1]s.price) * fx.rate, fx.scale),
+			COALESCE(discount.final_price, %[1]s.price)
+		) AS final_price,
+		CASE
+			WHEN %[1]s.price > 0 THEN ROUND((%[1]s.price - COALESCE(discount.final_price, %[1]s.price)) / %[1]s.price * 100, 2)
+			ELSE 0
+		END AS discount_percent
+	`, table)
+}
+
+// PriceChangeJoin joins the last time the price history of the product
+// aliased as table moved: an offer or price starting or ending, or a price
+// leaving the 30 days LowestPriceJoin looks back on.
+func PriceChangeJoin(table string) string {
+	return fmt.Sprintf(`
+		LEFT JOIN LATERAL (
+			SELECT
+				MAX(edge.at) AS at
+			FROM
+				price_history ph
+			CROSS JOIN LATERAL (VALUES (ph.effective_from), (ph.effective_to), (ph.effective_to + INTERVAL '30 days')) edge(at)
+			WHERE
+				ph.product_id = %[1]s.id
+				AND edge.at <= NOW()
+		) price_change ON TRUE
+	`, table)
+}
+
+// ModifiedAtColumn selects modified_at, the last change to what is shown of
+// the product aliased as table: its row, its price history or the rate it is
+// converted with. Queries using it must include FxJoin and PriceChangeJoin.
+func ModifiedAtColumn(table string) string {
+	return fmt.Sprintf(`GREATEST(%[1]s.updated_at, price_change.at, fx.updated_at) AS modified_at`, table)
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"product-service/internal/module/product/entity"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// GetQuoteSnapshot reads the quoted products with their live offers in one
+// read-only REPEATABLE READ transaction, so the time of the quote is the NOW()
+// the offers were read at. Prices are converted to currency when it is set.
+func (p *productRepository) GetQuoteSnapshot(ctx context.Context, productIds []string, currency string) (entity.QuoteSnapshot, error) {
+	var (
+		res = entity.QuoteSnapshot{
+			Products: make([]entity.QuoteProduct, 0),
+		}
+	)
+
+	tx, err := p.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		log.Error().Err(err).Any("payload", productIds).Msg("repository: GetQuoteSnapshot failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, &res.At, `SELECT NOW()`)
+	if err != nil {
+		log.Error().Err(err).Any("payload", productIds).Msg("repository: GetQuoteSnapshot failed")
+		return res, err
+	}
+
+	productsQuery := `
+		SELECT
+			products.id,
+			products.shop_id,
+			products.name,
+			products.stock,
+			discount.offer_source,
+			discount.offer_id,
+			discount.offer_ends_at,
+	` + ConvertedPriceColumns("products") + `
+		FROM
+			products
+	` + DiscountJoin("products") + FxJoin("products", "$2") + `
+		WHERE
+			products.id = ANY($1::uuid[])
+			AND products.deleted_at IS NULL
+			AND ` + Published("products") + `
+	`
+
+	err = tx.SelectContext(ctx, &res.Products, productsQuery, pq.Array(productIds), currency)
+	if err != nil {
+		log.Error().Err(err).Any("payload", productIds).Msg("repository: GetQuoteSnapshot failed to get products")
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", productIds).Msg("repository: GetQuoteSnapshot failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
@@ -2,13 +2,23 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	outboxEntity "product-service/internal/module/outbox/entity"
+	outboxRepository "product-service/internal/module/outbox/repository"
 	"product-service/internal/module/product/ports"
+	"product-service/pkg"
 	"product-service/pkg/errmsg"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"context"
 	"product-service/internal/module/product/entity"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 )
 
@@ -27,6 +37,13 @@ func (p *productRepository) CreateProduct(ctx context.Context, req *entity.Creat
 		res entity.UpsertProductResponse
 	)
 
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateProduct failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO
 			products (
@@ -36,14 +53,21 @@ func (p *productRepository) CreateProduct(ctx context.Context, req *entity.Creat
 				description,
 				image_url,
 				price,
-				stock
+				stock,
+				brand_id,
+				brand,
+				attributes,
+				currency,
+				publish_at,
+				unpublish_at,
+				low_stock_threshold
 			)
-			VALUES ( $1, $2, $3, $4, $5, $6, $7 )
+			VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, COALESCE((SELECT name FROM brands WHERE id = $8), ''), $9, $10, $11, $12, $13 )
 			RETURNING
-				id, shop_id, name, description, image_url, price, stock, created_at, updated_at
+				id, shop_id, category_id, brand_id, brand, name, description, image_url, price, currency, stock, low_stock_threshold, status, publish_at, unpublish_at, attributes, created_at, updated_at
 	`
 
-	err := p.db.QueryRowxContext(ctx, query,
+	err = tx.QueryRowxContext(ctx, query,
 		req.ShopId,
 		req.CategoryId,
 		req.Name,
@@ -51,12 +75,42 @@ func (p *productRepository) CreateProduct(ctx context.Context, req *entity.Creat
 		req.ImageUrl,
 		req.Price,
 		req.Stock,
+		req.BrandId,
+		req.Attributes,
+		req.Currency,
+		utc(req.PublishAt),
+		utc(req.UnpublishAt),
+		req.LowStockThreshold,
 	).StructScan(&res)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository: CreateProduct failed")
 		return res, err
 	}
 
+	if err = RecordRegularPrice(ctx, tx, res.Id); err != nil {
+		return res, err
+	}
+
+	err = outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+		AggregateType: outboxEntity.AggregateProduct,
+		AggregateId:   res.Id,
+		EventType:     outboxEntity.EventProductCreated,
+		ShopId:        res.ShopId,
+		Payload:       entity.NewProductEvent(res),
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if err = SyncLowStock(ctx, tx, []string{res.Id}, false); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateProduct failed to commit transaction")
+		return res, err
+	}
+
 	res.UserId = req.UserId
 	return res, nil
 }
@@ -75,6 +129,262 @@ func (p *productRepository) GetProducts(ctx context.Context, req *entity.GetProd
 	res.Meta.Limit = req.Limit
 
 	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+	` + productListColumns(req, arg) + `
+		FROM
+			products
+	` + productJoins() + PriceChangeJoin("products") + `
+		WHERE
+			deleted_at IS NULL
+	`
+
+	query += productFilters(req, arg, "")
+	query += " ORDER BY " + productOrder(req)
+
+	query += `
+		LIMIT :limit
+		OFFSET :offset
+	`
+	arg["limit"] = req.Limit
+	arg["offset"] = (req.Page - 1) * req.Limit
+
+	log.Print(query)
+
+	nstmt, err := p.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetProducts failed")
+		return res, err
+	}
+	defer nstmt.Close()
+
+	err = nstmt.SelectContext(ctx, &data, arg)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetProducts failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, entity.Product{
+			Id:             d.Id,
+			CategoryId:     d.CategoryId,
+			ShopId:         d.ShopId,
+			Name:           d.Name,
+			ImageUrl:       d.ImageUrl,
+			Price:          d.Price,
+			Currency:       d.Currency,
+			OriginalPrice:  d.OriginalPrice,
+			FinalPrice:     d.FinalPrice,
+			DiscountPct:    d.DiscountPct,
+			BrandId:        d.BrandId,
+			Brand:          d.Brand,
+			FavouriteCount: d.FavouriteCount,
+			InWishlist:     d.InWishlist,
+			Status:         d.Status,
+			CreatedAt:      d.CreatedAt,
+			UpdatedAt:      d.UpdatedAt,
+			ModifiedAt:     d.ModifiedAt,
+		})
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+// GetShopsProducts returns the same page of the listing of every shop in one
+// query, each shop's products are ranked on their own and the page is cut
+// from every ranking. Shops without products on the page are left out.
+func (p *productRepository) GetShopsProducts(ctx context.Context, shopIds []string, req *entity.GetProductsRequest) (map[string]entity.GetProductsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		ShopRank  int `db:"shop_rank"`
+		entity.Product
+	}
+	var (
+		res  = make(map[string]entity.GetProductsResponse, len(shopIds))
+		data = make([]dao, 0)
+		arg  = make(map[string]any)
+	)
+
+	filters := *req
+	filters.ShopId = ""
+
+	query := `
+		WITH ranked AS (
+			SELECT
+				COUNT(*) OVER (PARTITION BY shop_id) AS total_data,
+				ROW_NUMBER() OVER (PARTITION BY shop_id ORDER BY ` + productOrder(req) + `) AS shop_rank,
+		` + productListColumns(req, arg) + `
+			FROM
+				products
+		` + productJoins() + PriceChangeJoin("products") + `
+			WHERE
+				deleted_at IS NULL
+				AND shop_id = ANY(CAST(:shop_ids AS UUID[]))
+	` + productFilters(&filters, arg, "") + `
+		)
+		SELECT
+			*
+		FROM
+			ranked
+		WHERE
+			shop_rank > :offset
+			AND shop_rank <= :offset + :limit
+		ORDER BY shop_id, shop_rank
+	`
+	arg["shop_ids"] = pq.Array(shopIds)
+	arg["limit"] = req.Limit
+	arg["offset"] = (req.Page - 1) * req.Limit
+
+	nstmt, err := p.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetShopsProducts failed")
+		return res, err
+	}
+	defer nstmt.Close()
+
+	err = nstmt.SelectContext(ctx, &data, arg)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetShopsProducts failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		page, ok := res[d.ShopId]
+		if !ok {
+			page.Items = make([]entity.Product, 0, req.Limit)
+			page.Meta.Page = req.Page
+			page.Meta.Limit = req.Limit
+		}
+
+		page.Items = append(page.Items, d.Product)
+		page.Meta.TotalData = d.TotalData
+		res[d.ShopId] = page
+	}
+
+	for shopId, page := range res {
+		page.Meta.CountTotalPage()
+		res[shopId] = page
+	}
+
+	return res, nil
+}
+
+// productListColumns are the columns of a listed product, with the caller's
+// wishlist flag.
+func productListColumns(req *entity.GetProductsRequest, arg map[string]any) string {
+	columns := `
+			id,
+			category_id,
+			shop_id,
+			name,
+			image_url,
+			brand_id,
+			brand,
+			favourite_count,
+			status,
+			created_at,
+			updated_at,
+			` + ModifiedAtColumn("products") + `,
+	` + ConvertedPriceColumns("products") + ","
+
+	if req.UserId == "" {
+		return columns + " FALSE AS in_wishlist"
+	}
+
+	arg["user_id"] = req.UserId
+	return columns + `
+			EXISTS (
+				SELECT 1
+				FROM
+					wishlists w
+				WHERE
+					w.product_id = products.id
+					AND w.user_id = :user_id
+			) AS in_wishlist
+	`
+}
+
+// productOrder is the ORDER BY of a listing sorted by req.Sort.
+func productOrder(req *entity.GetProductsRequest) string {
+	switch req.Sort {
+	case "price_asc":
+		return ConvertedEffectivePrice + " ASC, created_at DESC"
+	case "price_desc":
+		return ConvertedEffectivePrice + " DESC, created_at DESC"
+	default:
+		return "created_at DESC"
+	}
+}
+
+// GetRecommendedProducts ranks live, in-stock products by the caller's category
+// preferences. Users without preferences (or once preferred products run out)
+// fall back to popular then newest products. Ranking within each shop is
+// interleaved so a single shop can't flood the first page.
+func (p *productRepository) GetRecommendedProducts(ctx context.Context, req *entity.GetRecommendedProductsRequest) (entity.GetProductsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.Product
+	}
+	var (
+		res  entity.GetProductsResponse
+		data = make([]dao, 0)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.Product, 0)
+
+	query := `
+		WITH preferences AS (
+			SELECT
+				category_preferences
+			FROM
+				users
+			WHERE
+				id = $1
+		),
+		candidates AS (
+			SELECT
+				p.id,
+				p.category_id,
+				p.shop_id,
+				p.name,
+				p.image_url,
+				p.price,
+				p.currency,
+				p.brand_id,
+				p.brand,
+				p.favourite_count,
+				p.created_at,
+				p.updated_at,
+				` + PriceColumns("p") + `,
+				COALESCE(p.category_id::text = ANY(pref.category_preferences), FALSE) AS is_preferred
+			FROM
+				products p
+			` + DiscountJoin("p") + `
+			JOIN
+				shops s ON s.id = p.shop_id
+			LEFT JOIN
+				preferences pref ON TRUE
+			WHERE
+				p.deleted_at IS NULL
+				AND ` + Published("p") + `
+				AND p.stock > 0
+				AND s.deleted_at IS NULL
+				AND s.user_id <> $1
+		),
+		ranked AS (
+			SELECT
+				*,
+				ROW_NUMBER() OVER (
+					PARTITION BY shop_id, is_preferred
+					ORDER BY favourite_count DESC, created_at DESC
+				) AS shop_rank
+			FROM
+				candidates
+		)
 		SELECT
 			COUNT(*) OVER() AS total_data,
 			id,
@@ -83,89 +393,359 @@ func (p *productRepository) GetProducts(ctx context.Context, req *entity.GetProd
 			name,
 			image_url,
 			price,
+			currency,
+			original_price,
+			final_price,
+			discount_percent,
+			brand_id,
 			brand,
+			favourite_count,
+			EXISTS (
+				SELECT 1
+				FROM
+					wishlists w
+				WHERE
+					w.product_id = ranked.id
+					AND w.user_id = $1
+			) AS in_wishlist,
 			created_at,
 			updated_at
 		FROM
-			products
-		WHERE
-			deleted_at IS NULL
+			ranked
+		ORDER BY
+			is_preferred DESC,
+			shop_rank ASC,
+			favourite_count DESC,
+			created_at DESC
+		LIMIT $2
+		OFFSET $3
 	`
 
+	err := p.db.SelectContext(ctx, &data, query, req.UserId, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetRecommendedProducts failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.Product)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+// productJoins joins what the listing needs to price products, it expects
+// :currency bound, which productFilters does.
+func productJoins() string {
+	return DiscountJoin("products") + FxJoin("products", ":currency")
+}
+
+// productFilters appends the GetProducts filters to arg and returns them as
+// " AND ..." conditions. Filters of the excluded facet group are skipped so
+// each facet can be counted without its own filter applied.
+func productFilters(req *entity.GetProductsRequest, arg map[string]any, exclude string) string {
+	var conditions string
+
+	// prices are filtered in the requested currency
+	arg["currency"] = req.Currency
+
+	// owners browsing the catalogue also see their own unpublished products
+	if req.UserId != "" {
+		conditions += " AND (" + Published("products") + " OR shop_id IN (SELECT id FROM shops WHERE user_id = CAST(:user_id AS UUID)))"
+		arg["user_id"] = req.UserId
+	} else {
+		conditions += " AND " + Published("products")
+	}
+
 	if req.ShopId != "" {
-		query += " AND shop_id = :shop_id"
+		conditions += " AND shop_id = :shop_id"
 		arg["shop_id"] = req.ShopId
 	}
 
-	if req.CategoryId != "" {
-		query += " AND category_id = :category_id"
+	if req.CategoryId != "" && exclude != entity.FacetCategory {
+		conditions += " AND category_id = :category_id"
 		arg["category_id"] = req.CategoryId
 	}
 
 	if req.Name != "" {
-		query += " AND name ILIKE '%' || :name || '%'"
+		conditions += " AND name ILIKE '%' || :name || '%'"
 		arg["name"] = req.Name
 	}
 
-	if req.PriceMinStr != "" {
-		query += " AND price >= :price_min"
+	if req.PriceMinStr != "" && exclude != entity.FacetPrice {
+		conditions += " AND " + ConvertedEffectivePrice + " >= :price_min"
 		arg["price_min"] = req.PriceMin
 	}
 
-	if req.PriceMaxStr != "" {
-		query += " AND price <= :price_max"
+	if req.PriceMaxStr != "" && exclude != entity.FacetPrice {
+		conditions += " AND " + ConvertedEffectivePrice + " <= :price_max"
 		arg["price_max"] = req.PriceMax
 	}
 
-	if req.IsAvailable {
-		query += " AND stock > 0"
+	if req.IsAvailable && exclude != entity.FacetAvailability {
+		conditions += " AND stock > 0"
 	}
 
-	if req.Brand != "" {
-		query += " AND brand ILIKE '%' || :brand || '%'"
-		arg["brand"] = req.Brand
+	conditions += attributeFilters(req.AttributeFilters, arg)
+
+	if exclude != entity.FacetBrand {
+		// brand matches the brand name exactly once normalised, like the
+		// brands table dedupes it
+		if req.Brand != "" {
+			conditions += " AND brand_id = (SELECT id FROM brands WHERE normalized_name = :brand AND deleted_at IS NULL)"
+			arg["brand"] = pkg.NormalizeBrandName(req.Brand)
+		}
+
+		if req.BrandId != "" {
+			conditions += " AND brand_id = :brand_id"
+			arg["brand_id"] = req.BrandId
+		}
+
+		if req.BrandSlug != "" {
+			conditions += " AND brand_id = (SELECT id FROM brands WHERE slug = :brand_slug AND deleted_at IS NULL)"
+			arg["brand_slug"] = req.BrandSlug
+		}
 	}
 
-	query += `
-		ORDER BY created_at DESC
-		LIMIT :limit
-		OFFSET :offset
-	`
-	arg["limit"] = req.Limit
-	arg["offset"] = (req.Page - 1) * req.Limit
+	return conditions
+}
 
-	log.Print(query)
+// attributeFilters turns attr[code]=value filters into JSONB conditions.
+// Exact matches use containment so the GIN index on attributes applies, the
+// value is tried as string, number and boolean since the query string is
+// untyped. attr[code_min] and attr[code_max] compare numeric attributes.
+func attributeFilters(filters map[string]string, arg map[string]any) string {
+	var (
+		conditions string
+		codes      = make([]string, 0, len(filters))
+	)
+
+	for code := range filters {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes) // stable query text
+
+	for i, code := range codes {
+		var (
+			value = filters[code]
+			key   = fmt.Sprintf("attr_key_%d", i)
+			val   = fmt.Sprintf("attr_val_%d", i)
+		)
+
+		switch {
+		case strings.HasSuffix(code, "_min"), strings.HasSuffix(code, "_max"):
+			operator := ">="
+			if strings.HasSuffix(code, "_max") {
+				operator = "<="
+			}
+
+			conditions += fmt.Sprintf(`
+				AND CASE
+					WHEN jsonb_typeof(attributes -> :%[1]s) = 'number' THEN CAST(attributes ->> :%[1]s AS NUMERIC)
+				END %[2]s CAST(:%[3]s AS NUMERIC)`, key, operator, val)
+			arg[key] = code[:len(code)-len("_min")]
+			arg[val] = value
+		default:
+			candidates := []any{value}
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				candidates = append(candidates, number)
+			}
+			if boolean, err := strconv.ParseBool(value); err == nil && (value == "true" || value == "false") {
+				candidates = append(candidates, boolean)
+			}
+
+			contains := make([]string, 0, len(candidates))
+			for j, candidate := range candidates {
+				name := fmt.Sprintf("%s_%d", val, j)
+				doc, _ := json.Marshal(map[string]any{code: candidate})
+
+				contains = append(contains, fmt.Sprintf("attributes @> CAST(:%s AS JSONB)", name))
+				arg[name] = string(doc)
+			}
+
+			conditions += " AND (" + strings.Join(contains, " OR ") + ")"
+		}
+	}
+
+	return conditions
+}
+
+// GetProductFacets counts the GetProducts result set per requested facet.
+func (p *productRepository) GetProductFacets(ctx context.Context, req *entity.GetProductsRequest) (entity.Facets, error) {
+	var (
+		res entity.Facets
+		err error
+	)
+
+	for _, facet := range req.FacetList {
+		switch facet {
+		case entity.FacetCategory:
+			res.Categories, err = p.countFacet(ctx, req, facet, `
+				SELECT
+					f.id,
+					pc.name,
+					f.count
+				FROM (
+					SELECT
+						category_id AS id,
+						COUNT(*) AS count
+					FROM
+						products
+					`+productJoins()+`
+					WHERE
+						deleted_at IS NULL
+						%s
+					GROUP BY category_id
+				) f
+				JOIN
+					product_categories pc ON pc.id = f.id
+				ORDER BY f.count DESC, pc.name ASC
+			`)
+		case entity.FacetBrand:
+			res.Brands, err = p.countFacet(ctx, req, facet, `
+				SELECT
+					brand_id AS id,
+					MAX(brand) AS name,
+					COUNT(*) AS count
+				FROM
+					products
+				`+productJoins()+`
+				WHERE
+					deleted_at IS NULL
+					AND brand_id IS NOT NULL
+					%s
+				GROUP BY brand_id
+				ORDER BY count DESC, name ASC
+			`)
+		case entity.FacetPrice:
+			res.Prices, err = p.countPriceFacet(ctx, req)
+		case entity.FacetAvailability:
+			res.Availability, err = p.countAvailabilityFacet(ctx, req)
+		}
+
+		if err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) countFacet(ctx context.Context, req *entity.GetProductsRequest, facet, query string) ([]entity.FacetBucket, error) {
+	var (
+		res = make([]entity.FacetBucket, 0)
+		arg = make(map[string]any)
+	)
+
+	query = fmt.Sprintf(query, productFilters(req, arg, facet))
 
 	nstmt, err := p.db.PrepareNamedContext(ctx, query)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository: GetProducts failed")
+		log.Error().Err(err).Any("payload", req).Str("facet", facet).Msg("repository: GetProductFacets failed")
+		return res, err
+	}
+	defer nstmt.Close()
+
+	err = nstmt.SelectContext(ctx, &res, arg)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Str("facet", facet).Msg("repository: GetProductFacets failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) countPriceFacet(ctx context.Context, req *entity.GetProductsRequest) ([]entity.PriceFacetBucket, error) {
+	type dao struct {
+		Bucket int `db:"bucket"`
+		Count  int `db:"count"`
+	}
+	var (
+		res  = make([]entity.PriceFacetBucket, 0, len(req.PriceBuckets)+1)
+		data = make([]dao, 0)
+		arg  = make(map[string]any)
+	)
+
+	// width_bucket returns 0 below the first bound and len(bounds) above the last
+	query := `
+		SELECT
+			width_bucket(` + ConvertedEffectivePrice + `, CAST(:price_buckets AS NUMERIC[])) AS bucket,
+			COUNT(*) AS count
+		FROM
+			products
+		` + productJoins() + `
+		WHERE
+			deleted_at IS NULL
+	` + productFilters(req, arg, entity.FacetPrice) + `
+		GROUP BY bucket
+	`
+	arg["price_buckets"] = pq.Array(req.PriceBuckets)
+
+	nstmt, err := p.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetProductFacets price failed")
 		return res, err
 	}
 	defer nstmt.Close()
 
 	err = nstmt.SelectContext(ctx, &data, arg)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository: GetProducts failed")
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetProductFacets price failed")
 		return res, err
 	}
 
+	counts := make(map[int]int, len(data))
 	for _, d := range data {
-		res.Items = append(res.Items, entity.Product{
-			Id:         d.Id,
-			CategoryId: d.CategoryId,
-			ShopId:     d.ShopId,
-			Name:       d.Name,
-			ImageUrl:   d.ImageUrl,
-			Price:      d.Price,
-			Brand:      d.Brand,
-			CreatedAt:  d.CreatedAt,
-			UpdatedAt:  d.UpdatedAt,
-		})
+		counts[d.Bucket] = d.Count
+	}
 
-		res.Meta.TotalData = d.TotalData
+	for i := 0; i <= len(req.PriceBuckets); i++ {
+		bucket := entity.PriceFacetBucket{Count: counts[i]}
+		if i > 0 {
+			bucket.Min = &req.PriceBuckets[i-1]
+		}
+		if i < len(req.PriceBuckets) {
+			bucket.Max = &req.PriceBuckets[i]
+		}
+		res = append(res, bucket)
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) countAvailabilityFacet(ctx context.Context, req *entity.GetProductsRequest) (*entity.AvailabilityFacet, error) {
+	var (
+		res = &entity.AvailabilityFacet{}
+		arg = make(map[string]any)
+	)
+
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE stock > 0) AS in_stock,
+			COUNT(*) FILTER (WHERE stock <= 0) AS out_of_stock
+		FROM
+			products
+		` + productJoins() + `
+		WHERE
+			deleted_at IS NULL
+	` + productFilters(req, arg, entity.FacetAvailability)
+
+	nstmt, err := p.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetProductFacets availability failed")
+		return res, err
+	}
+	defer nstmt.Close()
+
+	err = nstmt.GetContext(ctx, res, arg)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetProductFacets availability failed")
+		return res, err
 	}
 
-	res.Meta.CountTotalPage()
 	return res, nil
 }
 
@@ -174,25 +754,103 @@ func (p *productRepository) UpdateProduct(ctx context.Context, req *entity.Updat
 		res entity.UpsertProductResponse
 	)
 
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateProduct failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	// locked so the stock event reports the stock this update replaced
+	var previous struct {
+		Stock       int        `db:"stock"`
+		Currency    string     `db:"currency"`
+		Status      string     `db:"status"`
+		PublishAt   *time.Time `db:"publish_at"`
+		UnpublishAt *time.Time `db:"unpublish_at"`
+	}
+
+	err = tx.GetContext(ctx, &previous, `SELECT stock, currency, status, publish_at, unpublish_at FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, req.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Product not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Product not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateProduct failed")
+		return res, err
+	}
+
+	// discounts, campaign prices and the price history are amounts in the
+	// product's currency, switching it would silently reprice all of them
+	if req.Currency != "" && req.Currency != previous.Currency {
+		log.Warn().Any("payload", req).Msg("repository: Product currency can't be changed")
+		return res, errmsg.NewCostumErrors(422, errmsg.WithMessage("Product currency can't be changed"), errmsg.WithErrors("currency", fmt.Sprintf("product is priced in %s, currency can't be changed.", previous.Currency)))
+	}
+
+	// a bound left out of the request keeps the stored one, so the pair the
+	// product ends up with is checked here rather than by the constraint
+	publishAt, unpublishAt := req.PublishAt, req.UnpublishAt
+	if !req.ClearSchedule {
+		if publishAt == nil {
+			publishAt = previous.PublishAt
+		}
+		if unpublishAt == nil {
+			unpublishAt = previous.UnpublishAt
+		}
+	}
+
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		log.Warn().Any("payload", req).Msg("repository: Product schedule ends before it starts")
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("unpublish_at", "unpublish at must be after publish at."))
+	}
+
+	// what buyers see must be reviewed again, the right hand sides read the
+	// row as it was before the update
+	contentChanged := `
+		products.status IN ('published', 'pending_review')
+		AND (
+			category_id IS DISTINCT FROM COALESCE(NULLIF($1, '')::uuid, category_id)
+			OR name IS DISTINCT FROM $2
+			OR description IS DISTINCT FROM $3
+			OR image_url IS DISTINCT FROM $4
+			OR attributes IS DISTINCT FROM COALESCE($9, attributes)
+			OR CASE WHEN $13 THEN brand_id IS NOT NULL ELSE brand_id IS DISTINCT FROM COALESCE($8, brand_id) END
+		)
+	`
+
 	query := `
 		UPDATE
 			products
 		SET
-			category_id = $1,
+			status = CASE WHEN ` + contentChanged + ` THEN 'pending_review' ELSE status END,
+			submitted_at = CASE WHEN ` + contentChanged + ` THEN NOW() ELSE submitted_at END,
+			category_id = COALESCE(NULLIF($1, '')::uuid, category_id),
 			name = $2,
 			description = $3,
 			image_url = $4,
 			price = $5,
 			stock = $6,
+			brand_id = CASE WHEN $13 THEN NULL ELSE COALESCE($8, brand_id) END,
+			brand = CASE WHEN $13 THEN '' ELSE COALESCE((SELECT name FROM brands WHERE id = $8), brand) END,
+			attributes = COALESCE($9, attributes),
+			publish_at = $10,
+			unpublish_at = $11,
+			low_stock_threshold = CASE WHEN $14 THEN NULL ELSE COALESCE($12, low_stock_threshold) END,
 			updated_at = NOW()
 		WHERE
 			id = $7
 			AND deleted_at IS NULL
 		RETURNING
-			id, shop_id, name, description, image_url, price, stock, created_at, updated_at
+			id, shop_id, category_id, brand_id, brand, name, description, image_url, price, currency, stock, low_stock_threshold, status, publish_at, unpublish_at, attributes, created_at, updated_at
 	`
 
-	err := p.db.QueryRowxContext(ctx, query,
+	// nil keeps the stored attributes, an empty object clears them
+	var attributes any
+	if req.Attributes != nil {
+		attributes = req.Attributes
+	}
+
+	err = tx.QueryRowxContext(ctx, query,
 		req.CategoryId,
 		req.Name,
 		req.Description,
@@ -200,6 +858,13 @@ func (p *productRepository) UpdateProduct(ctx context.Context, req *entity.Updat
 		req.Price,
 		req.Stock,
 		req.Id,
+		req.BrandId,
+		attributes,
+		utc(publishAt),
+		utc(unpublishAt),
+		req.LowStockThreshold,
+		req.ClearBrand,
+		req.ClearLowStockThreshold,
 	).StructScan(&res)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -210,24 +875,119 @@ func (p *productRepository) UpdateProduct(ctx context.Context, req *entity.Updat
 		return res, err
 	}
 
+	// discounts are priced off the regular price, so they move with it
+	if err = RecordRegularPrice(ctx, tx, res.Id); err != nil {
+		return res, err
+	}
+
+	if err = SyncOfferPrices(ctx, tx, []string{res.Id}); err != nil {
+		return res, err
+	}
+
+	events := []outboxEntity.Event{{
+		AggregateType: outboxEntity.AggregateProduct,
+		AggregateId:   res.Id,
+		EventType:     outboxEntity.EventProductUpdated,
+		ShopId:        res.ShopId,
+		Payload:       entity.NewProductEvent(res),
+	}}
+
+	if previous.Stock != res.Stock {
+		events = append(events, outboxEntity.Event{
+			AggregateType: outboxEntity.AggregateProduct,
+			AggregateId:   res.Id,
+			EventType:     outboxEntity.EventProductStockChanged,
+			ShopId:        res.ShopId,
+			Payload: entity.StockChangedEvent{
+				ProductId:     res.Id,
+				ShopId:        res.ShopId,
+				PreviousStock: previous.Stock,
+				Stock:         res.Stock,
+				ChangedAt:     res.UpdatedAt,
+			},
+		})
+	}
+
+	for _, event := range events {
+		if err = outboxRepository.Enqueue(ctx, tx, event); err != nil {
+			return res, err
+		}
+	}
+
+	if res.Status == entity.StatusPendingReview && previous.Status == entity.StatusPublished {
+		err = enqueueSubmission(ctx, tx, entity.SubmissionEvent{
+			ProductId:      res.Id,
+			ShopId:         res.ShopId,
+			SellerId:       req.UserId,
+			Name:           res.Name,
+			Status:         res.Status,
+			PreviousStatus: previous.Status,
+			SubmittedAt:    res.UpdatedAt,
+		})
+		if err != nil {
+			return res, err
+		}
+	}
+
+	// the seller setting the stock is a restock, it rearms the alert
+	if err = SyncLowStock(ctx, tx, []string{res.Id}, true); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateProduct failed to commit transaction")
+		return res, err
+	}
+
 	res.UserId = req.UserId
 	return res, nil
 }
 
 func (p *productRepository) DeleteProduct(ctx context.Context, req *entity.DeleteProductRequest) error {
+	var event entity.ProductDeletedEvent
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteProduct failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 	UPDATE products
 		SET deleted_at = NOW()
 	WHERE
 		id = $1
+		AND deleted_at IS NULL
+	RETURNING
+		id, shop_id, deleted_at
 	`
 
-	_, err := p.db.ExecContext(ctx, query, req.ProductId)
+	err = tx.QueryRowxContext(ctx, query, req.ProductId).Scan(&event.ProductId, &event.ShopId, &event.DeletedAt)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil // already deleted
+		}
 		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteProduct failed")
 		return err
 	}
 
+	err = outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+		AggregateType: outboxEntity.AggregateProduct,
+		AggregateId:   event.ProductId,
+		EventType:     outboxEntity.EventProductDeleted,
+		ShopId:        event.ShopId,
+		Payload:       event,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteProduct failed to commit transaction")
+		return err
+	}
+
 	return nil
 }
 
@@ -262,6 +1022,96 @@ func (p *productRepository) IsShopOwner(ctx context.Context, userId, shopId stri
 	return isOwner, nil
 }
 
+func (p *productRepository) GetAttributeDefinitions(ctx context.Context, categoryId string) ([]entity.AttributeDefinition, error) {
+	var (
+		res = make([]entity.AttributeDefinition, 0)
+	)
+
+	query := `
+		SELECT
+			code,
+			type,
+			options,
+			is_required
+		FROM
+			category_attributes
+		WHERE
+			category_id = $1
+			AND deleted_at IS NULL
+	`
+
+	err := p.db.SelectContext(ctx, &res, query, categoryId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", categoryId).Msg("repository: GetAttributeDefinitions failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) IsBrandExist(ctx context.Context, brandId string) (bool, error) {
+	var exist bool
+
+	query := `SELECT EXISTS(SELECT 1 FROM brands WHERE id = $1 AND deleted_at IS NULL)`
+
+	err := p.db.GetContext(ctx, &exist, query, brandId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", brandId).Msg("repository: IsBrandExist failed")
+		return exist, err
+	}
+
+	return exist, nil
+}
+
+func (p *productRepository) IsCurrencyExist(ctx context.Context, currency string) (bool, error) {
+	var exist bool
+
+	query := `SELECT EXISTS(SELECT 1 FROM currencies WHERE code = $1)`
+
+	err := p.db.GetContext(ctx, &exist, query, currency)
+	if err != nil {
+		log.Error().Err(err).Any("payload", currency).Msg("repository: IsCurrencyExist failed")
+		return exist, err
+	}
+
+	return exist, nil
+}
+
+// GetUnconvertibleCurrencies lists the currencies of the products in ids, or
+// of every product when ids is empty, that have no rate to currency in either
+// direction, so FxJoin would leave their prices unconverted.
+func (p *productRepository) GetUnconvertibleCurrencies(ctx context.Context, currency string, ids []string) ([]string, error) {
+	var res = make([]string, 0)
+
+	query := `
+		SELECT DISTINCT
+			p.currency
+		FROM
+			products p
+		WHERE
+			p.deleted_at IS NULL
+			AND (COALESCE(CARDINALITY($2::uuid[]), 0) = 0 OR p.id = ANY($2::uuid[]))
+			AND p.currency <> $1
+			AND NOT EXISTS (
+				SELECT 1
+				FROM
+					exchange_rates er
+				WHERE
+					(er.from_currency = p.currency AND er.to_currency = $1)
+					OR (er.from_currency = $1 AND er.to_currency = p.currency)
+			)
+		ORDER BY p.currency
+	`
+
+	err := p.db.SelectContext(ctx, &res, query, currency, pq.Array(ids))
+	if err != nil {
+		log.Error().Err(err).Str("currency", currency).Msg("repository: GetUnconvertibleCurrencies failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
 func (p *productRepository) IsProductOwner(ctx context.Context, userId, productId string) (bool, error) {
 	var (
 		isOwner bool
@@ -310,17 +1160,31 @@ func (p *productRepository) GetProductById(ctx context.Context, req *entity.GetP
 		description,
 		image_url,
 		stock,
-		price,
+		brand_id,
 		brand,
+		favourite_count,
+		status,
+		rejection_reason,
+		publish_at,
+		unpublish_at,
+		attributes,
 		created_at,
-		updated_at
+		updated_at,
+		` + ModifiedAtColumn("products") + `,
+	` + ConvertedPriceColumns("products") + `
 	FROM
 		products
+	` + DiscountJoin("products") + FxJoin("products", "$2") + PriceChangeJoin("products") + `
 	WHERE
 		id = $1
+		AND deleted_at IS NULL
+		AND (
+			` + Published("products") + `
+			OR shop_id IN (SELECT id FROM shops WHERE user_id = NULLIF($3, '')::uuid)
+		)
 	`
 
-	row := p.db.QueryRowContext(ctx, query, req.ProductId)
+	row := p.db.QueryRowContext(ctx, query, req.ProductId, req.Currency, req.UserId)
 	err := row.Scan(&res.Id,
 		&res.ShopId,
 		&res.CategoryId,
@@ -328,10 +1192,22 @@ func (p *productRepository) GetProductById(ctx context.Context, req *entity.GetP
 		&res.Description,
 		&res.ImageUrl,
 		&res.Stock,
-		&res.Price,
+		&res.BrandId,
 		&res.Brand,
+		&res.FavouriteCount,
+		&res.Status,
+		&res.RejectionReason,
+		&res.PublishAt,
+		&res.UnpublishAt,
+		&res.Attributes,
 		&res.CreatedAt,
 		&res.UpdatedAt,
+		&res.ModifiedAt,
+		&res.Price,
+		&res.Currency,
+		&res.OriginalPrice,
+		&res.FinalPrice,
+		&res.DiscountPct,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -345,3 +1221,194 @@ func (p *productRepository) GetProductById(ctx context.Context, req *entity.GetP
 	return res, nil
 
 }
+
+// GetProductsByIds returns the published products among the ids in any
+// order, with their lowest price of the last 30 days. Unknown, deleted and
+// unpublished ids are left out.
+func (p *productRepository) GetProductsByIds(ctx context.Context, ids []string, currency string) ([]entity.GetProductResponseById, error) {
+	var (
+		res = make([]entity.GetProductResponseById, 0, len(ids))
+	)
+
+	query := `
+	SELECT
+		id,
+		shop_id,
+		category_id,
+		name,
+		description,
+		image_url,
+		stock,
+		brand_id,
+		brand,
+		favourite_count,
+		status,
+		rejection_reason,
+		publish_at,
+		unpublish_at,
+		attributes,
+		created_at,
+		updated_at,
+	` + ConvertedPriceColumns("products") + `,
+		COALESCE(ROUND(lowest.price * fx.rate, fx.scale), lowest.price) AS lowest_price_30d
+	FROM
+		products
+	` + DiscountJoin("products") + LowestPriceJoin("products") + FxJoin("products", "$2") + `
+	WHERE
+		id = ANY($1::uuid[])
+		AND deleted_at IS NULL
+		AND ` + Published("products") + `
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, pq.Array(ids), currency)
+	if err != nil {
+		log.Error().Err(err).Any("payload", ids).Msg("repository: GetProductsByIds failed")
+		return res, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product entity.GetProductResponseById
+
+		err = rows.Scan(&product.Id,
+			&product.ShopId,
+			&product.CategoryId,
+			&product.Name,
+			&product.Description,
+			&product.ImageUrl,
+			&product.Stock,
+			&product.BrandId,
+			&product.Brand,
+			&product.FavouriteCount,
+			&product.Status,
+			&product.RejectionReason,
+			&product.PublishAt,
+			&product.UnpublishAt,
+			&product.Attributes,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+			&product.Price,
+			&product.Currency,
+			&product.OriginalPrice,
+			&product.FinalPrice,
+			&product.DiscountPct,
+			&product.LowestPrice30d,
+		)
+		if err != nil {
+			log.Error().Err(err).Any("payload", ids).Msg("repository: GetProductsByIds failed")
+			return res, err
+		}
+
+		res = append(res, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Error().Err(err).Any("payload", ids).Msg("repository: GetProductsByIds failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetCategoriesByIds returns the categories among the ids in any order,
+// deleted categories are left out.
+func (p *productRepository) GetCategoriesByIds(ctx context.Context, ids []string) ([]entity.Category, error) {
+	var (
+		res = make([]entity.Category, 0, len(ids))
+	)
+
+	query := `
+		SELECT
+			id,
+			name
+		FROM
+			product_categories
+		WHERE
+			id = ANY($1::uuid[])
+			AND deleted_at IS NULL
+	`
+
+	err := p.db.SelectContext(ctx, &res, query, pq.Array(ids))
+	if err != nil {
+		log.Error().Err(err).Any("payload", ids).Msg("repository: GetCategoriesByIds failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetRelatedProducts scores candidates against the source product by category,
+// brand, price band (+/- 25%) and name similarity. The name query is built with
+// plainto_tsquery and OR-ed so any shared word counts. Prices are banded in the
+// source product's currency, candidates without a rate to it get no band score.
+func (p *productRepository) GetRelatedProducts(ctx context.Context, req *entity.GetRelatedProductsRequest) (entity.GetRelatedProductsResponse, error) {
+	var (
+		res entity.GetRelatedProductsResponse
+	)
+	res.Items = make([]entity.Product, 0)
+
+	query := `
+		WITH source AS (
+			SELECT
+				id,
+				category_id,
+				brand_id,
+				price,
+				currency,
+				REPLACE(plainto_tsquery('simple', name)::text, '&', '|')::tsquery AS name_query
+			FROM
+				products
+			WHERE
+				id = $1
+				AND deleted_at IS NULL
+				AND ` + Published("products") + `
+		)
+		SELECT
+			p.id,
+			p.category_id,
+			p.shop_id,
+			p.name,
+			p.image_url,
+			p.price,
+			p.currency,
+			p.brand_id,
+			p.brand,
+			p.favourite_count,
+			p.created_at,
+			p.updated_at,
+		` + PriceColumns("p") + `
+		FROM
+			products p
+		CROSS JOIN
+			source s
+		` + DiscountJoin("p") + FxJoin("p", "s.currency") + `
+		WHERE
+			p.id <> s.id
+			AND p.deleted_at IS NULL
+			AND ` + Published("p") + `
+			AND p.stock > 0
+			AND (
+				p.category_id = s.category_id
+				OR p.brand_id = s.brand_id
+				OR to_tsvector('simple', p.name) @@ s.name_query
+			)
+		ORDER BY
+			(
+				CASE WHEN p.category_id = s.category_id THEN 3 ELSE 0 END
+				+ CASE WHEN p.brand_id = s.brand_id THEN 2 ELSE 0 END
+				+ CASE WHEN s.price > 0 AND p.price * fx.rate BETWEEN s.price * 0.75 AND s.price * 1.25 THEN 1 ELSE 0 END
+				+ ts_rank(to_tsvector('simple', p.name), s.name_query) * 4
+			) DESC,
+			p.favourite_count DESC,
+			p.created_at DESC
+		LIMIT $2
+	`
+
+	err := p.db.SelectContext(ctx, &res.Items, query, req.ProductId, req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetRelatedProducts failed")
+		return res, err
+	}
+
+	return res, nil
+}
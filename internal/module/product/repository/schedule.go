@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	outboxEntity "product-service/internal/module/outbox/entity"
+	outboxRepository "product-service/internal/module/outbox/repository"
+	"product-service/internal/module/product/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// EmitScheduledEvents announces up to limit products that went live and up
+// to limit that went offline since the last run. Products are claimed with
+// SKIP LOCKED, so schedulers running on several replicas split the work and
+// never announce a product twice.
+func (p *productRepository) EmitScheduledEvents(ctx context.Context, limit int) (entity.ScheduleRun, error) {
+	var res entity.ScheduleRun
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository: EmitScheduledEvents failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	// announced again when the product is rescheduled after going live
+	liveQuery := `
+		WITH due AS (
+			SELECT
+				id
+			FROM
+				products
+			WHERE
+				deleted_at IS NULL
+				AND status = 'published'
+				AND publish_at <= NOW()
+				AND (unpublish_at IS NULL OR unpublish_at > NOW())
+				AND (live_event_at IS NULL OR live_event_at < publish_at)
+			ORDER BY publish_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE
+			products p
+		SET
+			live_event_at = NOW()
+		FROM
+			due,
+			shops s
+		WHERE
+			p.id = due.id
+			AND s.id = p.shop_id
+		RETURNING
+			p.id AS product_id,
+			p.shop_id,
+			s.user_id AS seller_id,
+			p.name,
+			p.publish_at AS at
+	`
+
+	published, err := emitSchedule(ctx, tx, liveQuery, limit, outboxEntity.EventProductPublished)
+	if err != nil {
+		return res, err
+	}
+	res.Published = len(published)
+	res.ProductIds = append(res.ProductIds, published...)
+
+	offlineQuery := `
+		WITH due AS (
+			SELECT
+				id
+			FROM
+				products
+			WHERE
+				deleted_at IS NULL
+				AND status = 'published'
+				AND unpublish_at <= NOW()
+				AND (offline_event_at IS NULL OR offline_event_at < unpublish_at)
+			ORDER BY unpublish_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE
+			products p
+		SET
+			offline_event_at = NOW()
+		FROM
+			due,
+			shops s
+		WHERE
+			p.id = due.id
+			AND s.id = p.shop_id
+		RETURNING
+			p.id AS product_id,
+			p.shop_id,
+			s.user_id AS seller_id,
+			p.name,
+			p.unpublish_at AS at
+	`
+
+	unpublished, err := emitSchedule(ctx, tx, offlineQuery, limit, outboxEntity.EventProductUnpublished)
+	if err != nil {
+		return res, err
+	}
+	res.Unpublished = len(unpublished)
+	res.ProductIds = append(res.ProductIds, unpublished...)
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository: EmitScheduledEvents failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// emitSchedule claims the products selected by query and queues eventType
+// for each of them. It returns the ids of the claimed products.
+func emitSchedule(ctx context.Context, tx *sqlx.Tx, query string, limit int, eventType string) ([]string, error) {
+	events := make([]entity.ScheduleEvent, 0)
+
+	err := tx.SelectContext(ctx, &events, query, limit)
+	if err != nil {
+		log.Error().Err(err).Str("event_type", eventType).Msg("repository: EmitScheduledEvents failed")
+		return nil, err
+	}
+
+	productIds := make([]string, 0, len(events))
+	for _, event := range events {
+		err = outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+			AggregateType: outboxEntity.AggregateProduct,
+			AggregateId:   event.ProductId,
+			EventType:     eventType,
+			ShopId:        event.ShopId,
+			Payload:       event,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		productIds = append(productIds, event.ProductId)
+	}
+
+	return productIds, nil
+}
+
+// utc stores optional timestamps in UTC like the rest of the schema.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+	return &u
+}
@@ -0,0 +1,452 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	outboxEntity "product-service/internal/module/outbox/entity"
+	outboxRepository "product-service/internal/module/outbox/repository"
+	"product-service/internal/module/product/entity"
+	"product-service/pkg/errmsg"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// stockLine is an item of a reservation with the position it was first
+// listed at, which errors point back to.
+type stockLine struct {
+	entity.StockItem
+	index int
+}
+
+// ReserveStock takes the items of an order out of stock, all of them or none.
+// Only published products can be reserved.
+// Each product is decremented in a single conditional UPDATE so concurrent
+// orders can never oversell, and products are locked in id order so orders
+// sharing products cannot deadlock. The reserved quantity also counts against
+// the stock cap of the product's active discount. An order is reserved once,
+// reserving it again returns what it holds.
+func (p *productRepository) ReserveStock(ctx context.Context, req *entity.ReserveStockRequest) (entity.StockResponse, error) {
+	var (
+		res = entity.StockResponse{OrderId: req.OrderId}
+	)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReserveStock failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	if err = lockOrder(ctx, tx, req.OrderId); err != nil {
+		return res, err
+	}
+
+	reserved, released, err := orderReservations(ctx, tx, req.OrderId)
+	if err != nil {
+		return res, err
+	}
+
+	if released {
+		log.Warn().Any("payload", req).Msg("repository: Order stock was released")
+		return res, errmsg.NewCostumErrors(409, errmsg.WithMessage("Order stock has been released"))
+	}
+
+	if len(reserved) > 0 {
+		log.Debug().Str("order_id", req.OrderId).Msg("repository: Order stock is already reserved")
+		res.Items = reserved
+		return res, nil
+	}
+
+	lines := mergeStockItems(req.Items)
+	res.Items = make([]entity.StockLevel, 0, len(lines))
+	productIds := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		var event entity.StockChangedEvent
+
+		// only products on sale can be ordered, drafts and products off their
+		// schedule can't
+		query := `
+			UPDATE
+				products
+			SET
+				stock = stock - $2,
+				updated_at = NOW()
+			WHERE
+				id = $1
+				AND deleted_at IS NULL
+				AND stock >= $2
+				AND ` + Published("products") + `
+			RETURNING
+				id AS product_id,
+				shop_id,
+				stock + $2 AS previous_stock,
+				stock,
+				updated_at AS changed_at
+		`
+
+		err = tx.QueryRowxContext(ctx, query, line.ProductId, line.Quantity).StructScan(&event)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return res, reserveFailed(ctx, tx, line)
+			}
+			log.Error().Err(err).Any("payload", req).Msg("repository: ReserveStock failed")
+			return res, err
+		}
+
+		// the discount row is locked before its count is read, so concurrent
+		// orders fill the cap one after the other and never past it
+		var discount struct {
+			Id       *string `db:"id"`
+			Quantity int     `db:"quantity"`
+		}
+
+		query = `
+			WITH discount AS (
+				SELECT
+					id,
+					sold_count
+				FROM
+					product_discounts
+				WHERE
+					product_id = $1
+					AND deleted_at IS NULL
+					AND starts_at <= NOW()
+					AND ends_at > NOW()
+					AND (stock_cap IS NULL OR sold_count < stock_cap)
+				ORDER BY starts_at DESC
+				LIMIT 1
+				FOR UPDATE
+			)
+			UPDATE
+				product_discounts pd
+			SET
+				sold_count = CASE
+					WHEN pd.stock_cap IS NULL THEN pd.sold_count + $2
+					ELSE LEAST(pd.sold_count + $2, pd.stock_cap)
+				END,
+				updated_at = NOW()
+			FROM
+				discount
+			WHERE
+				pd.id = discount.id
+			RETURNING
+				pd.id,
+				pd.sold_count - discount.sold_count AS quantity
+		`
+
+		err = tx.QueryRowxContext(ctx, query, line.ProductId, line.Quantity).StructScan(&discount)
+		if err != nil && err != sql.ErrNoRows {
+			log.Error().Err(err).Any("payload", req).Msg("repository: ReserveStock failed to count the discount")
+			return res, err
+		}
+
+		query = `
+			INSERT INTO
+				stock_reservations (order_id, product_id, quantity, discount_id, discount_quantity)
+			VALUES
+				($1, $2, $3, $4, $5)
+		`
+
+		_, err = tx.ExecContext(ctx, query, req.OrderId, line.ProductId, line.Quantity, discount.Id, discount.Quantity)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository: ReserveStock failed to record the reservation")
+			return res, err
+		}
+
+		err = outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+			AggregateType: outboxEntity.AggregateProduct,
+			AggregateId:   event.ProductId,
+			EventType:     outboxEntity.EventProductStockChanged,
+			ShopId:        event.ShopId,
+			Payload:       event,
+		})
+		if err != nil {
+			return res, err
+		}
+
+		res.Items = append(res.Items, entity.StockLevel{
+			ProductId: event.ProductId,
+			Quantity:  line.Quantity,
+			Stock:     event.Stock,
+		})
+		productIds = append(productIds, event.ProductId)
+	}
+
+	// a discount sold out by the order stops being offered
+	if err = SyncOfferPrices(ctx, tx, productIds); err != nil {
+		return res, err
+	}
+
+	if err = SyncLowStock(ctx, tx, productIds, false); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReserveStock failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// ReleaseStock puts what the order still holds back in stock, of the listed
+// products only when there are any. See ReleaseOrderStock.
+func (p *productRepository) ReleaseStock(ctx context.Context, req *entity.ReleaseStockRequest) (entity.StockResponse, error) {
+	var (
+		res = entity.StockResponse{OrderId: req.OrderId}
+	)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReleaseStock failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	res.Items, err = ReleaseOrderStock(ctx, tx, req.OrderId, req.ProductIds)
+	if err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReleaseStock failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// ReleaseOrderStock puts what the order still holds back in stock, of the
+// given products only when there are any, and gives the discounts back what
+// they counted. Releasing twice releases nothing the second time. Deleted
+// products are not restocked. It is the one release path of reservations,
+// every caller runs it in its own transaction, which keeps the order locked
+// until it ends.
+func ReleaseOrderStock(ctx context.Context, tx *sqlx.Tx, orderId string, productIds []string) ([]entity.StockLevel, error) {
+	var (
+		res = make([]entity.StockLevel, 0)
+	)
+
+	if err := lockOrder(ctx, tx, orderId); err != nil {
+		return res, err
+	}
+
+	reservations := make([]struct {
+		ProductId        string  `db:"product_id"`
+		Quantity         int     `db:"quantity"`
+		DiscountId       *string `db:"discount_id"`
+		DiscountQuantity int     `db:"discount_quantity"`
+	}, 0)
+
+	query := `
+		UPDATE
+			stock_reservations
+		SET
+			released_at = NOW()
+		WHERE
+			order_id = $1
+			AND released_at IS NULL
+			AND (COALESCE(CARDINALITY($2::uuid[]), 0) = 0 OR product_id = ANY($2::uuid[]))
+		RETURNING
+			product_id,
+			quantity,
+			discount_id,
+			discount_quantity
+	`
+
+	err := tx.SelectContext(ctx, &reservations, query, orderId, pq.Array(productIds))
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderId).Msg("repository: ReleaseStock failed")
+		return res, err
+	}
+
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].ProductId < reservations[j].ProductId
+	})
+
+	restockedIds := make([]string, 0, len(reservations))
+
+	for _, reservation := range reservations {
+		var event entity.StockChangedEvent
+
+		query = `
+			UPDATE
+				products
+			SET
+				stock = stock + $2,
+				updated_at = NOW()
+			WHERE
+				id = $1
+				AND deleted_at IS NULL
+			RETURNING
+				id AS product_id,
+				shop_id,
+				stock - $2 AS previous_stock,
+				stock,
+				updated_at AS changed_at
+		`
+
+		restocked := true
+		err = tx.QueryRowxContext(ctx, query, reservation.ProductId, reservation.Quantity).StructScan(&event)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				log.Error().Err(err).Str("order_id", orderId).Msg("repository: ReleaseStock failed")
+				return res, err
+			}
+			log.Warn().Str("product_id", reservation.ProductId).Msg("repository: ReleaseStock skipped a deleted product")
+			restocked = false
+		}
+
+		// the product is locked before the discount, like ReserveStock locks them
+		if reservation.DiscountId != nil && reservation.DiscountQuantity > 0 {
+			query = `
+				UPDATE
+					product_discounts
+				SET
+					sold_count = GREATEST(sold_count - $2, 0),
+					updated_at = NOW()
+				WHERE
+					id = $1
+			`
+
+			if _, err = tx.ExecContext(ctx, query, *reservation.DiscountId, reservation.DiscountQuantity); err != nil {
+				log.Error().Err(err).Str("order_id", orderId).Msg("repository: ReleaseStock failed to release the discount")
+				return res, err
+			}
+		}
+
+		if !restocked {
+			continue
+		}
+
+		err = outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+			AggregateType: outboxEntity.AggregateProduct,
+			AggregateId:   event.ProductId,
+			EventType:     outboxEntity.EventProductStockChanged,
+			ShopId:        event.ShopId,
+			Payload:       event,
+		})
+		if err != nil {
+			return res, err
+		}
+
+		res = append(res, entity.StockLevel{
+			ProductId: event.ProductId,
+			Quantity:  reservation.Quantity,
+			Stock:     event.Stock,
+		})
+		restockedIds = append(restockedIds, event.ProductId)
+	}
+
+	if err = SyncOfferPrices(ctx, tx, restockedIds); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// lockOrder serialises reservations and releases of the same order until the
+// transaction ends, so a retry waits for the attempt still in flight.
+func lockOrder(ctx context.Context, tx *sqlx.Tx, orderId string) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "stock_reservations:"+orderId); err != nil {
+		log.Error().Err(err).Str("order_id", orderId).Msg("repository: Failed to lock order reservations")
+		return err
+	}
+
+	return nil
+}
+
+// orderReservations returns what the order holds and whether it has released
+// all it held.
+func orderReservations(ctx context.Context, tx *sqlx.Tx, orderId string) ([]entity.StockLevel, bool, error) {
+	var (
+		reservations = make([]struct {
+			entity.StockLevel
+			Released bool `db:"released"`
+		}, 0)
+		res = make([]entity.StockLevel, 0)
+	)
+
+	query := `
+		SELECT
+			sr.product_id,
+			sr.quantity,
+			p.stock,
+			sr.released_at IS NOT NULL AS released
+		FROM
+			stock_reservations sr
+		JOIN
+			products p ON p.id = sr.product_id
+		WHERE
+			sr.order_id = $1
+		ORDER BY sr.product_id
+	`
+
+	if err := tx.SelectContext(ctx, &reservations, query, orderId); err != nil {
+		log.Error().Err(err).Str("order_id", orderId).Msg("repository: Failed to get order reservations")
+		return res, false, err
+	}
+
+	for _, reservation := range reservations {
+		if !reservation.Released {
+			res = append(res, reservation.StockLevel)
+		}
+	}
+
+	return res, len(reservations) > 0 && len(res) == 0, nil
+}
+
+// mergeStockItems adds up the quantities of items listed more than once and
+// sorts the lines by product id, the order products are locked in.
+func mergeStockItems(items []entity.StockItem) []stockLine {
+	var (
+		lines = make([]stockLine, 0, len(items))
+		byId  = make(map[string]int, len(items))
+	)
+
+	for i, item := range items {
+		if at, ok := byId[item.ProductId]; ok {
+			lines[at].Quantity += item.Quantity
+			continue
+		}
+
+		byId[item.ProductId] = len(lines)
+		lines = append(lines, stockLine{StockItem: item, index: i})
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].ProductId < lines[j].ProductId
+	})
+
+	return lines
+}
+
+// reserveFailed tells a missing or unpublished product apart from one
+// without enough stock.
+func reserveFailed(ctx context.Context, tx *sqlx.Tx, line stockLine) error {
+	var product struct {
+		Stock     int  `db:"stock"`
+		Published bool `db:"published"`
+	}
+
+	query := `SELECT stock, ` + Published("products") + ` AS published FROM products WHERE id = $1 AND deleted_at IS NULL`
+
+	err := tx.GetContext(ctx, &product, query, line.ProductId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error().Err(err).Any("item", line.StockItem).Msg("repository: ReserveStock failed")
+		return err
+	}
+
+	if err == sql.ErrNoRows || !product.Published {
+		log.Warn().Any("item", line.StockItem).Msg("repository: Product not found")
+		return errmsg.NewCostumErrors(404, errmsg.WithMessage("Product not found"), errmsg.WithErrors(fmt.Sprintf("items.%d.product_id", line.index), "product is not available."))
+	}
+
+	log.Warn().Any("item", line.StockItem).Int("stock", product.Stock).Msg("repository: Insufficient stock")
+	return errmsg.NewCostumErrors(409, errmsg.WithMessage("Insufficient stock"), errmsg.WithErrors(fmt.Sprintf("items.%d.quantity", line.index), fmt.Sprintf("only %d left in stock.", product.Stock)))
+}
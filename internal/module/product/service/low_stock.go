@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/product/entity"
+	"product-service/pkg/errmsg"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (p *productService) GetLowStock(ctx context.Context, req *entity.GetLowStockRequest) (entity.GetLowStockResponse, error) {
+	isShopOwner, err := p.repo.IsShopOwner(ctx, req.UserId, req.ShopId)
+	if err != nil {
+		return entity.GetLowStockResponse{}, err
+	}
+
+	if !isShopOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not shop owner")
+		return entity.GetLowStockResponse{}, errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not shop owner"))
+	}
+
+	return p.repo.GetLowStock(ctx, req)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/product/entity"
+	"product-service/pkg/errmsg"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (p *productService) SubmitProduct(ctx context.Context, req *entity.SubmitProductRequest) (entity.ModerationResponse, error) {
+	var res entity.ModerationResponse
+
+	isProductOwner, err := p.repo.IsProductOwner(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if !isProductOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not product owner")
+		return res, errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not product owner"))
+	}
+
+	res, err = p.repo.SubmitProduct(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	p.cache.invalidate(ctx, res.Id)
+	return res, nil
+}
+
+func (p *productService) GetModerationQueue(ctx context.Context, req *entity.GetModerationQueueRequest) (entity.GetModerationQueueResponse, error) {
+	return p.repo.GetModerationQueue(ctx, req)
+}
+
+func (p *productService) ApproveProduct(ctx context.Context, req *entity.ApproveProductRequest) (entity.ModerationResponse, error) {
+	return p.reviewProduct(ctx, &entity.ReviewProduct{
+		ProductId:  req.ProductId,
+		ReviewerId: req.UserId,
+		Status:     entity.StatusPublished,
+	})
+}
+
+func (p *productService) RejectProduct(ctx context.Context, req *entity.RejectProductRequest) (entity.ModerationResponse, error) {
+	return p.reviewProduct(ctx, &entity.ReviewProduct{
+		ProductId:  req.ProductId,
+		ReviewerId: req.UserId,
+		Status:     entity.StatusRejected,
+		Reason:     &req.Reason,
+	})
+}
+
+func (p *productService) reviewProduct(ctx context.Context, review *entity.ReviewProduct) (entity.ModerationResponse, error) {
+	res, err := p.repo.ReviewProduct(ctx, review)
+	if err != nil {
+		return res, err
+	}
+
+	p.cache.invalidate(ctx, res.Id)
+	return res, nil
+}
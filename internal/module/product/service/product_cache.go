@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"product-service/internal/infrastructure"
+	"product-service/internal/module/product/entity"
+	"product-service/pkg/cache"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	relatedCacheTTL  = 10 * time.Minute
+	relatedCacheSize = 50 // matches the max limit of GetRelatedProductsRequest
+)
+
+// listingsVersionKey versions every cached listing at once, any product
+// change can move a product in or out of any listing.
+const listingsVersionKey = "products:version"
+
+// ratesVersionKey versions every converted product and every related products
+// list, an exchange rate change reprices or rebands all of them.
+const ratesVersionKey = "products:rates:version"
+
+func productVersionKey(productId string) string {
+	return "product:" + productId + ":version"
+}
+
+// productCache reads products and anonymous listings through the cache.
+// Requests with a user are never cached, owners see their unpublished
+// products and listings mark the user's wishlist. Other modules writing to
+// products invalidate them with InvalidateProducts. With the memory driver
+// each process only invalidates its own entries, writes made by another
+// process show once they expire after CACHE_PRODUCT_TTL.
+type productCache struct {
+	cache  cache.Cache
+	loader *cache.Loader
+	ttl    time.Duration
+}
+
+func newProductCache(c cache.Cache) *productCache {
+	return &productCache{
+		cache:  c,
+		loader: cache.NewLoader(c),
+		ttl:    time.Duration(infrastructure.Envs.Cache.ProductTTL) * time.Second,
+	}
+}
+
+func (c *productCache) getProduct(ctx context.Context, req *entity.GetProductRequestById, load func(ctx context.Context) (entity.GetProductResponseById, error)) (entity.GetProductResponseById, error) {
+	if req.UserId != "" {
+		return load(ctx)
+	}
+
+	version, err := c.cache.Version(ctx, productVersionKey(req.ProductId))
+	if err != nil {
+		log.Warn().Err(err).Str("product_id", req.ProductId).Msg("service: Cache unavailable, reading product from the database")
+		return load(ctx)
+	}
+
+	key := fmt.Sprintf("product:%s:v%d:%s", req.ProductId, version, req.Currency)
+	if req.Currency != "" {
+		rates, err := c.cache.Version(ctx, ratesVersionKey)
+		if err != nil {
+			log.Warn().Err(err).Str("product_id", req.ProductId).Msg("service: Cache unavailable, reading product from the database")
+			return load(ctx)
+		}
+		key += fmt.Sprintf(":r%d", rates)
+	}
+
+	return cache.Fetch(ctx, c.loader, key, c.ttl, load)
+}
+
+// getRelated caches the related products of every caller, they don't depend
+// on the user. The key holds the source product's updated_at too, so a
+// change made through a replica with its own memory cache is still picked up.
+func (c *productCache) getRelated(ctx context.Context, productId string, updatedAt time.Time, load func(ctx context.Context) ([]entity.Product, error)) ([]entity.Product, error) {
+	version, err := c.cache.Version(ctx, productVersionKey(productId))
+	if err != nil {
+		log.Warn().Err(err).Str("product_id", productId).Msg("service: Cache unavailable, reading related products from the database")
+		return load(ctx)
+	}
+
+	// the price band converts through the exchange rates
+	rates, err := c.cache.Version(ctx, ratesVersionKey)
+	if err != nil {
+		log.Warn().Err(err).Str("product_id", productId).Msg("service: Cache unavailable, reading related products from the database")
+		return load(ctx)
+	}
+
+	key := fmt.Sprintf("product:%s:v%d:r%d:related:%d", productId, version, rates, updatedAt.UnixNano())
+	return cache.Fetch(ctx, c.loader, key, relatedCacheTTL, load)
+}
+
+func (c *productCache) getProducts(ctx context.Context, req *entity.GetProductsRequest, load func(ctx context.Context) (entity.GetProductsResponse, error)) (entity.GetProductsResponse, error) {
+	if req.UserId != "" {
+		return load(ctx)
+	}
+
+	version, err := c.cache.Version(ctx, listingsVersionKey)
+	if err != nil {
+		log.Warn().Err(err).Msg("service: Cache unavailable, reading products from the database")
+		return load(ctx)
+	}
+
+	// the parsed request identifies the listing, whatever order the query
+	// string had its parameters in
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return load(ctx)
+	}
+	hash := sha256.Sum256(encoded)
+
+	key := fmt.Sprintf("products:v%d:%s", version, hex.EncodeToString(hash[:16]))
+	return cache.Fetch(ctx, c.loader, key, c.ttl, load)
+}
+
+// invalidate moves the products and every listing to new keys. A failure is
+// only logged, the write already happened and the old entries expire.
+func (c *productCache) invalidate(ctx context.Context, productIds ...string) {
+	for _, productId := range productIds {
+		if _, err := c.cache.Bump(ctx, productVersionKey(productId)); err != nil {
+			log.Warn().Err(err).Str("product_id", productId).Msg("service: Failed to invalidate cached product")
+		}
+	}
+
+	if _, err := c.cache.Bump(ctx, listingsVersionKey); err != nil {
+		log.Warn().Err(err).Msg("service: Failed to invalidate cached listings")
+	}
+}
+
+// InvalidateProducts moves the cached products and every listing to new keys
+// after a write made outside the product service, like a discount, a brand
+// rename, a wishlist change or stock released by an order.
+func InvalidateProducts(ctx context.Context, c cache.Cache, productIds ...string) {
+	(&productCache{cache: c}).invalidate(ctx, productIds...)
+}
+
+// InvalidateRates moves every converted product, related products list and
+// listing to new keys after the exchange rates changed.
+func InvalidateRates(ctx context.Context, c cache.Cache) {
+	if _, err := c.Bump(ctx, ratesVersionKey); err != nil {
+		log.Warn().Err(err).Msg("service: Failed to invalidate cached converted products")
+	}
+
+	(&productCache{cache: c}).invalidate(ctx)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/internal/module/product/entity"
+	"product-service/pkg/errmsg"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Quote prices a cart from a single snapshot. Repeated products are merged
+// into one line, lines that can't be fulfilled are listed as unavailable and
+// left out of the totals. The totals are in one currency, the requested one
+// or else the one every product is priced in.
+func (p *productService) Quote(ctx context.Context, req *entity.QuoteRequest) (entity.QuoteResponse, error) {
+	var res entity.QuoteResponse
+	res.Items = make([]entity.QuoteLine, 0, len(req.Items))
+	res.Shops = make([]entity.QuoteShopSubtotal, 0)
+	res.Unavailable = make([]entity.QuoteUnavailableItem, 0)
+
+	items := make([]entity.QuoteItem, 0, len(req.Items))
+	positions := make(map[string]int, len(req.Items))
+	for _, item := range req.Items {
+		if i, ok := positions[item.ProductId]; ok {
+			items[i].Quantity += item.Quantity
+			continue
+		}
+
+		positions[item.ProductId] = len(items)
+		items = append(items, item)
+	}
+
+	productIds := make([]string, 0, len(items))
+	for _, item := range items {
+		productIds = append(productIds, item.ProductId)
+	}
+
+	if err := p.checkConversion(ctx, req.Currency, productIds...); err != nil {
+		return res, err
+	}
+
+	snapshot, err := p.repo.GetQuoteSnapshot(ctx, productIds, req.Currency)
+	if err != nil {
+		return res, err
+	}
+	res.QuotedAt = snapshot.At
+	res.Currency = req.Currency
+
+	products := make(map[string]entity.QuoteProduct, len(snapshot.Products))
+	for _, product := range snapshot.Products {
+		products[product.Id] = product
+	}
+
+	shops := make(map[string]int)
+	for _, item := range items {
+		product, ok := products[item.ProductId]
+		switch {
+		case !ok:
+			res.Unavailable = append(res.Unavailable, entity.QuoteUnavailableItem{
+				ProductId: item.ProductId,
+				Quantity:  item.Quantity,
+				Reason:    entity.UnavailableNotFound,
+			})
+			continue
+		case product.Stock <= 0:
+			res.Unavailable = append(res.Unavailable, entity.QuoteUnavailableItem{
+				ProductId: item.ProductId,
+				Quantity:  item.Quantity,
+				Reason:    entity.UnavailableOutOfStock,
+			})
+			continue
+		case product.Stock < item.Quantity:
+			res.Unavailable = append(res.Unavailable, entity.QuoteUnavailableItem{
+				ProductId:      item.ProductId,
+				Quantity:       item.Quantity,
+				AvailableStock: product.Stock,
+				Reason:         entity.UnavailableInsufficientStock,
+			})
+			continue
+		}
+
+		if res.Currency == "" {
+			res.Currency = product.Currency
+		}
+
+		// the price stays in the product's currency when there is no rate
+		if product.Currency != res.Currency {
+			log.Warn().Any("payload", req).Str("product_id", product.Id).Msg("service: Cart mixes currencies")
+			return res, errmsg.NewCostumErrors(422,
+				errmsg.WithMessage("Products can't be quoted in one currency"),
+				errmsg.WithErrors("items", fmt.Sprintf("product %s is priced in %s and can't be quoted in %s, set currency to quote the cart in one.", product.Id, product.Currency, res.Currency)),
+			)
+		}
+
+		line := entity.QuoteLine{
+			ProductId:       product.Id,
+			ShopId:          product.ShopId,
+			Name:            product.Name,
+			Quantity:        item.Quantity,
+			Currency:        product.Currency,
+			OriginalPrice:   product.OriginalPrice,
+			UnitPrice:       product.FinalPrice,
+			DiscountPercent: product.DiscountPercent,
+			Offer:           appliedOffer(product),
+		}
+
+		i, ok := shops[line.ShopId]
+		if !ok {
+			i = len(res.Shops)
+			shops[line.ShopId] = i
+			res.Shops = append(res.Shops, entity.QuoteShopSubtotal{ShopId: line.ShopId})
+		}
+		shop := &res.Shops[i]
+
+		if err := addLine(&line, shop, &res, product, item.Quantity); err != nil {
+			log.Warn().Err(err).Any("payload", req).Msg("service: Quote total out of range")
+			return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("items", "cart total is too large."))
+		}
+		res.Items = append(res.Items, line)
+	}
+
+	return res, nil
+}
+
+// addLine prices the line and adds it to the shop subtotal and the quote
+// totals. It fails with decimal.ErrRange when an amount doesn't fit.
+func addLine(line *entity.QuoteLine, shop *entity.QuoteShopSubtotal, res *entity.QuoteResponse, product entity.QuoteProduct, quantity int) error {
+	unitDiscount, err := product.OriginalPrice.Sub(product.FinalPrice)
+	if err != nil {
+		return err
+	}
+
+	if line.LineDiscount, err = unitDiscount.Mul(int64(quantity)); err != nil {
+		return err
+	}
+
+	if line.LineTotal, err = product.FinalPrice.Mul(int64(quantity)); err != nil {
+		return err
+	}
+
+	shop.Items += quantity
+	if shop.Discount, err = shop.Discount.Add(line.LineDiscount); err != nil {
+		return err
+	}
+
+	if shop.Subtotal, err = shop.Subtotal.Add(line.LineTotal); err != nil {
+		return err
+	}
+
+	if res.Discount, err = res.Discount.Add(line.LineDiscount); err != nil {
+		return err
+	}
+
+	res.Total, err = res.Total.Add(line.LineTotal)
+	return err
+}
+
+// appliedOffer is the offer that produced the product's final price, nil
+// when it sells at its regular price.
+func appliedOffer(product entity.QuoteProduct) *entity.QuoteOffer {
+	if product.OfferId == nil || !product.FinalPrice.LessThan(product.OriginalPrice) {
+		return nil
+	}
+
+	return &entity.QuoteOffer{
+		Source:    *product.OfferSource,
+		Id:        *product.OfferId,
+		UnitPrice: product.FinalPrice,
+		EndsAt:    *product.OfferEndsAt,
+	}
+}
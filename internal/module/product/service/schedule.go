@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/product/entity"
+)
+
+// scheduleBatchSize caps how many products one run announces per event type,
+// a run announcing a full batch is followed by another straight away.
+const scheduleBatchSize = 100
+
+// RunSchedule announces the products that went live or offline on their
+// schedule. Visibility itself is computed by the queries, so a late or
+// missed run only delays the events.
+func (p *productService) RunSchedule(ctx context.Context) (entity.ScheduleRun, error) {
+	var total entity.ScheduleRun
+
+	for {
+		run, err := p.repo.EmitScheduledEvents(ctx, scheduleBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total.Published += run.Published
+		total.Unpublished += run.Unpublished
+		total.ProductIds = append(total.ProductIds, run.ProductIds...)
+
+		if run.Published < scheduleBatchSize && run.Unpublished < scheduleBatchSize {
+			break
+		}
+	}
+
+	// cached products and listings were computed before they went live or
+	// offline
+	if len(total.ProductIds) > 0 {
+		p.cache.invalidate(ctx, total.ProductIds...)
+	}
+
+	return total, nil
+}
@@ -2,20 +2,28 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"product-service/internal/infrastructure"
 	"product-service/internal/module/product/entity"
 	"product-service/internal/module/product/ports"
+	"product-service/pkg/cache"
 	"product-service/pkg/errmsg"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
 type productService struct {
-	repo ports.ProductRepository
+	repo  ports.ProductRepository
+	cache *productCache
 }
 
-func NewProductService(r ports.ProductRepository) ports.ProductService {
+func NewProductService(r ports.ProductRepository, c cache.Cache) ports.ProductService {
 	return &productService{
-		repo: r,
+		repo:  r,
+		cache: newProductCache(c),
 	}
 }
 
@@ -32,28 +40,110 @@ func (p *productService) CreateProduct(ctx context.Context, req *entity.CreatePr
 		return res, errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not shop owner"))
 	}
 
+	if err = p.checkBrand(ctx, req.BrandId); err != nil {
+		return res, err
+	}
+
+	if req.Currency == "" {
+		req.Currency = infrastructure.Envs.Product.DefaultCurrency
+	}
+
+	if err = p.checkCurrency(ctx, req.Currency); err != nil {
+		return res, err
+	}
+
+	if err = p.checkAttributes(ctx, req.CategoryId, req.Attributes); err != nil {
+		return res, err
+	}
+
+	if err = checkSchedule(req.PublishAt, req.UnpublishAt); err != nil {
+		return res, err
+	}
+
 	res, err = p.repo.CreateProduct(ctx, req)
 	if err != nil {
 		return res, err
 	}
 
+	p.cache.invalidate(ctx, res.Id)
+
 	return res, nil
 }
 
 func (p *productService) GetProducts(ctx context.Context, req *entity.GetProductsRequest) (entity.GetProductsResponse, error) {
+	if err := p.checkConversion(ctx, req.Currency); err != nil {
+		return entity.GetProductsResponse{}, err
+	}
+
+	return p.cache.getProducts(ctx, req, func(ctx context.Context) (entity.GetProductsResponse, error) {
+		return p.getProducts(ctx, req)
+	})
+}
+
+func (p *productService) getProducts(ctx context.Context, req *entity.GetProductsRequest) (entity.GetProductsResponse, error) {
 	res, err := p.repo.GetProducts(ctx, req)
 	if err != nil {
 		return res, err
 	}
 
-	if len(res.Items) == 0 {
+	// an empty page with facets is still an answer, the facets show how to
+	// broaden the filters
+	if len(res.Items) == 0 && len(req.FacetList) == 0 {
 		log.Warn().Any("payload", req).Msg("service: Products not found")
 		return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Products not found"))
 	}
 
+	if res.Items == nil {
+		res.Items = make([]entity.Product, 0)
+	}
+
+	if len(req.FacetList) > 0 {
+		if len(req.PriceBuckets) == 0 {
+			req.PriceBuckets, err = entity.ParsePriceBuckets(infrastructure.Envs.Product.FacetPriceBuckets)
+			if err != nil {
+				log.Error().Err(err).Msg("service: Invalid PRODUCT_FACET_PRICE_BUCKETS config")
+				return res, err
+			}
+		}
+
+		facets, err := p.repo.GetProductFacets(ctx, req)
+		if err != nil {
+			return res, err
+		}
+		res.Facets = &facets
+	}
+
+	return res, nil
+}
+
+// GetShopsProducts returns the page of every shop's listing, an empty page for
+// shops with nothing on it. Facets aren't counted.
+func (p *productService) GetShopsProducts(ctx context.Context, shopIds []string, req *entity.GetProductsRequest) (map[string]entity.GetProductsResponse, error) {
+	if err := p.checkConversion(ctx, req.Currency); err != nil {
+		return nil, err
+	}
+
+	res, err := p.repo.GetShopsProducts(ctx, shopIds, req)
+	if err != nil {
+		return res, err
+	}
+
+	for _, shopId := range shopIds {
+		if _, ok := res[shopId]; !ok {
+			page := entity.GetProductsResponse{Items: make([]entity.Product, 0)}
+			page.Meta.Page = req.Page
+			page.Meta.Limit = req.Limit
+			res[shopId] = page
+		}
+	}
+
 	return res, nil
 }
 
+func (p *productService) GetRecommendedProducts(ctx context.Context, req *entity.GetRecommendedProductsRequest) (entity.GetProductsResponse, error) {
+	return p.repo.GetRecommendedProducts(ctx, req)
+}
+
 func (p *productService) UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (entity.UpsertProductResponse, error) {
 	var res entity.UpsertProductResponse
 
@@ -67,11 +157,29 @@ func (p *productService) UpdateProduct(ctx context.Context, req *entity.UpdatePr
 		return res, errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not product owner"))
 	}
 
+	if err = p.checkBrand(ctx, req.BrandId); err != nil {
+		return res, err
+	}
+
+	if err = p.checkCurrency(ctx, req.Currency); err != nil {
+		return res, err
+	}
+
+	if err = checkSchedule(req.PublishAt, req.UnpublishAt); err != nil {
+		return res, err
+	}
+
+	if err = p.checkUpdatedAttributes(ctx, req); err != nil {
+		return res, err
+	}
+
 	res, err = p.repo.UpdateProduct(ctx, req)
 	if err != nil {
 		return res, err
 	}
 
+	p.cache.invalidate(ctx, req.Id)
+
 	return res, nil
 }
 
@@ -86,14 +194,302 @@ func (p *productService) DeleteProduct(ctx context.Context, req *entity.DeletePr
 		return errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not product owner"))
 	}
 
-	return p.repo.DeleteProduct(ctx, req)
+	if err := p.repo.DeleteProduct(ctx, req); err != nil {
+		return err
+	}
+
+	p.cache.invalidate(ctx, req.ProductId)
+	return nil
 }
 
 func (p *productService) GetProductById(ctx context.Context, req *entity.GetProductRequestById) (entity.GetProductResponseById, error) {
+	if err := p.checkConversion(ctx, req.Currency, req.ProductId); err != nil {
+		return entity.GetProductResponseById{}, err
+	}
+
+	return p.cache.getProduct(ctx, req, func(ctx context.Context) (entity.GetProductResponseById, error) {
+		return p.getProductById(ctx, req)
+	})
+}
+
+func (p *productService) getProductById(ctx context.Context, req *entity.GetProductRequestById) (entity.GetProductResponseById, error) {
 	res, err := p.repo.GetProductById(ctx, req)
 	if err != nil {
 		return res, err
 	}
 
+	res.LowestPrice30d, err = p.repo.GetLowestPrice30d(ctx, res.Id, req.Currency)
+	if err != nil {
+		return res, err
+	}
+
 	return res, nil
 }
+
+// BatchGetProducts returns the published products in the order they were
+// asked for, each once, and lists the ids that aren't available.
+func (p *productService) BatchGetProducts(ctx context.Context, req *entity.BatchGetProductsRequest) (entity.BatchGetProductsResponse, error) {
+	var (
+		res = entity.BatchGetProductsResponse{
+			Items:      make([]entity.GetProductResponseById, 0, len(req.ProductIds)),
+			MissingIds: make([]string, 0),
+		}
+		ids = make([]string, 0, len(req.ProductIds))
+	)
+
+	for _, id := range req.ProductIds {
+		id = strings.ToLower(id)
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	if err := p.checkConversion(ctx, req.Currency, ids...); err != nil {
+		return res, err
+	}
+
+	products, err := p.repo.GetProductsByIds(ctx, ids, req.Currency)
+	if err != nil {
+		return res, err
+	}
+
+	byId := make(map[string]entity.GetProductResponseById, len(products))
+	for _, product := range products {
+		byId[product.Id] = product
+	}
+
+	for _, id := range ids {
+		product, ok := byId[id]
+		if !ok {
+			res.MissingIds = append(res.MissingIds, id)
+			continue
+		}
+
+		res.Items = append(res.Items, product)
+	}
+
+	return res, nil
+}
+
+func (p *productService) GetCategoriesByIds(ctx context.Context, ids []string) ([]entity.Category, error) {
+	return p.repo.GetCategoriesByIds(ctx, ids)
+}
+
+func (p *productService) GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (entity.GetPriceHistoryResponse, error) {
+	var res entity.GetPriceHistoryResponse
+
+	// surfaces the 404 for unknown products
+	_, err := p.repo.GetProductById(ctx, &entity.GetProductRequestById{ProductId: req.ProductId})
+	if err != nil {
+		return res, err
+	}
+
+	return p.repo.GetPriceHistory(ctx, req)
+}
+
+func (p *productService) GetRelatedProducts(ctx context.Context, req *entity.GetRelatedProductsRequest) (entity.GetRelatedProductsResponse, error) {
+	var res entity.GetRelatedProductsResponse
+
+	source, err := p.repo.GetProductById(ctx, &entity.GetProductRequestById{ProductId: req.ProductId})
+	if err != nil {
+		return res, err
+	}
+
+	// always compute the largest page so every limit is served from one entry
+	items, err := p.cache.getRelated(ctx, req.ProductId, source.UpdatedAt, func(ctx context.Context) ([]entity.Product, error) {
+		related, err := p.repo.GetRelatedProducts(ctx, &entity.GetRelatedProductsRequest{
+			ProductId: req.ProductId,
+			Limit:     relatedCacheSize,
+		})
+		return related.Items, err
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if len(items) > req.Limit {
+		items = items[:req.Limit]
+	}
+
+	res.Items = items
+	return res, nil
+}
+
+// checkBrand rejects unknown or deleted brands, an empty brand id is allowed.
+func (p *productService) checkBrand(ctx context.Context, brandId *string) error {
+	if brandId == nil {
+		return nil
+	}
+
+	exist, err := p.repo.IsBrandExist(ctx, *brandId)
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		log.Warn().Str("brand_id", *brandId).Msg("service: Brand not found")
+		return errmsg.NewCostumErrors(422, errmsg.WithMessage("Brand not found"), errmsg.WithErrors("brand_id", "brand id is not exist."))
+	}
+
+	return nil
+}
+
+// checkCurrency accepts an empty currency, callers default it when needed.
+func (p *productService) checkCurrency(ctx context.Context, currency string) error {
+	if currency == "" {
+		return nil
+	}
+
+	exist, err := p.repo.IsCurrencyExist(ctx, currency)
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		log.Warn().Str("currency", currency).Msg("service: Currency not supported")
+		return errmsg.NewCostumErrors(422, errmsg.WithMessage("Currency not supported"), errmsg.WithErrors("currency", "currency is not supported."))
+	}
+
+	return nil
+}
+
+// checkConversion is checkCurrency for reads converting the products in ids,
+// or any product when ids is empty. A product without a rate to currency
+// fails the read, prices are never shown unconverted in its place.
+func (p *productService) checkConversion(ctx context.Context, currency string, ids ...string) error {
+	if err := p.checkCurrency(ctx, currency); err != nil || currency == "" {
+		return err
+	}
+
+	missing, err := p.repo.GetUnconvertibleCurrencies(ctx, currency, ids)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		log.Warn().Str("currency", currency).Strs("from", missing).Msg("service: Exchange rate not found")
+		return errmsg.NewCostumErrors(422, errmsg.WithMessage("Exchange rate not found"), errmsg.WithErrors("currency", fmt.Sprintf("no exchange rate from %s to %s.", strings.Join(missing, ", "), currency)))
+	}
+
+	return nil
+}
+
+// checkSchedule accepts any publish_at, a past one publishes right away, but
+// the product must not be unpublished before it is published.
+func checkSchedule(publishAt, unpublishAt *time.Time) error {
+	if unpublishAt == nil {
+		return nil
+	}
+
+	if !unpublishAt.After(time.Now()) {
+		return errmsg.NewCostumErrors(422, errmsg.WithErrors("unpublish_at", "unpublish at must be in the future."))
+	}
+
+	if publishAt != nil && !unpublishAt.After(*publishAt) {
+		return errmsg.NewCostumErrors(422, errmsg.WithErrors("unpublish_at", "unpublish at must be after publish at."))
+	}
+
+	return nil
+}
+
+// checkUpdatedAttributes checks the attributes the product has after the
+// update: the new ones, or the stored ones when only the category changes,
+// which may not fit the new category.
+func (p *productService) checkUpdatedAttributes(ctx context.Context, req *entity.UpdateProductRequest) error {
+	if req.Attributes == nil && req.CategoryId == "" {
+		return nil
+	}
+
+	current, err := p.repo.GetProductById(ctx, &entity.GetProductRequestById{ProductId: req.Id, UserId: req.UserId})
+	if err != nil {
+		return err
+	}
+
+	var (
+		categoryId = current.CategoryId
+		attributes = req.Attributes
+	)
+
+	if req.CategoryId != "" {
+		if req.Attributes == nil && strings.EqualFold(req.CategoryId, current.CategoryId) {
+			return nil
+		}
+		categoryId = req.CategoryId
+	}
+
+	if attributes == nil {
+		attributes = current.Attributes
+	}
+
+	return p.checkAttributes(ctx, categoryId, attributes)
+}
+
+// checkAttributes validates attribute values against the definitions of the
+// product category. The map replaces the stored attributes, so required
+// attributes must always be present.
+func (p *productService) checkAttributes(ctx context.Context, categoryId string, attributes entity.Attributes) error {
+	definitions, err := p.repo.GetAttributeDefinitions(ctx, categoryId)
+	if err != nil {
+		return err
+	}
+
+	var (
+		byCode = make(map[string]entity.AttributeDefinition, len(definitions))
+		opts   = []errmsg.Option{errmsg.WithMessage("Invalid product attributes")}
+	)
+
+	for _, definition := range definitions {
+		byCode[definition.Code] = definition
+
+		if _, ok := attributes[definition.Code]; definition.IsRequired && !ok {
+			opts = append(opts, errmsg.WithErrors("attributes."+definition.Code, definition.Code+" is required."))
+		}
+	}
+
+	for code, value := range attributes {
+		definition, ok := byCode[code]
+		if !ok {
+			opts = append(opts, errmsg.WithErrors("attributes."+code, code+" is not an attribute of this category."))
+			continue
+		}
+
+		if msg := checkAttributeValue(definition, value); msg != "" {
+			opts = append(opts, errmsg.WithErrors("attributes."+code, msg))
+		}
+	}
+
+	if len(opts) > 1 {
+		log.Warn().Any("attributes", attributes).Str("category_id", categoryId).Msg("service: Invalid product attributes")
+		return errmsg.NewCostumErrors(422, opts...)
+	}
+
+	return nil
+}
+
+func checkAttributeValue(definition entity.AttributeDefinition, value any) string {
+	switch definition.Type {
+	case entity.AttributeTypeText:
+		text, ok := value.(string)
+		if !ok {
+			return definition.Code + " must be a text."
+		}
+		if len(text) > 255 {
+			return definition.Code + " must not be greater than 255 characters."
+		}
+	case entity.AttributeTypeNumber:
+		if _, ok := value.(float64); !ok {
+			return definition.Code + " must be a number."
+		}
+	case entity.AttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return definition.Code + " must be a boolean."
+		}
+	case entity.AttributeTypeEnum:
+		text, ok := value.(string)
+		if !ok || !slices.Contains(definition.Options, text) {
+			return definition.Code + " must be one of " + strings.Join(definition.Options, " ") + "."
+		}
+	}
+
+	return ""
+}
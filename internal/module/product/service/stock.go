@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/product/entity"
+	"strings"
+)
+
+// ReserveStock merges items by product id, so ids are compared lowercased.
+func (p *productService) ReserveStock(ctx context.Context, req *entity.ReserveStockRequest) (entity.StockResponse, error) {
+	for i := range req.Items {
+		req.Items[i].ProductId = strings.ToLower(req.Items[i].ProductId)
+	}
+
+	res, err := p.repo.ReserveStock(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	p.cache.invalidate(ctx, stockProductIds(res)...)
+
+	return res, nil
+}
+
+func (p *productService) ReleaseStock(ctx context.Context, req *entity.ReleaseStockRequest) (entity.StockResponse, error) {
+	for i := range req.ProductIds {
+		req.ProductIds[i] = strings.ToLower(req.ProductIds[i])
+	}
+
+	res, err := p.repo.ReleaseStock(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	p.cache.invalidate(ctx, stockProductIds(res)...)
+
+	return res, nil
+}
+
+func stockProductIds(res entity.StockResponse) []string {
+	ids := make([]string, 0, len(res.Items))
+	for _, item := range res.Items {
+		ids = append(ids, item.ProductId)
+	}
+
+	return ids
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	productEntity "product-service/internal/module/product/entity"
+	"time"
+)
+
+type CreateReviewRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId string  `params:"id" validate:"required,uuid"`
+	Rating    int     `json:"rating" validate:"required,min=1,max=5"`
+	Comment   *string `json:"comment" validate:"omitempty,max=2000"`
+}
+
+type DeleteReviewRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId string `params:"id" validate:"required,uuid"`
+	ReviewId  string `params:"review_id" validate:"required,uuid"`
+}
+
+type GetReviewsRequest struct {
+	ProductId string `params:"id" validate:"required,uuid"`
+
+	Page  int `query:"page" validate:"required,min=1"`
+	Limit int `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetReviewsRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type ReviewResponse struct {
+	Id        string    `json:"id" db:"id"`
+	ProductId string    `json:"product_id" db:"product_id"`
+	UserId    string    `json:"user_id" db:"user_id"`
+	Rating    int       `json:"rating" db:"rating"`
+	Comment   *string   `json:"comment" db:"comment"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type GetReviewsResponse struct {
+	Items []ReviewResponse   `json:"items"`
+	Meta  productEntity.Meta `json:"meta"`
+}
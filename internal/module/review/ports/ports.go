@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/review/entity"
+)
+
+type ReviewService interface {
+	CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (entity.ReviewResponse, error)
+	GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (entity.GetReviewsResponse, error)
+	GetProductsReviews(ctx context.Context, productIds []string, req *entity.GetReviewsRequest) (map[string]entity.GetReviewsResponse, error)
+	DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error
+}
+
+type ReviewRepository interface {
+	CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (entity.ReviewResponse, error)
+	GetProductsReviews(ctx context.Context, productIds []string, req *entity.GetReviewsRequest) (map[string]entity.GetReviewsResponse, error)
+	DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error
+
+	IsProductExist(ctx context.Context, productId string) (bool, error)
+	IsReviewed(ctx context.Context, userId, productId string) (bool, error)
+}
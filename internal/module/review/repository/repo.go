@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"product-service/internal/module/review/entity"
+	"product-service/internal/module/review/ports"
+	"product-service/pkg/errmsg"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+type reviewRepository struct {
+	db *sqlx.DB
+}
+
+func NewReviewRepository(db *sqlx.DB) ports.ReviewRepository {
+	return &reviewRepository{
+		db: db,
+	}
+}
+
+func (r *reviewRepository) CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (entity.ReviewResponse, error) {
+	var (
+		res entity.ReviewResponse
+	)
+
+	query := `
+		INSERT INTO
+			product_reviews (product_id, user_id, rating, comment)
+		VALUES ($1, $2, $3, $4)
+		RETURNING
+			id, product_id, user_id, rating, comment, created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, req.ProductId, req.UserId, req.Rating, req.Comment).StructScan(&res)
+	if err != nil {
+		// a concurrent review of the same user hit the unique index
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			log.Warn().Any("payload", req).Msg("repository: Product already reviewed")
+			return res, errmsg.NewCostumErrors(409, errmsg.WithMessage("Product already reviewed"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateReview failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetProductsReviews returns the same page of the reviews of every product in
+// one query, each product's reviews are ranked on their own and the page is
+// cut from every ranking. Products without reviews on the page are left out.
+func (r *reviewRepository) GetProductsReviews(ctx context.Context, productIds []string, req *entity.GetReviewsRequest) (map[string]entity.GetReviewsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.ReviewResponse
+	}
+	var (
+		res  = make(map[string]entity.GetReviewsResponse, len(productIds))
+		data = make([]dao, 0)
+	)
+
+	query := `
+		SELECT
+			total_data,
+			id,
+			product_id,
+			user_id,
+			rating,
+			comment,
+			created_at,
+			updated_at
+		FROM (
+			SELECT
+				COUNT(*) OVER (PARTITION BY product_id) AS total_data,
+				ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY created_at DESC, id) AS product_rank,
+				id,
+				product_id,
+				user_id,
+				rating,
+				comment,
+				created_at,
+				updated_at
+			FROM
+				product_reviews
+			WHERE
+				product_id = ANY($1)
+				AND deleted_at IS NULL
+		) ranked
+		WHERE
+			product_rank > $2
+			AND product_rank <= $2 + $3
+		ORDER BY product_id, product_rank
+	`
+
+	err := r.db.SelectContext(ctx, &data, query, pq.Array(productIds), (req.Page-1)*req.Limit, req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetProductsReviews failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		page, ok := res[d.ProductId]
+		if !ok {
+			page.Items = make([]entity.ReviewResponse, 0, req.Limit)
+			page.Meta.Page = req.Page
+			page.Meta.Limit = req.Limit
+		}
+
+		page.Items = append(page.Items, d.ReviewResponse)
+		page.Meta.TotalData = d.TotalData
+		res[d.ProductId] = page
+	}
+
+	for productId, page := range res {
+		page.Meta.CountTotalPage()
+		res[productId] = page
+	}
+
+	return res, nil
+}
+
+func (r *reviewRepository) DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error {
+	query := `
+		UPDATE
+			product_reviews
+		SET
+			deleted_at = NOW()
+		WHERE
+			id = $1
+			AND product_id = $2
+			AND user_id = $3
+			AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, req.ReviewId, req.ProductId, req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteReview failed")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteReview failed")
+		return err
+	}
+
+	// only the author deletes a review, others don't learn it exists
+	if affected == 0 {
+		log.Warn().Any("payload", req).Msg("repository: Review not found")
+		return errmsg.NewCostumErrors(404, errmsg.WithMessage("Review not found"))
+	}
+
+	return nil
+}
+
+func (r *reviewRepository) IsProductExist(ctx context.Context, productId string) (bool, error) {
+	var exist bool
+
+	query := `SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)`
+
+	err := r.db.GetContext(ctx, &exist, query, productId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", productId).Msg("repository: IsProductExist failed")
+		return exist, err
+	}
+
+	return exist, nil
+}
+
+func (r *reviewRepository) IsReviewed(ctx context.Context, userId, productId string) (bool, error) {
+	var (
+		isReviewed bool
+		payload    = struct {
+			UserId    string `json:"user_id"`
+			ProductId string `json:"product_id"`
+		}{userId, productId}
+	)
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM
+					product_reviews
+				WHERE
+					user_id = $1
+					AND product_id = $2
+					AND deleted_at IS NULL
+			)
+	`
+
+	err := r.db.GetContext(ctx, &isReviewed, query, userId, productId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", payload).Msg("repository: IsReviewed failed")
+		return isReviewed, err
+	}
+
+	return isReviewed, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/review/entity"
+	"product-service/internal/module/review/ports"
+	"product-service/pkg/errmsg"
+
+	"github.com/rs/zerolog/log"
+)
+
+type reviewService struct {
+	repo ports.ReviewRepository
+}
+
+func NewReviewService(r ports.ReviewRepository) ports.ReviewService {
+	return &reviewService{
+		repo: r,
+	}
+}
+
+func (s *reviewService) CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (entity.ReviewResponse, error) {
+	var res entity.ReviewResponse
+
+	exist, err := s.repo.IsProductExist(ctx, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if !exist {
+		log.Warn().Any("payload", req).Msg("service: Product not found")
+		return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Product not found"))
+	}
+
+	isReviewed, err := s.repo.IsReviewed(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if isReviewed {
+		log.Warn().Any("payload", req).Msg("service: Product already reviewed")
+		return res, errmsg.NewCostumErrors(409, errmsg.WithMessage("Product already reviewed"))
+	}
+
+	return s.repo.CreateReview(ctx, req)
+}
+
+func (s *reviewService) GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (entity.GetReviewsResponse, error) {
+	found, err := s.repo.GetProductsReviews(ctx, []string{req.ProductId}, req)
+	if err != nil {
+		return entity.GetReviewsResponse{}, err
+	}
+
+	res, ok := found[req.ProductId]
+	if !ok {
+		res.Items = make([]entity.ReviewResponse, 0)
+		res.Meta.Page = req.Page
+		res.Meta.Limit = req.Limit
+	}
+
+	return res, nil
+}
+
+func (s *reviewService) GetProductsReviews(ctx context.Context, productIds []string, req *entity.GetReviewsRequest) (map[string]entity.GetReviewsResponse, error) {
+	return s.repo.GetProductsReviews(ctx, productIds, req)
+}
+
+func (s *reviewService) DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error {
+	return s.repo.DeleteReview(ctx, req)
+}
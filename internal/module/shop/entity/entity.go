@@ -21,6 +21,14 @@ type UpsertShopResponse struct {
 	UpdatedAt string `json:"updated_at" db:"updated_at"`
 }
 
+// ShopEvent is the outbox payload of the shop.created, shop.updated and
+// shop.deleted events.
+type ShopEvent struct {
+	ShopId string `json:"shop_id" db:"shop_id"`
+	UserId string `json:"user_id" db:"user_id"`
+	Name   string `json:"name" db:"name"`
+}
+
 type DeleteShopRequest struct {
 	Id     string `query:"id" validate:"required,uuid"`
 	UserId string `query:"user_id" validate:"required,uuid"`
@@ -9,6 +9,7 @@ type ShopService interface {
 	CreateShop(ctx context.Context, req *entity.CreateShopRequest) (entity.UpsertShopResponse, error)
 	DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error
 	GetShops(ctx context.Context, req *entity.GetShopsRequest) (entity.GetShopsResponse, error)
+	GetShopsByIds(ctx context.Context, ids []string) ([]entity.ShopItem, error)
 	UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (entity.UpsertShopResponse, error)
 }
 
@@ -16,6 +17,7 @@ type ShopRepository interface {
 	CreateShop(ctx context.Context, req *entity.CreateShopRequest) (entity.UpsertShopResponse, error)
 	DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error
 	GetShops(ctx context.Context, req *entity.GetShopsRequest) (entity.GetShopsResponse, error)
+	GetShopsByIds(ctx context.Context, ids []string) ([]entity.ShopItem, error)
 	UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (entity.UpsertShopResponse, error)
 	IsHaveShop(ctx context.Context, UserId string) (bool, error)
 }
@@ -3,11 +3,14 @@ package repository
 import (
 	"context"
 	"database/sql"
+	outboxEntity "product-service/internal/module/outbox/entity"
+	outboxRepository "product-service/internal/module/outbox/repository"
 	"product-service/internal/module/shop/entity"
 	"product-service/internal/module/shop/ports"
 	"product-service/pkg/errmsg"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 )
 
@@ -26,6 +29,13 @@ func (s *shopRepo) CreateShop(ctx context.Context, req *entity.CreateShopRequest
 		res = entity.UpsertShopResponse{}
 	)
 
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: Failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO
 			shops (user_id, name)
@@ -38,7 +48,7 @@ func (s *shopRepo) CreateShop(ctx context.Context, req *entity.CreateShopRequest
 			updated_at
 	`
 
-	err := s.db.QueryRowContext(ctx, query, req.UserId, req.Name).Scan(
+	err = tx.QueryRowContext(ctx, query, req.UserId, req.Name).Scan(
 		&res.Id,
 		&res.UserId,
 		&res.Name,
@@ -50,6 +60,15 @@ func (s *shopRepo) CreateShop(ctx context.Context, req *entity.CreateShopRequest
 		return res, err
 	}
 
+	if err = enqueueShopEvent(ctx, tx, outboxEntity.EventShopCreated, res); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: Failed to commit transaction")
+		return res, err
+	}
+
 	return res, nil
 }
 
@@ -66,6 +85,15 @@ func (s *shopRepo) IsHaveShop(ctx context.Context, UserId string) (bool, error)
 }
 
 func (s *shopRepo) DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error {
+	var res entity.UpsertShopResponse
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE
 			shops
@@ -75,14 +103,28 @@ func (s *shopRepo) DeleteShop(ctx context.Context, req *entity.DeleteShopRequest
 			user_id = $1
 			AND id = $2
 			AND deleted_at IS NULL
+		RETURNING
+			id, user_id, name, created_at, updated_at
 	`
 
-	_, err := s.db.ExecContext(ctx, query, req.UserId, req.Id)
+	err = tx.QueryRowxContext(ctx, query, req.UserId, req.Id).StructScan(&res)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil // nothing to delete
+		}
 		log.Error().Err(err).Any("payload", req).Msg("repository: Failed to delete shop")
 		return err
 	}
 
+	if err = enqueueShopEvent(ctx, tx, outboxEntity.EventShopDeleted, res); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: Failed to commit transaction")
+		return err
+	}
+
 	return nil
 }
 
@@ -154,11 +196,49 @@ func (s *shopRepo) GetShops(ctx context.Context, req *entity.GetShopsRequest) (e
 	return res, nil
 }
 
+// GetShopsByIds returns the shops among the ids in any order, deleted shops
+// are left out.
+func (s *shopRepo) GetShopsByIds(ctx context.Context, ids []string) ([]entity.ShopItem, error) {
+	var (
+		res = make([]entity.ShopItem, 0, len(ids))
+	)
+
+	query := `
+		SELECT
+			id,
+			user_id,
+			name,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM
+			shops
+		WHERE
+			id = ANY($1::uuid[])
+			AND deleted_at IS NULL
+	`
+
+	err := s.db.SelectContext(ctx, &res, query, pq.Array(ids))
+	if err != nil {
+		log.Error().Err(err).Any("payload", ids).Msg("repository: Failed to get shops by ids")
+		return res, err
+	}
+
+	return res, nil
+}
+
 func (s *shopRepo) UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (entity.UpsertShopResponse, error) {
 	var (
 		res = entity.UpsertShopResponse{}
 	)
 
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: Failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE
 			shops
@@ -172,7 +252,7 @@ func (s *shopRepo) UpdateShop(ctx context.Context, req *entity.UpdateShopRequest
 			id, user_id, name, created_at, updated_at
 	`
 
-	err := s.db.QueryRowxContext(ctx, query, req.Name, req.UserId, req.Id).StructScan(&res)
+	err = tx.QueryRowxContext(ctx, query, req.Name, req.UserId, req.Id).StructScan(&res)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Warn().Any("payload", req).Msg("repository: Shop not found")
@@ -183,5 +263,27 @@ func (s *shopRepo) UpdateShop(ctx context.Context, req *entity.UpdateShopRequest
 		return res, err
 	}
 
+	if err = enqueueShopEvent(ctx, tx, outboxEntity.EventShopUpdated, res); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: Failed to commit transaction")
+		return res, err
+	}
+
 	return res, nil
 }
+
+func enqueueShopEvent(ctx context.Context, tx *sqlx.Tx, eventType string, shop entity.UpsertShopResponse) error {
+	return outboxRepository.Enqueue(ctx, tx, outboxEntity.Event{
+		AggregateType: outboxEntity.AggregateShop,
+		AggregateId:   shop.Id,
+		EventType:     eventType,
+		Payload: entity.ShopEvent{
+			ShopId: shop.Id,
+			UserId: shop.UserId,
+			Name:   shop.Name,
+		},
+	})
+}
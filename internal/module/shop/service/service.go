@@ -51,6 +51,10 @@ func (s *shopService) GetShops(ctx context.Context, req *entity.GetShopsRequest)
 	return s.repo.GetShops(ctx, req)
 }
 
+func (s *shopService) GetShopsByIds(ctx context.Context, ids []string) ([]entity.ShopItem, error) {
+	return s.repo.GetShopsByIds(ctx, ids)
+}
+
 func (s *shopService) UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (entity.UpsertShopResponse, error) {
 	return s.repo.UpdateShop(ctx, req)
 }
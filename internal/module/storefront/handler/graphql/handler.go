@@ -0,0 +1,194 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"product-service/internal/adapter"
+	"product-service/internal/infrastructure"
+	productPorts "product-service/internal/module/product/ports"
+	productRepository "product-service/internal/module/product/repository"
+	productService "product-service/internal/module/product/service"
+	reviewPorts "product-service/internal/module/review/ports"
+	reviewRepository "product-service/internal/module/review/repository"
+	reviewService "product-service/internal/module/review/service"
+	shopPorts "product-service/internal/module/shop/ports"
+	shopRepository "product-service/internal/module/shop/repository"
+	shopService "product-service/internal/module/shop/service"
+	"product-service/pkg/errmsg"
+	"product-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/rs/zerolog/log"
+)
+
+// handler serves the storefront reads over GraphQL, on top of the product,
+// shop and review services the REST handlers use.
+type handler struct {
+	productService productPorts.ProductService
+	shopService    shopPorts.ShopService
+	reviewService  reviewPorts.ReviewService
+	schema         graphql.Schema
+}
+
+type request struct {
+	Query         string         `json:"query" validate:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// authRequest is the caller like REST knows it, anonymous when empty. It
+// marks wishlisted products and shows owners their unpublished products.
+type authRequest struct {
+	UserId string `query:"user_id" validate:"omitempty,uuid"`
+}
+
+func NewStorefrontHandler() *handler {
+	productRepo := productRepository.NewProductRepository(adapter.Adapters.ShopeefunProductPostgres)
+	shopRepo := shopRepository.NewShopRepo(adapter.Adapters.ShopeefunProductPostgres)
+	reviewRepo := reviewRepository.NewReviewRepository(adapter.Adapters.ShopeefunProductPostgres)
+
+	h := &handler{
+		productService: productService.NewProductService(productRepo, adapter.Adapters.Cache),
+		shopService:    shopService.NewShopService(shopRepo),
+		reviewService:  reviewService.NewReviewService(reviewRepo),
+	}
+
+	schema, err := h.buildSchema()
+	if err != nil {
+		log.Fatal().Err(err).Msg("handler: Invalid GraphQL schema")
+	}
+	h.schema = schema
+
+	return h
+}
+
+func (h *handler) Register(router fiber.Router) {
+	router.Get("/graphql", h.serve)
+	router.Post("/graphql", h.serve)
+}
+
+func (h *handler) serve(c *fiber.Ctx) error {
+	var (
+		req  = &request{}
+		auth = &authRequest{}
+		v    = adapter.Adapters.Validator
+		envs = infrastructure.Envs
+	)
+
+	if err := c.QueryParser(auth); err != nil {
+		log.Warn().Err(err).Msg("handler: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(auth); err != nil {
+		log.Warn().Err(err).Any("payload", auth).Msg("handler: Invalid request query")
+		code, errs := errmsg.Errors(err, auth)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := parseRequest(c, req); err != nil {
+		log.Warn().Err(err).Msg("handler: Failed to parse GraphQL request")
+		return c.Status(fiber.StatusBadRequest).JSON(requestErrors(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(requestErrors(errors.New("query is required")))
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(requestErrors(err))
+	}
+
+	if result := graphql.ValidateDocument(&h.schema, doc, nil); !result.IsValid {
+		return c.Status(fiber.StatusBadRequest).JSON(&graphql.Result{Errors: result.Errors})
+	}
+
+	if err := checkLimits(doc, req.OperationName, req.Variables, envs.Graphql.MaxDepth, envs.Graphql.MaxComplexity); err != nil {
+		log.Warn().Err(err).Str("query", req.Query).Msg("handler: GraphQL query over limits")
+		return c.Status(fiber.StatusBadRequest).JSON(requestErrors(err))
+	}
+
+	ctx := context.WithValue(c.Context(), userIdKey, auth.UserId)
+	ctx = context.WithValue(ctx, loadersKey, newLoaders(h.productService, h.shopService, h.reviewService))
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// parseRequest reads the JSON body of a POST or the query string of a GET,
+// where variables are a JSON encoded object.
+func parseRequest(c *fiber.Ctx, req *request) error {
+	if c.Method() == fiber.MethodPost {
+		return c.BodyParser(req)
+	}
+
+	req.Query = c.Query("query")
+	req.OperationName = c.Query("operationName")
+
+	if variables := c.Query("variables"); variables != "" {
+		return json.Unmarshal([]byte(variables), &req.Variables)
+	}
+
+	return nil
+}
+
+func requestErrors(err error) *graphql.Result {
+	return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+}
+
+// graphError is a service error as GraphQL reports it, the status code and
+// field errors of the REST response go in its extensions.
+type graphError struct {
+	code   int
+	msg    string
+	fields map[string][]string
+}
+
+func (e *graphError) Error() string {
+	return e.msg
+}
+
+func (e *graphError) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.code}
+	if len(e.fields) > 0 {
+		extensions["errors"] = e.fields
+	}
+
+	return extensions
+}
+
+// resolveError maps err like the REST handlers do, server errors keep their
+// cause out of the message.
+func resolveError[T any](err error, req *T) error {
+	var (
+		res        = &graphError{msg: "Your request has been failed to process"}
+		code, errs = errmsg.Errors(err, req)
+	)
+
+	res.code = code
+
+	switch errs := errs.(type) {
+	case *errmsg.CostumError:
+		if code < 500 {
+			res.msg = errs.Msg
+		}
+		res.fields = errs.Errors
+	case map[string][]string:
+		res.fields = errs
+	}
+
+	return res
+}
@@ -0,0 +1,137 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// defaultLimit is the page size of paginated fields called without a limit.
+	defaultLimit = 10
+
+	// ceiling caps the counts, huge limits must not overflow them
+	ceiling = math.MaxInt32
+)
+
+// paginated are the fields returning a page, their selection is counted once
+// per item the page may hold.
+var paginated = map[string]bool{
+	"shops":    true,
+	"products": true,
+	"reviews":  true,
+}
+
+// limits measures the operation before it runs. The depth is the deepest
+// field nesting, root fields being 1. The complexity counts every field once,
+// the fields under a page once per item its limit allows. Introspection
+// fields are free.
+type limits struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+func checkLimits(doc *ast.Document, operationName string, variables map[string]any, maxDepth, maxComplexity int) error {
+	var (
+		l         = limits{fragments: make(map[string]*ast.FragmentDefinition), variables: variables}
+		operation *ast.OperationDefinition
+	)
+
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			l.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+
+	if operation == nil {
+		return nil
+	}
+
+	complexity, depth := l.measure(operation.SelectionSet, 1, make(map[string]bool))
+
+	if depth > maxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", depth, maxDepth)
+	}
+
+	if complexity > maxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, maxComplexity)
+	}
+
+	return nil
+}
+
+// measure returns the complexity and the depth of the selections at depth,
+// spreading is the chain of fragments being measured, which validation keeps
+// free of cycles.
+func (l limits) measure(set *ast.SelectionSet, depth int, spreading map[string]bool) (int, int) {
+	var complexity, deepest int
+
+	if set == nil {
+		return 0, depth - 1
+	}
+
+	for _, selection := range set.Selections {
+		var cost, reached int
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+
+			childCost, childDepth := l.measure(selection.SelectionSet, depth+1, spreading)
+			cost, reached = min(1+childCost*l.multiplier(selection), ceiling), max(depth, childDepth)
+		case *ast.InlineFragment:
+			cost, reached = l.measure(selection.SelectionSet, depth, spreading)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := l.fragments[name]
+			if !ok || spreading[name] {
+				continue
+			}
+
+			spreading[name] = true
+			cost, reached = l.measure(fragment.SelectionSet, depth, spreading)
+			delete(spreading, name)
+		}
+
+		complexity = min(complexity+cost, ceiling)
+		deepest = max(deepest, reached)
+	}
+
+	return complexity, deepest
+}
+
+func (l limits) multiplier(field *ast.Field) int {
+	if !paginated[field.Name.Value] {
+		return 1
+	}
+
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "limit" {
+			continue
+		}
+
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if limit, err := strconv.Atoi(value.Value); err == nil && limit > 0 {
+				return min(limit, ceiling)
+			}
+		case *ast.Variable:
+			// JSON numbers decode as float64
+			if limit, ok := l.variables[value.Name.Value].(float64); ok && limit > 0 {
+				return int(min(limit, ceiling))
+			}
+		}
+	}
+
+	return defaultLimit
+}
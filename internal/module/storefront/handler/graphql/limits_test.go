@@ -0,0 +1,127 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, query string) *ast.Document {
+	t.Helper()
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(query)})})
+	require.NoError(t, err)
+	return doc
+}
+
+// assertMeasured checks the query is let through at exactly depth and
+// complexity, and refused one below either.
+func assertMeasured(t *testing.T, query string, variables map[string]any, depth, complexity int) {
+	t.Helper()
+
+	doc := parse(t, query)
+
+	assert.NoError(t, checkLimits(doc, "", variables, depth, complexity))
+	assert.Error(t, checkLimits(doc, "", variables, depth-1, complexity))
+	assert.Error(t, checkLimits(doc, "", variables, depth, complexity-1))
+}
+
+func TestCheckLimits(t *testing.T) {
+	t.Run("counts the fields of a page once per item of its limit", func(t *testing.T) {
+		// products: 1 + (items: 1 + id + name) * 2
+		assertMeasured(t, `{ products(limit: 2) { items { id name } } }`, nil, 3, 7)
+	})
+
+	t.Run("uses the default limit without one", func(t *testing.T) {
+		assertMeasured(t, `{ products { items { id } } }`, nil, 3, 1+2*defaultLimit)
+	})
+
+	t.Run("multiplies nested pages", func(t *testing.T) {
+		// shops: 1 + (items: 1 + (products: 1 + (items: 1 + id) * 3)) * 2
+		assertMeasured(t, `{ shops(limit: 2) { items { products(limit: 3) { items { id } } } } }`, nil, 5, 17)
+	})
+
+	t.Run("measures fragment spreads where they are spread", func(t *testing.T) {
+		query := `
+			query {
+				products(limit: 2) { ...page }
+			}
+
+			fragment page on ProductPage {
+				items { ...fields }
+			}
+
+			fragment fields on Product {
+				id
+				shop { name }
+			}
+		`
+
+		// products: 1 + (items: 1 + id + (shop: 1 + name)) * 2
+		assertMeasured(t, query, nil, 4, 9)
+	})
+
+	t.Run("measures inline fragments at the depth of their parent", func(t *testing.T) {
+		assertMeasured(t, `{ product(id: "1") { ... on Product { id name } } }`, nil, 2, 3)
+	})
+
+	t.Run("reads the limit from a variable", func(t *testing.T) {
+		query := `query ($limit: Int) { products(limit: $limit) { items { id } } }`
+
+		// JSON numbers decode as float64
+		assertMeasured(t, query, map[string]any{"limit": float64(5)}, 3, 11)
+	})
+
+	t.Run("uses the default limit for a missing variable", func(t *testing.T) {
+		query := `query ($limit: Int) { products(limit: $limit) { items { id } } }`
+
+		assertMeasured(t, query, nil, 3, 1+2*defaultLimit)
+	})
+
+	t.Run("lets introspection through for free", func(t *testing.T) {
+		query := `
+			{
+				__schema { types { name fields { name type { name ofType { name ofType { name } } } } } }
+				products(limit: 1) { __typename items { id } }
+			}
+		`
+
+		assertMeasured(t, query, nil, 3, 3)
+	})
+
+	t.Run("measures the named operation only", func(t *testing.T) {
+		query := `
+			query Small { product(id: "1") { id } }
+			query Large { products(limit: 100) { items { id name } } }
+		`
+		doc := parse(t, query)
+
+		assert.NoError(t, checkLimits(doc, "Small", nil, 2, 2))
+		assert.Error(t, checkLimits(doc, "Large", nil, 2, 2))
+	})
+
+	t.Run("refuses a query over the depth limit", func(t *testing.T) {
+		doc := parse(t, `{ products { items { shop { products { items { id } } } } } }`)
+
+		err := checkLimits(doc, "", nil, 5, 1_000_000)
+		assert.EqualError(t, err, "query depth 6 exceeds the limit of 5")
+	})
+
+	t.Run("refuses a query over the complexity limit", func(t *testing.T) {
+		doc := parse(t, `{ products(limit: 100) { items { id name } } }`)
+
+		err := checkLimits(doc, "", nil, 10, 100)
+		assert.EqualError(t, err, "query complexity 301 exceeds the limit of 100")
+	})
+
+	t.Run("caps huge limits instead of overflowing", func(t *testing.T) {
+		doc := parse(t, `{ shops(limit: 2147483647) { items { products(limit: 2147483647) { items { id } } } } }`)
+
+		err := checkLimits(doc, "", nil, 10, 1000)
+		assert.EqualError(t, err, "query complexity 2147483647 exceeds the limit of 1000")
+	})
+}
@@ -0,0 +1,189 @@
+package graphql
+
+import (
+	"context"
+	productEntity "product-service/internal/module/product/entity"
+	productPorts "product-service/internal/module/product/ports"
+	reviewEntity "product-service/internal/module/review/entity"
+	reviewPorts "product-service/internal/module/review/ports"
+	shopEntity "product-service/internal/module/shop/entity"
+	shopPorts "product-service/internal/module/shop/ports"
+	"product-service/pkg/dataloader"
+	"product-service/pkg/errmsg"
+)
+
+type contextKey int
+
+const (
+	userIdKey contextKey = iota
+	loadersKey
+)
+
+// detailKey is a product in the currency its listing was converted to.
+type detailKey struct {
+	id       string
+	currency string
+}
+
+// shopProductsKey is a page of a shop's products, the same page asked for by
+// several shops is fetched for all of them at once.
+type shopProductsKey struct {
+	shopId     string
+	categoryId string
+	name       string
+	sort       string
+	currency   string
+	page       int
+	limit      int
+}
+
+// reviewsKey is a page of a product's reviews, batched like shopProductsKey.
+type reviewsKey struct {
+	productId string
+	page      int
+	limit     int
+}
+
+// loaders batch the nested lookups of one request, products of a listing
+// load their shop, category, details and reviews in one query each instead
+// of one per product, and shops of a listing their products.
+type loaders struct {
+	shops        *dataloader.Loader[string, shopEntity.ShopItem]
+	categories   *dataloader.Loader[string, productEntity.Category]
+	details      *dataloader.Loader[detailKey, productEntity.GetProductResponseById]
+	shopProducts *dataloader.Loader[shopProductsKey, productEntity.GetProductsResponse]
+	reviews      *dataloader.Loader[reviewsKey, reviewEntity.GetReviewsResponse]
+}
+
+func newLoaders(products productPorts.ProductService, shops shopPorts.ShopService, reviews reviewPorts.ReviewService) *loaders {
+	return &loaders{
+		shops: dataloader.New(func(ctx context.Context, ids []string) (map[string]shopEntity.ShopItem, error) {
+			items, err := shops.GetShopsByIds(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			res := make(map[string]shopEntity.ShopItem, len(items))
+			for _, item := range items {
+				res[item.Id] = item
+			}
+			return res, nil
+		}),
+		categories: dataloader.New(func(ctx context.Context, ids []string) (map[string]productEntity.Category, error) {
+			items, err := products.GetCategoriesByIds(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			res := make(map[string]productEntity.Category, len(items))
+			for _, item := range items {
+				res[item.Id] = item
+			}
+			return res, nil
+		}),
+		details: dataloader.New(func(ctx context.Context, keys []detailKey) (map[detailKey]productEntity.GetProductResponseById, error) {
+			byCurrency := make(map[string][]string)
+			for _, key := range keys {
+				byCurrency[key.currency] = append(byCurrency[key.currency], key.id)
+			}
+
+			res := make(map[detailKey]productEntity.GetProductResponseById, len(keys))
+			for currency, ids := range byCurrency {
+				// BatchGetProducts takes up to 100 ids
+				for start := 0; start < len(ids); start += 100 {
+					batch := ids[start:min(start+100, len(ids))]
+
+					found, err := products.BatchGetProducts(ctx, &productEntity.BatchGetProductsRequest{ProductIds: batch, Currency: currency})
+					if err != nil {
+						return nil, err
+					}
+
+					for _, item := range found.Items {
+						res[detailKey{id: item.Id, currency: currency}] = item
+					}
+				}
+			}
+			return res, nil
+		}),
+		shopProducts: dataloader.New(func(ctx context.Context, keys []shopProductsKey) (map[shopProductsKey]productEntity.GetProductsResponse, error) {
+			// shops asking for the same page are listed in one query
+			byPage := make(map[shopProductsKey][]string)
+			for _, key := range keys {
+				page := key
+				page.shopId = ""
+				byPage[page] = append(byPage[page], key.shopId)
+			}
+
+			res := make(map[shopProductsKey]productEntity.GetProductsResponse, len(keys))
+			for page, shopIds := range byPage {
+				found, err := products.GetShopsProducts(ctx, shopIds, &productEntity.GetProductsRequest{
+					UserId:     userId(ctx),
+					CategoryId: page.categoryId,
+					Name:       page.name,
+					Sort:       page.sort,
+					Currency:   page.currency,
+					Page:       page.page,
+					Limit:      page.limit,
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				for shopId, items := range found {
+					key := page
+					key.shopId = shopId
+					res[key] = items
+				}
+			}
+			return res, nil
+		}),
+		reviews: dataloader.New(func(ctx context.Context, keys []reviewsKey) (map[reviewsKey]reviewEntity.GetReviewsResponse, error) {
+			// products asking for the same page are read in one query
+			byPage := make(map[reviewsKey][]string)
+			for _, key := range keys {
+				page := key
+				page.productId = ""
+				byPage[page] = append(byPage[page], key.productId)
+			}
+
+			res := make(map[reviewsKey]reviewEntity.GetReviewsResponse, len(keys))
+			for page, productIds := range byPage {
+				found, err := reviews.GetProductsReviews(ctx, productIds, &reviewEntity.GetReviewsRequest{Page: page.page, Limit: page.limit})
+				if err != nil {
+					return nil, err
+				}
+
+				for productId, items := range found {
+					key := page
+					key.productId = productId
+					res[key] = items
+				}
+			}
+			return res, nil
+		}),
+	}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
+
+// userId is the caller of the request, empty for anonymous callers.
+func userId(ctx context.Context) string {
+	id, _ := ctx.Value(userIdKey).(string)
+	return id
+}
+
+// getProducts is GetProducts with an empty page instead of a not found error,
+// an empty list is a valid answer to a query.
+func getProducts(ctx context.Context, products productPorts.ProductService, req *productEntity.GetProductsRequest) (productEntity.GetProductsResponse, error) {
+	res, err := products.GetProducts(ctx, req)
+	if costumErr, ok := err.(*errmsg.CostumError); ok && costumErr.Code == 404 {
+		res = productEntity.GetProductsResponse{Items: make([]productEntity.Product, 0)}
+		res.Meta.Page = req.Page
+		res.Meta.Limit = req.Limit
+		return res, nil
+	}
+
+	return res, err
+}
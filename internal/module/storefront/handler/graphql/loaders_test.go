@@ -0,0 +1,229 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	productEntity "product-service/internal/module/product/entity"
+	productPorts "product-service/internal/module/product/ports"
+	reviewEntity "product-service/internal/module/review/entity"
+	reviewPorts "product-service/internal/module/review/ports"
+	shopEntity "product-service/internal/module/shop/entity"
+	shopPorts "product-service/internal/module/shop/ports"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeProducts answers the batch lookups of the loaders and records them,
+// the other methods of the service are not called.
+type fakeProducts struct {
+	productPorts.ProductService
+
+	categoryCalls [][]string
+	detailCalls   []productEntity.BatchGetProductsRequest
+	shopCalls     []productEntity.GetProductsRequest
+	fail          error
+}
+
+func (f *fakeProducts) GetCategoriesByIds(ctx context.Context, ids []string) ([]productEntity.Category, error) {
+	f.categoryCalls = append(f.categoryCalls, ids)
+	if f.fail != nil {
+		return nil, f.fail
+	}
+
+	res := make([]productEntity.Category, 0, len(ids))
+	for _, id := range ids {
+		if id != "unknown" {
+			res = append(res, productEntity.Category{Id: id, Name: "category " + id})
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeProducts) BatchGetProducts(ctx context.Context, req *productEntity.BatchGetProductsRequest) (productEntity.BatchGetProductsResponse, error) {
+	f.detailCalls = append(f.detailCalls, *req)
+
+	res := productEntity.BatchGetProductsResponse{}
+	for _, id := range req.ProductIds {
+		res.Items = append(res.Items, productEntity.GetProductResponseById{Id: id, Currency: req.Currency})
+	}
+	return res, nil
+}
+
+func (f *fakeProducts) GetShopsProducts(ctx context.Context, shopIds []string, req *productEntity.GetProductsRequest) (map[string]productEntity.GetProductsResponse, error) {
+	call := *req
+	call.ShopId = fmt.Sprint(sorted(shopIds))
+	f.shopCalls = append(f.shopCalls, call)
+
+	res := make(map[string]productEntity.GetProductsResponse, len(shopIds))
+	for _, shopId := range shopIds {
+		res[shopId] = productEntity.GetProductsResponse{Items: []productEntity.Product{{ShopId: shopId}}}
+	}
+	return res, nil
+}
+
+type fakeShops struct {
+	shopPorts.ShopService
+
+	calls [][]string
+}
+
+func (f *fakeShops) GetShopsByIds(ctx context.Context, ids []string) ([]shopEntity.ShopItem, error) {
+	f.calls = append(f.calls, ids)
+
+	res := make([]shopEntity.ShopItem, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, shopEntity.ShopItem{Id: id})
+	}
+	return res, nil
+}
+
+type fakeReviews struct {
+	reviewPorts.ReviewService
+
+	calls []reviewEntity.GetReviewsRequest
+}
+
+// GetProductsReviews leaves out products without reviews like the repository.
+func (f *fakeReviews) GetProductsReviews(ctx context.Context, productIds []string, req *reviewEntity.GetReviewsRequest) (map[string]reviewEntity.GetReviewsResponse, error) {
+	call := *req
+	call.ProductId = fmt.Sprint(sorted(productIds))
+	f.calls = append(f.calls, call)
+
+	res := make(map[string]reviewEntity.GetReviewsResponse, len(productIds))
+	for _, productId := range productIds {
+		if productId != "unreviewed" {
+			res[productId] = reviewEntity.GetReviewsResponse{Items: []reviewEntity.ReviewResponse{{ProductId: productId}}}
+		}
+	}
+	return res, nil
+}
+
+func sorted(ids []string) []string {
+	ids = append([]string(nil), ids...)
+	sort.Strings(ids)
+	return ids
+}
+
+func TestLoaders(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("loads the shops and categories of a page in one call each", func(t *testing.T) {
+		products, shops := &fakeProducts{}, &fakeShops{}
+		l := newLoaders(products, shops, &fakeReviews{})
+
+		a, b, again := l.categories.Load(ctx, "a"), l.categories.Load(ctx, "b"), l.categories.Load(ctx, "a")
+		unknown := l.categories.Load(ctx, "unknown")
+		shop := l.shops.Load(ctx, "s")
+
+		category, found, err := a()
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "category a", category.Name)
+
+		_, _, _ = b()
+		_, _, _ = again()
+
+		_, found, err = unknown()
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		item, found, _ := shop()
+		assert.True(t, found)
+		assert.Equal(t, "s", item.Id)
+
+		assert.Equal(t, [][]string{{"a", "b", "unknown"}}, products.categoryCalls)
+		assert.Equal(t, [][]string{{"s"}}, shops.calls)
+	})
+
+	t.Run("reports a failed batch to every product", func(t *testing.T) {
+		failure := errors.New("database is down")
+		l := newLoaders(&fakeProducts{fail: failure}, &fakeShops{}, &fakeReviews{})
+
+		a, b := l.categories.Load(ctx, "a"), l.categories.Load(ctx, "b")
+
+		_, _, err := a()
+		assert.ErrorIs(t, err, failure)
+
+		_, _, err = b()
+		assert.ErrorIs(t, err, failure)
+	})
+
+	t.Run("splits details by currency and in batches of 100", func(t *testing.T) {
+		products := &fakeProducts{}
+		l := newLoaders(products, &fakeShops{}, &fakeReviews{})
+
+		thunks := make([]func() (productEntity.GetProductResponseById, bool, error), 0)
+		for i := range 150 {
+			thunks = append(thunks, l.details.Load(ctx, detailKey{id: fmt.Sprint(i)}))
+		}
+		usd := l.details.Load(ctx, detailKey{id: "0", currency: "USD"})
+
+		for _, thunk := range thunks {
+			_, found, err := thunk()
+			assert.NoError(t, err)
+			assert.True(t, found)
+		}
+
+		detail, found, _ := usd()
+		assert.True(t, found)
+		assert.Equal(t, "USD", detail.Currency)
+
+		sizes := make(map[string][]int)
+		for _, call := range products.detailCalls {
+			sizes[call.Currency] = append(sizes[call.Currency], len(call.ProductIds))
+		}
+		assert.Equal(t, map[string][]int{"": {100, 50}, "USD": {1}}, sizes)
+		assert.Equal(t, 1, l.details.Batches())
+	})
+
+	t.Run("lists the products of shops asking for the same page together", func(t *testing.T) {
+		products := &fakeProducts{}
+		l := newLoaders(products, &fakeShops{}, &fakeReviews{})
+
+		first := shopProductsKey{page: 1, limit: 10}
+		second := shopProductsKey{page: 2, limit: 10}
+
+		a := l.shopProducts.Load(ctx, shopProductsKey{shopId: "a", page: first.page, limit: first.limit})
+		b := l.shopProducts.Load(ctx, shopProductsKey{shopId: "b", page: first.page, limit: first.limit})
+		c := l.shopProducts.Load(ctx, shopProductsKey{shopId: "c", page: second.page, limit: second.limit})
+
+		for shopId, thunk := range map[string]func() (productEntity.GetProductsResponse, bool, error){"a": a, "b": b, "c": c} {
+			page, found, err := thunk()
+			assert.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, shopId, page.Items[0].ShopId)
+		}
+
+		calls := make(map[int]string)
+		for _, call := range products.shopCalls {
+			calls[call.Page] = call.ShopId
+		}
+		assert.Equal(t, map[int]string{1: "[a b]", 2: "[c]"}, calls)
+	})
+
+	t.Run("reads the reviews of products asking for the same page together", func(t *testing.T) {
+		reviews := &fakeReviews{}
+		l := newLoaders(&fakeProducts{}, &fakeShops{}, reviews)
+
+		a := l.reviews.Load(ctx, reviewsKey{productId: "a", page: 1, limit: 5})
+		b := l.reviews.Load(ctx, reviewsKey{productId: "b", page: 1, limit: 5})
+		unreviewed := l.reviews.Load(ctx, reviewsKey{productId: "unreviewed", page: 1, limit: 5})
+
+		page, found, err := a()
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "a", page.Items[0].ProductId)
+
+		page, _, _ = b()
+		assert.Equal(t, "b", page.Items[0].ProductId)
+
+		_, found, err = unreviewed()
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		assert.Equal(t, []reviewEntity.GetReviewsRequest{{ProductId: "[a b unreviewed]", Page: 1, Limit: 5}}, reviews.calls)
+	})
+}
@@ -0,0 +1,539 @@
+package graphql
+
+import (
+	"context"
+	"product-service/internal/adapter"
+	productEntity "product-service/internal/module/product/entity"
+	reviewEntity "product-service/internal/module/review/entity"
+	shopEntity "product-service/internal/module/shop/entity"
+	"product-service/pkg/errmsg"
+
+	"github.com/graphql-go/graphql"
+)
+
+// product is a product of a listing or one looked up by id. Listings leave
+// out a few fields of the lookup, those are loaded when they are selected.
+type product struct {
+	productEntity.Product
+	currency string
+	detail   *productEntity.GetProductResponseById
+}
+
+type page struct {
+	items any
+	meta  productEntity.Meta
+}
+
+// shopArgs and productArgs are validated like the REST params they mirror.
+type shopArgs struct {
+	Id string `json:"id" validate:"required,uuid"`
+}
+
+type productArgs struct {
+	Id       string `json:"id" validate:"required,uuid"`
+	Currency string `json:"currency" validate:"omitempty,iso4217"`
+}
+
+func (h *handler) buildSchema() (graphql.Schema, error) {
+	var (
+		shopType     *graphql.Object
+		productType  *graphql.Object
+		categoryType = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Category",
+			Fields: graphql.Fields{
+				"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: categoryField(func(c productEntity.Category) any { return c.Id })},
+				"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: categoryField(func(c productEntity.Category) any { return c.Name })},
+			},
+		})
+		metaType = graphql.NewObject(graphql.ObjectConfig{
+			Name: "PageMeta",
+			Fields: graphql.Fields{
+				"totalData": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: metaField(func(m productEntity.Meta) any { return m.TotalData })},
+				"totalPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: metaField(func(m productEntity.Meta) any { return m.TotalPage })},
+				"page":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: metaField(func(m productEntity.Meta) any { return m.Page })},
+				"limit":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: metaField(func(m productEntity.Meta) any { return m.Limit })},
+			},
+		})
+		sortType = graphql.NewEnum(graphql.EnumConfig{
+			Name: "ProductSort",
+			Values: graphql.EnumValueConfigMap{
+				"NEWEST":     &graphql.EnumValueConfig{Value: "newest"},
+				"PRICE_ASC":  &graphql.EnumValueConfig{Value: "price_asc"},
+				"PRICE_DESC": &graphql.EnumValueConfig{Value: "price_desc"},
+			},
+		})
+		reviewType = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Review",
+			Fields: graphql.Fields{
+				"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: reviewField(func(r reviewEntity.ReviewResponse) any { return r.Id })},
+				"userId":    &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: reviewField(func(r reviewEntity.ReviewResponse) any { return r.UserId })},
+				"rating":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: reviewField(func(r reviewEntity.ReviewResponse) any { return r.Rating })},
+				"comment":   &graphql.Field{Type: graphql.String, Resolve: reviewField(func(r reviewEntity.ReviewResponse) any { return optional(r.Comment) })},
+				"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: reviewField(func(r reviewEntity.ReviewResponse) any { return r.CreatedAt })},
+			},
+		})
+		productPageType *graphql.Object
+		shopPageType    *graphql.Object
+		reviewPageType  = graphql.NewObject(graphql.ObjectConfig{
+			Name: "ReviewPage",
+			Fields: graphql.Fields{
+				"items": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reviewType))), Resolve: pageItems},
+				"meta":  &graphql.Field{Type: graphql.NewNonNull(metaType), Resolve: pageMeta},
+			},
+		})
+	)
+
+	productListArgs := func(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		args["categoryId"] = &graphql.ArgumentConfig{Type: graphql.ID}
+		args["name"] = &graphql.ArgumentConfig{Type: graphql.String}
+		args["sort"] = &graphql.ArgumentConfig{Type: sortType}
+		args["currency"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "converts the prices, ISO 4217"}
+		args["page"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1}
+		args["limit"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10}
+		return args
+	}
+
+	productType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Product",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":              &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: productField(func(p *product) any { return p.Id })},
+				"name":            &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: productField(func(p *product) any { return p.Name })},
+				"imageUrl":        &graphql.Field{Type: graphql.String, Resolve: productField(func(p *product) any { return optional(p.ImageUrl) })},
+				"price":           &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: productField(func(p *product) any { return p.Price.String() })},
+				"currency":        &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: productField(func(p *product) any { return p.Currency })},
+				"originalPrice":   &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: productField(func(p *product) any { return p.OriginalPrice.String() })},
+				"finalPrice":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: productField(func(p *product) any { return p.FinalPrice.String() })},
+				"discountPercent": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: productField(func(p *product) any { return p.DiscountPct.String() })},
+				"status":          &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: productField(func(p *product) any { return p.Status })},
+				"brandId":         &graphql.Field{Type: graphql.ID, Resolve: productField(func(p *product) any { return optional(p.BrandId) })},
+				"brand":           &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: productField(func(p *product) any { return p.Brand })},
+				"favouriteCount":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: productField(func(p *product) any { return p.FavouriteCount })},
+				"inWishlist":      &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Resolve: productField(func(p *product) any { return p.InWishlist })},
+				"createdAt":       &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: productField(func(p *product) any { return p.CreatedAt })},
+				"updatedAt":       &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: productField(func(p *product) any { return p.UpdatedAt })},
+
+				"description": &graphql.Field{Type: graphql.String, Resolve: productDetail(func(d productEntity.GetProductResponseById) any {
+					return optional(d.Description)
+				})},
+				"stock": &graphql.Field{Type: graphql.Int, Resolve: productDetail(func(d productEntity.GetProductResponseById) any {
+					return d.Stock
+				})},
+				"lowestPrice30d": &graphql.Field{Type: graphql.String, Resolve: productDetail(func(d productEntity.GetProductResponseById) any {
+					if d.LowestPrice30d == nil {
+						return nil
+					}
+					return d.LowestPrice30d.String()
+				})},
+
+				"shop":     &graphql.Field{Type: shopType, Resolve: h.productShop},
+				"category": &graphql.Field{Type: categoryType, Resolve: h.productCategory},
+				"reviews": &graphql.Field{
+					Type: graphql.NewNonNull(reviewPageType),
+					Args: graphql.FieldConfigArgument{
+						"page":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+						"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+					},
+					Resolve: h.productReviews,
+				},
+			}
+		}),
+	})
+
+	productPageType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProductPage",
+		Fields: graphql.Fields{
+			"items": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productType))), Resolve: pageItems},
+			"meta":  &graphql.Field{Type: graphql.NewNonNull(metaType), Resolve: pageMeta},
+		},
+	})
+
+	shopType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Shop",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: shopField(func(s shopEntity.ShopItem) any { return s.Id })},
+				"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: shopField(func(s shopEntity.ShopItem) any { return s.Name })},
+				"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: shopField(func(s shopEntity.ShopItem) any { return s.CretedAt })},
+				"products": &graphql.Field{
+					Type:    graphql.NewNonNull(productPageType),
+					Args:    productListArgs(graphql.FieldConfigArgument{}),
+					Resolve: h.shopProducts,
+				},
+			}
+		}),
+	})
+
+	shopPageType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ShopPage",
+		Fields: graphql.Fields{
+			"items": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(shopType))), Resolve: pageItems},
+			"meta":  &graphql.Field{Type: graphql.NewNonNull(metaType), Resolve: pageMeta},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"shop": &graphql.Field{
+				Type:    shopType,
+				Args:    graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: h.shop,
+			},
+			"shops": &graphql.Field{
+				Type: graphql.NewNonNull(shopPageType),
+				Args: graphql.FieldConfigArgument{
+					"name":  &graphql.ArgumentConfig{Type: graphql.String},
+					"page":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+				},
+				Resolve: h.shops,
+			},
+			"product": &graphql.Field{
+				Type: productType,
+				Args: graphql.FieldConfigArgument{
+					"id":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"currency": &graphql.ArgumentConfig{Type: graphql.String, Description: "converts the prices, ISO 4217"},
+				},
+				Resolve: h.product,
+			},
+			"products": &graphql.Field{
+				Type:    graphql.NewNonNull(productPageType),
+				Args:    productListArgs(graphql.FieldConfigArgument{"shopId": &graphql.ArgumentConfig{Type: graphql.ID}}),
+				Resolve: h.products,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func (h *handler) shop(params graphql.ResolveParams) (any, error) {
+	var (
+		req = &shopArgs{Id: stringArg(params, "id")}
+		ctx = params.Context
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := v.Validate(req); err != nil {
+		return nil, resolveError(err, req)
+	}
+
+	return loadShop(ctx, req.Id), nil
+}
+
+func (h *handler) shops(params graphql.ResolveParams) (any, error) {
+	var (
+		req = &shopEntity.GetShopsRequest{ShopName: stringArg(params, "name"), Page: intArg(params, "page"), Limit: intArg(params, "limit")}
+		ctx = params.Context
+		v   = adapter.Adapters.Validator
+	)
+
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		return nil, resolveError(err, req)
+	}
+
+	resp, err := h.shopService.GetShops(ctx, req)
+	if err != nil {
+		return nil, resolveError(err, req)
+	}
+
+	items := make([]shopEntity.ShopItem, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		if item.DeletedAt == nil {
+			items = append(items, item)
+		}
+	}
+
+	return page{items: items, meta: productEntity.Meta(resp.Meta)}, nil
+}
+
+func (h *handler) product(params graphql.ResolveParams) (any, error) {
+	var (
+		args = &productArgs{Id: stringArg(params, "id"), Currency: stringArg(params, "currency")}
+		ctx  = params.Context
+		v    = adapter.Adapters.Validator
+	)
+
+	if err := v.Validate(args); err != nil {
+		return nil, resolveError(err, args)
+	}
+
+	req := &productEntity.GetProductRequestById{ProductId: args.Id, Currency: args.Currency, UserId: userId(ctx)}
+
+	resp, err := h.productService.GetProductById(ctx, req)
+	if err != nil {
+		// a missing product is null, not an error
+		if costumErr, ok := err.(*errmsg.CostumError); ok && costumErr.Code == 404 {
+			return nil, nil
+		}
+		return nil, resolveError(err, req)
+	}
+
+	return fromDetail(resp, args.Currency), nil
+}
+
+func (h *handler) products(params graphql.ResolveParams) (any, error) {
+	var (
+		req = productsRequest(params)
+		ctx = params.Context
+		v   = adapter.Adapters.Validator
+	)
+
+	req.ShopId = stringArg(params, "shopId")
+	req.UserId = userId(ctx)
+
+	if err := v.Validate(req); err != nil {
+		return nil, resolveError(err, req)
+	}
+
+	resp, err := getProducts(ctx, h.productService, req)
+	if err != nil {
+		return nil, resolveError(err, req)
+	}
+
+	return productPage(resp, req.Currency), nil
+}
+
+func (h *handler) shopProducts(params graphql.ResolveParams) (any, error) {
+	var (
+		shop = params.Source.(shopEntity.ShopItem)
+		req  = productsRequest(params)
+		ctx  = params.Context
+		v    = adapter.Adapters.Validator
+	)
+
+	req.ShopId = shop.Id
+
+	if err := v.Validate(req); err != nil {
+		return nil, resolveError(err, req)
+	}
+
+	thunk := loadersFrom(ctx).shopProducts.Load(ctx, shopProductsKey{
+		shopId:     req.ShopId,
+		categoryId: req.CategoryId,
+		name:       req.Name,
+		sort:       req.Sort,
+		currency:   req.Currency,
+		page:       req.Page,
+		limit:      req.Limit,
+	})
+
+	return func() (any, error) {
+		resp, _, err := thunk()
+		if err != nil {
+			return nil, resolveError(err, req)
+		}
+
+		return productPage(resp, req.Currency), nil
+	}, nil
+}
+
+func (h *handler) productShop(params graphql.ResolveParams) (any, error) {
+	return loadShop(params.Context, params.Source.(*product).ShopId), nil
+}
+
+func (h *handler) productCategory(params graphql.ResolveParams) (any, error) {
+	var (
+		p   = params.Source.(*product)
+		ctx = params.Context
+	)
+
+	thunk := loadersFrom(ctx).categories.Load(ctx, p.CategoryId)
+
+	return func() (any, error) {
+		category, found, err := thunk()
+		if err != nil {
+			return nil, resolveError[any](err, nil)
+		}
+		if !found {
+			return nil, nil
+		}
+
+		return category, nil
+	}, nil
+}
+
+func (h *handler) productReviews(params graphql.ResolveParams) (any, error) {
+	var (
+		p   = params.Source.(*product)
+		req = &reviewEntity.GetReviewsRequest{ProductId: p.Id, Page: intArg(params, "page"), Limit: intArg(params, "limit")}
+		ctx = params.Context
+		v   = adapter.Adapters.Validator
+	)
+
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		return nil, resolveError(err, req)
+	}
+
+	thunk := loadersFrom(ctx).reviews.Load(ctx, reviewsKey{productId: req.ProductId, page: req.Page, limit: req.Limit})
+
+	return func() (any, error) {
+		resp, found, err := thunk()
+		if err != nil {
+			return nil, resolveError(err, req)
+		}
+		if !found {
+			// products without reviews on the page are left out of the batch
+			resp.Items = make([]reviewEntity.ReviewResponse, 0)
+			resp.Meta.Page = req.Page
+			resp.Meta.Limit = req.Limit
+		}
+
+		return page{items: resp.Items, meta: resp.Meta}, nil
+	}, nil
+}
+
+func loadShop(ctx context.Context, shopId string) func() (any, error) {
+	thunk := loadersFrom(ctx).shops.Load(ctx, shopId)
+
+	return func() (any, error) {
+		shop, found, err := thunk()
+		if err != nil {
+			return nil, resolveError[any](err, nil)
+		}
+		if !found {
+			return nil, nil
+		}
+
+		return shop, nil
+	}
+}
+
+func productsRequest(params graphql.ResolveParams) *productEntity.GetProductsRequest {
+	req := &productEntity.GetProductsRequest{
+		CategoryId: stringArg(params, "categoryId"),
+		Name:       stringArg(params, "name"),
+		Sort:       stringArg(params, "sort"),
+		Currency:   stringArg(params, "currency"),
+		Page:       intArg(params, "page"),
+		Limit:      intArg(params, "limit"),
+	}
+
+	req.SetDefaults()
+	return req
+}
+
+func productPage(resp productEntity.GetProductsResponse, currency string) page {
+	items := make([]*product, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		items = append(items, &product{Product: item, currency: currency})
+	}
+
+	return page{items: items, meta: resp.Meta}
+}
+
+func fromDetail(d productEntity.GetProductResponseById, currency string) *product {
+	return &product{
+		Product: productEntity.Product{
+			Id:             d.Id,
+			CategoryId:     d.CategoryId,
+			ShopId:         d.ShopId,
+			Name:           d.Name,
+			ImageUrl:       d.ImageUrl,
+			Price:          d.Price,
+			Currency:       d.Currency,
+			OriginalPrice:  d.OriginalPrice,
+			FinalPrice:     d.FinalPrice,
+			DiscountPct:    d.DiscountPct,
+			BrandId:        d.BrandId,
+			Brand:          d.Brand,
+			FavouriteCount: d.FavouriteCount,
+			Status:         d.Status,
+			CreatedAt:      d.CreatedAt,
+			UpdatedAt:      d.UpdatedAt,
+		},
+		currency: currency,
+		detail:   &d,
+	}
+}
+
+func productField(get func(p *product) any) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (any, error) {
+		return get(params.Source.(*product)), nil
+	}
+}
+
+// productDetail resolves a field listings leave out, from the product's
+// details loaded together with those of the other listed products.
+func productDetail(get func(d productEntity.GetProductResponseById) any) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (any, error) {
+		var (
+			p   = params.Source.(*product)
+			ctx = params.Context
+		)
+
+		if p.detail != nil {
+			return get(*p.detail), nil
+		}
+
+		thunk := loadersFrom(ctx).details.Load(ctx, detailKey{id: p.Id, currency: p.currency})
+
+		return func() (any, error) {
+			detail, found, err := thunk()
+			if err != nil {
+				return nil, resolveError[any](err, nil)
+			}
+			if !found {
+				return nil, nil
+			}
+
+			return get(detail), nil
+		}, nil
+	}
+}
+
+func shopField(get func(s shopEntity.ShopItem) any) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (any, error) {
+		return get(params.Source.(shopEntity.ShopItem)), nil
+	}
+}
+
+func categoryField(get func(c productEntity.Category) any) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (any, error) {
+		return get(params.Source.(productEntity.Category)), nil
+	}
+}
+
+func reviewField(get func(r reviewEntity.ReviewResponse) any) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (any, error) {
+		return get(params.Source.(reviewEntity.ReviewResponse)), nil
+	}
+}
+
+func metaField(get func(m productEntity.Meta) any) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (any, error) {
+		return get(params.Source.(productEntity.Meta)), nil
+	}
+}
+
+func pageItems(params graphql.ResolveParams) (any, error) {
+	return params.Source.(page).items, nil
+}
+
+func pageMeta(params graphql.ResolveParams) (any, error) {
+	return params.Source.(page).meta, nil
+}
+
+func stringArg(params graphql.ResolveParams, name string) string {
+	value, _ := params.Args[name].(string)
+	return value
+}
+
+func intArg(params graphql.ResolveParams, name string) int {
+	value, _ := params.Args[name].(int)
+	return value
+}
+
+// optional turns a nil pointer into an untyped nil, which GraphQL writes as
+// null.
+func optional[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "github.com/lib/pq"
+
+type UpdateCategoryPreferencesRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	CategoryIds []string `json:"category_ids" validate:"max=20,unique_in_slice,dive,uuid"`
+}
+
+type CategoryPreferencesResponse struct {
+	UserId      string         `json:"user_id" db:"id"`
+	CategoryIds pq.StringArray `json:"category_ids" db:"category_preferences"`
+}
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"product-service/internal/adapter"
+	m "product-service/internal/middleware"
+	"product-service/internal/module/user/entity"
+	"product-service/internal/module/user/ports"
+	"product-service/internal/module/user/repository"
+	"product-service/internal/module/user/service"
+	"product-service/pkg/errmsg"
+	"product-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type userHandler struct {
+	service ports.UserService
+}
+
+func NewUserHandler() *userHandler {
+	repo := repository.NewUserRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewUserService(repo)
+
+	return &userHandler{
+		service: service,
+	}
+}
+
+func (h *userHandler) Register(router fiber.Router) {
+	router.Put("/users/category-preferences", m.AuthQueryParams, h.updateCategoryPreferences)
+}
+
+func (h *userHandler) updateCategoryPreferences(c *fiber.Ctx) error {
+	var (
+		req = &entity.UpdateCategoryPreferencesRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpdateCategoryPreferences(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
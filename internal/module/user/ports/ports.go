@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/user/entity"
+)
+
+type UserService interface {
+	UpdateCategoryPreferences(ctx context.Context, req *entity.UpdateCategoryPreferencesRequest) (entity.CategoryPreferencesResponse, error)
+}
+
+type UserRepository interface {
+	UpdateCategoryPreferences(ctx context.Context, req *entity.UpdateCategoryPreferencesRequest) (entity.CategoryPreferencesResponse, error)
+	GetExistingCategoryIds(ctx context.Context, categoryIds []string) ([]string, error)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"product-service/internal/module/user/entity"
+	"product-service/internal/module/user/ports"
+	"product-service/pkg/errmsg"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+type userRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserRepository(db *sqlx.DB) ports.UserRepository {
+	return &userRepository{
+		db: db,
+	}
+}
+
+func (r *userRepository) UpdateCategoryPreferences(ctx context.Context, req *entity.UpdateCategoryPreferencesRequest) (entity.CategoryPreferencesResponse, error) {
+	var (
+		res entity.CategoryPreferencesResponse
+	)
+
+	query := `
+		UPDATE
+			users
+		SET
+			category_preferences = $1,
+			updated_at = NOW()
+		WHERE
+			id = $2
+			AND deleted_at IS NULL
+		RETURNING
+			id, category_preferences
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, pq.Array(req.CategoryIds), req.UserId).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: User not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("User not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateCategoryPreferences failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *userRepository) GetExistingCategoryIds(ctx context.Context, categoryIds []string) ([]string, error) {
+	var (
+		ids = make([]string, 0)
+	)
+
+	query := `
+		SELECT
+			id
+		FROM
+			product_categories
+		WHERE
+			id = ANY($1::uuid[])
+			AND deleted_at IS NULL
+	`
+
+	err := r.db.SelectContext(ctx, &ids, query, pq.Array(categoryIds))
+	if err != nil {
+		log.Error().Err(err).Any("payload", categoryIds).Msg("repository: GetExistingCategoryIds failed")
+		return ids, err
+	}
+
+	return ids, nil
+}
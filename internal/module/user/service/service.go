@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/module/user/entity"
+	"product-service/internal/module/user/ports"
+	"product-service/pkg/errmsg"
+
+	"github.com/rs/zerolog/log"
+)
+
+type userService struct {
+	repo ports.UserRepository
+}
+
+func NewUserService(r ports.UserRepository) ports.UserService {
+	return &userService{
+		repo: r,
+	}
+}
+
+func (s *userService) UpdateCategoryPreferences(ctx context.Context, req *entity.UpdateCategoryPreferencesRequest) (entity.CategoryPreferencesResponse, error) {
+	var res entity.CategoryPreferencesResponse
+
+	if req.CategoryIds == nil {
+		req.CategoryIds = make([]string, 0)
+	}
+
+	if len(req.CategoryIds) > 0 {
+		existing, err := s.repo.GetExistingCategoryIds(ctx, req.CategoryIds)
+		if err != nil {
+			return res, err
+		}
+
+		found := make(map[string]bool, len(existing))
+		for _, id := range existing {
+			found[id] = true
+		}
+
+		opts := []errmsg.Option{errmsg.WithMessage("Invalid category preferences")}
+		for _, id := range req.CategoryIds {
+			if !found[id] {
+				opts = append(opts, errmsg.WithErrors("category_ids", "category "+id+" does not exist."))
+			}
+		}
+
+		if len(opts) > 1 {
+			log.Warn().Any("payload", req).Msg("service: Unknown category preferences")
+			return res, errmsg.NewCostumErrors(422, opts...)
+		}
+	}
+
+	return s.repo.UpdateCategoryPreferences(ctx, req)
+}
@@ -0,0 +1,173 @@
+package entity
+
+import (
+	"product-service/pkg/decimal"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	TypePercentage = "percentage"
+	TypeFixed      = "fixed"
+)
+
+type CreateVoucherRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId       string          `params:"id" validate:"required,uuid"`
+	Code         string          `json:"code" validate:"required,min=3,max=32,alphanum"`
+	Type         string          `json:"type" validate:"required,oneof=percentage fixed"`
+	Value        decimal.Decimal `json:"value" validate:"required,gt=0"`
+	MinSpend     decimal.Decimal `json:"min_spend" validate:"omitempty,gte=0"`
+	UsageLimit   *int            `json:"usage_limit" validate:"omitempty,min=1"`
+	PerUserLimit *int            `json:"per_user_limit" validate:"omitempty,min=1"`
+	ExpiresAt    time.Time       `json:"expires_at" validate:"required"`
+	CategoryIds  []string        `json:"category_ids" validate:"omitempty,max=20,unique_in_slice,dive,uuid"`
+	ProductIds   []string        `json:"product_ids" validate:"omitempty,max=100,unique_in_slice,dive,uuid"`
+}
+
+// UpdateVoucherRequest replaces the terms of a voucher, the code and type
+// are fixed once buyers may have seen them.
+type UpdateVoucherRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId       string          `params:"id" validate:"required,uuid"`
+	Id           string          `params:"voucher_id" validate:"required,uuid"`
+	Value        decimal.Decimal `json:"value" validate:"required,gt=0"`
+	MinSpend     decimal.Decimal `json:"min_spend" validate:"omitempty,gte=0"`
+	UsageLimit   *int            `json:"usage_limit" validate:"omitempty,min=1"`
+	PerUserLimit *int            `json:"per_user_limit" validate:"omitempty,min=1"`
+	ExpiresAt    time.Time       `json:"expires_at" validate:"required"`
+	CategoryIds  []string        `json:"category_ids" validate:"omitempty,max=20,unique_in_slice,dive,uuid"`
+	ProductIds   []string        `json:"product_ids" validate:"omitempty,max=100,unique_in_slice,dive,uuid"`
+}
+
+type DeleteVoucherRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId string `params:"id" validate:"required,uuid"`
+	Id     string `params:"voucher_id" validate:"required,uuid"`
+}
+
+type GetVouchersRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId string `params:"id" validate:"required,uuid"`
+	Page   int    `query:"page" validate:"required,min=1"`
+	Limit  int    `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetVouchersRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type VoucherResponse struct {
+	Id           string          `json:"id" db:"id"`
+	ShopId       string          `json:"shop_id" db:"shop_id"`
+	Code         string          `json:"code" db:"code"`
+	Type         string          `json:"type" db:"type"`
+	Value        decimal.Decimal `json:"value" db:"value"`
+	MinSpend     decimal.Decimal `json:"min_spend" db:"min_spend"`
+	UsageLimit   *int            `json:"usage_limit" db:"usage_limit"`
+	PerUserLimit *int            `json:"per_user_limit" db:"per_user_limit"`
+	UsedCount    int             `json:"used_count" db:"used_count"`
+	ExpiresAt    time.Time       `json:"expires_at" db:"expires_at"`
+	CategoryIds  pq.StringArray  `json:"category_ids" db:"category_ids"`
+	ProductIds   pq.StringArray  `json:"product_ids" db:"product_ids"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+type GetVouchersResponse struct {
+	Items []VoucherResponse `json:"items"`
+	Meta  Meta              `json:"meta"`
+}
+
+type CartItem struct {
+	ProductId string `json:"product_id" validate:"required,uuid"`
+	Quantity  int    `json:"quantity" validate:"required,min=1,max=100000"`
+}
+
+type ValidateVoucherRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId string     `json:"shop_id" validate:"required,uuid"`
+	Code   string     `json:"code" validate:"required,max=32"`
+	Items  []CartItem `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// CartProduct is a cart product as priced right now.
+type CartProduct struct {
+	Id         string          `db:"id"`
+	ShopId     string          `db:"shop_id"`
+	CategoryId string          `db:"category_id"`
+	FinalPrice decimal.Decimal `db:"final_price"`
+	Stock      int             `db:"stock"`
+}
+
+type VoucherLine struct {
+	ProductId string          `json:"product_id"`
+	Quantity  int             `json:"quantity"`
+	UnitPrice decimal.Decimal `json:"unit_price"`
+	LineTotal decimal.Decimal `json:"line_total"`
+	Eligible  bool            `json:"eligible"`
+	Discount  decimal.Decimal `json:"discount"`
+}
+
+type ValidateVoucherResponse struct {
+	VoucherId        string          `json:"voucher_id"`
+	Code             string          `json:"code"`
+	Type             string          `json:"type"`
+	Items            []VoucherLine   `json:"items"`
+	Subtotal         decimal.Decimal `json:"subtotal"`
+	EligibleSubtotal decimal.Decimal `json:"eligible_subtotal"`
+	Discount         decimal.Decimal `json:"discount"`
+	Total            decimal.Decimal `json:"total"`
+}
+
+// RedeemVoucherRequest consumes one use of a voucher for the buyer's order.
+// It is sent by the order service on checkout with the order's cart, which is
+// priced again so the voucher must still apply to it. Redeeming the same
+// order twice is a no-op, so the order service can retry safely.
+type RedeemVoucherRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	BuyerId string     `json:"buyer_id" validate:"required,uuid"`
+	ShopId  string     `json:"shop_id" validate:"required,uuid"`
+	Code    string     `json:"code" validate:"required,max=32"`
+	OrderId string     `json:"order_id" validate:"required,uuid"`
+	Items   []CartItem `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+type RedeemVoucherResponse struct {
+	Id        string    `json:"id" db:"id"`
+	VoucherId string    `json:"voucher_id" db:"voucher_id"`
+	OrderId   string    `json:"order_id" db:"order_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type Meta struct {
+	TotalData int `json:"total_data"`
+	TotalPage int `json:"total_page"`
+	Page      int `json:"page"`
+	Limit     int `json:"limit"`
+}
+
+func (m *Meta) CountTotalPage() {
+	if m.TotalData == 0 {
+		m.TotalPage = 0
+		return
+	}
+
+	m.TotalPage = m.TotalData / m.Limit
+	if m.TotalData%m.Limit > 0 {
+		m.TotalPage++
+	}
+}
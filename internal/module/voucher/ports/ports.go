@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/voucher/entity"
+)
+
+type VoucherService interface {
+	CreateVoucher(ctx context.Context, req *entity.CreateVoucherRequest) (entity.VoucherResponse, error)
+	UpdateVoucher(ctx context.Context, req *entity.UpdateVoucherRequest) (entity.VoucherResponse, error)
+	DeleteVoucher(ctx context.Context, req *entity.DeleteVoucherRequest) error
+	GetVouchers(ctx context.Context, req *entity.GetVouchersRequest) (entity.GetVouchersResponse, error)
+	ValidateVoucher(ctx context.Context, req *entity.ValidateVoucherRequest) (entity.ValidateVoucherResponse, error)
+	RedeemVoucher(ctx context.Context, req *entity.RedeemVoucherRequest) (entity.RedeemVoucherResponse, error)
+}
+
+type VoucherRepository interface {
+	CreateVoucher(ctx context.Context, req *entity.CreateVoucherRequest) (entity.VoucherResponse, error)
+	UpdateVoucher(ctx context.Context, req *entity.UpdateVoucherRequest) (entity.VoucherResponse, error)
+	DeleteVoucher(ctx context.Context, req *entity.DeleteVoucherRequest) error
+	GetVouchers(ctx context.Context, req *entity.GetVouchersRequest) (entity.GetVouchersResponse, error)
+	RedeemVoucher(ctx context.Context, req *entity.RedeemVoucherRequest, checkCart func(voucher entity.VoucherResponse, products []entity.CartProduct) error) (entity.RedeemVoucherResponse, error)
+
+	GetVoucherById(ctx context.Context, shopId, voucherId string) (entity.VoucherResponse, error)
+	GetVoucherByCode(ctx context.Context, shopId, code string) (entity.VoucherResponse, error)
+	CountUserUsages(ctx context.Context, voucherId, userId string) (int, error)
+	GetCartProducts(ctx context.Context, productIds []string) ([]entity.CartProduct, error)
+	IsShopOwner(ctx context.Context, userId, shopId string) (bool, error)
+	CountShopProducts(ctx context.Context, shopId string, productIds []string) (int, error)
+	CountCategories(ctx context.Context, categoryIds []string) (int, error)
+}
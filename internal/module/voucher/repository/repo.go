@@ -0,0 +1,531 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	productRepository "product-service/internal/module/product/repository"
+	"product-service/internal/module/voucher/entity"
+	"product-service/internal/module/voucher/ports"
+	"product-service/pkg/errmsg"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const voucherColumns = `
+	id,
+	shop_id,
+	code,
+	type,
+	value,
+	min_spend,
+	usage_limit,
+	per_user_limit,
+	used_count,
+	expires_at,
+	category_ids,
+	product_ids,
+	created_at,
+	updated_at
+`
+
+type voucherRepository struct {
+	db *sqlx.DB
+}
+
+func NewVoucherRepository(db *sqlx.DB) ports.VoucherRepository {
+	return &voucherRepository{
+		db: db,
+	}
+}
+
+func (r *voucherRepository) CreateVoucher(ctx context.Context, req *entity.CreateVoucherRequest) (entity.VoucherResponse, error) {
+	var (
+		res entity.VoucherResponse
+	)
+
+	query := `
+		INSERT INTO
+			vouchers (
+				shop_id,
+				code,
+				type,
+				value,
+				min_spend,
+				usage_limit,
+				per_user_limit,
+				expires_at,
+				category_ids,
+				product_ids
+			)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING
+	` + voucherColumns
+
+	err := r.db.QueryRowxContext(ctx, query,
+		req.ShopId,
+		req.Code,
+		req.Type,
+		req.Value,
+		req.MinSpend,
+		req.UsageLimit,
+		req.PerUserLimit,
+		req.ExpiresAt.UTC(),
+		pq.Array(nonNil(req.CategoryIds)),
+		pq.Array(nonNil(req.ProductIds)),
+	).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateVoucher failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *voucherRepository) UpdateVoucher(ctx context.Context, req *entity.UpdateVoucherRequest) (entity.VoucherResponse, error) {
+	var (
+		res entity.VoucherResponse
+	)
+
+	query := `
+		UPDATE
+			vouchers
+		SET
+			value = $3,
+			min_spend = $4,
+			usage_limit = $5,
+			per_user_limit = $6,
+			expires_at = $7,
+			category_ids = $8,
+			product_ids = $9,
+			updated_at = NOW()
+		WHERE
+			id = $1
+			AND shop_id = $2
+			AND deleted_at IS NULL
+		RETURNING
+	` + voucherColumns
+
+	err := r.db.QueryRowxContext(ctx, query,
+		req.Id,
+		req.ShopId,
+		req.Value,
+		req.MinSpend,
+		req.UsageLimit,
+		req.PerUserLimit,
+		req.ExpiresAt.UTC(),
+		pq.Array(nonNil(req.CategoryIds)),
+		pq.Array(nonNil(req.ProductIds)),
+	).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Voucher not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Voucher not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateVoucher failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *voucherRepository) DeleteVoucher(ctx context.Context, req *entity.DeleteVoucherRequest) error {
+	query := `
+		UPDATE
+			vouchers
+		SET
+			deleted_at = NOW()
+		WHERE
+			id = $1
+			AND shop_id = $2
+			AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, req.Id, req.ShopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteVoucher failed")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteVoucher failed")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("payload", req).Msg("repository: Voucher not found")
+		return errmsg.NewCostumErrors(404, errmsg.WithMessage("Voucher not found"))
+	}
+
+	return nil
+}
+
+func (r *voucherRepository) GetVouchers(ctx context.Context, req *entity.GetVouchersRequest) (entity.GetVouchersResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.VoucherResponse
+	}
+	var (
+		res  entity.GetVouchersResponse
+		data = make([]dao, 0)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.VoucherResponse, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+	` + voucherColumns + `
+		FROM
+			vouchers
+		WHERE
+			shop_id = $1
+			AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2
+		OFFSET $3
+	`
+
+	err := r.db.SelectContext(ctx, &data, query, req.ShopId, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetVouchers failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.VoucherResponse)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+// RedeemVoucher locks the voucher row so the overall and per-user limits are
+// checked and consumed atomically. The cart is priced in the same
+// transaction and passed to checkCart, which rejects carts the voucher no
+// longer applies to.
+func (r *voucherRepository) RedeemVoucher(ctx context.Context, req *entity.RedeemVoucherRequest, checkCart func(voucher entity.VoucherResponse, products []entity.CartProduct) error) (entity.RedeemVoucherResponse, error) {
+	var (
+		res     entity.RedeemVoucherResponse
+		voucher struct {
+			entity.VoucherResponse
+			IsExpired bool `db:"is_expired"`
+		}
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RedeemVoucher failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT
+			` + voucherColumns + `,
+			expires_at <= NOW() AS is_expired
+		FROM
+			vouchers
+		WHERE
+			shop_id = $1
+			AND code = $2
+			AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &voucher, query, req.ShopId, req.Code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Voucher not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Voucher not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: RedeemVoucher failed")
+		return res, err
+	}
+
+	redeemedQuery := `
+		SELECT
+			id,
+			voucher_id,
+			order_id,
+			created_at
+		FROM
+			voucher_usages
+		WHERE
+			voucher_id = $1
+			AND order_id = $2
+	`
+
+	err = tx.GetContext(ctx, &res, redeemedQuery, voucher.Id, req.OrderId)
+	switch {
+	case err == nil:
+		// the order already redeemed this voucher
+		return res, nil
+	case err != sql.ErrNoRows:
+		log.Error().Err(err).Any("payload", req).Msg("repository: RedeemVoucher failed")
+		return res, err
+	}
+
+	if voucher.IsExpired {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("code", "voucher has expired."))
+	}
+
+	if voucher.UsageLimit != nil && voucher.UsedCount >= *voucher.UsageLimit {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("code", "voucher has been fully used."))
+	}
+
+	if voucher.PerUserLimit != nil {
+		var used int
+
+		usedQuery := `SELECT COUNT(*) FROM voucher_usages WHERE voucher_id = $1 AND user_id = $2`
+
+		err = tx.GetContext(ctx, &used, usedQuery, voucher.Id, req.BuyerId)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository: RedeemVoucher failed to count usages")
+			return res, err
+		}
+
+		if used >= *voucher.PerUserLimit {
+			return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("code", "voucher usage limit per user reached."))
+		}
+	}
+
+	productIds := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		productIds = append(productIds, item.ProductId)
+	}
+
+	products, err := cartProducts(ctx, tx, productIds)
+	if err != nil {
+		return res, err
+	}
+
+	if err = checkCart(voucher.VoucherResponse, products); err != nil {
+		return res, err
+	}
+
+	insertQuery := `
+		INSERT INTO
+			voucher_usages (voucher_id, user_id, order_id)
+		VALUES ($1, $2, $3)
+		RETURNING
+			id,
+			voucher_id,
+			order_id,
+			created_at
+	`
+
+	err = tx.QueryRowxContext(ctx, insertQuery, voucher.Id, req.BuyerId, req.OrderId).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RedeemVoucher failed")
+		return res, err
+	}
+
+	counterQuery := `
+		UPDATE
+			vouchers
+		SET
+			used_count = used_count + 1
+		WHERE
+			id = $1
+	`
+
+	_, err = tx.ExecContext(ctx, counterQuery, voucher.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RedeemVoucher failed to increment used count")
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RedeemVoucher failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *voucherRepository) GetVoucherById(ctx context.Context, shopId, voucherId string) (entity.VoucherResponse, error) {
+	var (
+		res     entity.VoucherResponse
+		payload = struct {
+			ShopId    string `json:"shop_id"`
+			VoucherId string `json:"voucher_id"`
+		}{shopId, voucherId}
+	)
+
+	query := `SELECT ` + voucherColumns + ` FROM vouchers WHERE id = $1 AND shop_id = $2 AND deleted_at IS NULL`
+
+	err := r.db.GetContext(ctx, &res, query, voucherId, shopId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", payload).Msg("repository: Voucher not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Voucher not found"))
+		}
+		log.Error().Err(err).Any("payload", payload).Msg("repository: GetVoucherById failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *voucherRepository) GetVoucherByCode(ctx context.Context, shopId, code string) (entity.VoucherResponse, error) {
+	var (
+		res     entity.VoucherResponse
+		payload = struct {
+			ShopId string `json:"shop_id"`
+			Code   string `json:"code"`
+		}{shopId, code}
+	)
+
+	query := `SELECT ` + voucherColumns + ` FROM vouchers WHERE shop_id = $1 AND code = $2 AND deleted_at IS NULL`
+
+	err := r.db.GetContext(ctx, &res, query, shopId, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", payload).Msg("repository: Voucher not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Voucher not found"))
+		}
+		log.Error().Err(err).Any("payload", payload).Msg("repository: GetVoucherByCode failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *voucherRepository) CountUserUsages(ctx context.Context, voucherId, userId string) (int, error) {
+	var used int
+
+	query := `SELECT COUNT(*) FROM voucher_usages WHERE voucher_id = $1 AND user_id = $2`
+
+	err := r.db.GetContext(ctx, &used, query, voucherId, userId)
+	if err != nil {
+		log.Error().Err(err).Str("voucher_id", voucherId).Str("user_id", userId).Msg("repository: CountUserUsages failed")
+		return used, err
+	}
+
+	return used, nil
+}
+
+// GetCartProducts prices the cart at the effective price, so a voucher stacks
+// on top of running discounts and campaigns.
+func (r *voucherRepository) GetCartProducts(ctx context.Context, productIds []string) ([]entity.CartProduct, error) {
+	return cartProducts(ctx, r.db, productIds)
+}
+
+func cartProducts(ctx context.Context, q sqlx.QueryerContext, productIds []string) ([]entity.CartProduct, error) {
+	var (
+		res = make([]entity.CartProduct, 0)
+	)
+
+	query := `
+		SELECT
+			products.id,
+			products.shop_id,
+			products.category_id,
+			products.stock,
+			` + productRepository.EffectivePrice + ` AS final_price
+		FROM
+			products
+		` + productRepository.DiscountJoin("products") + `
+		WHERE
+			products.id = ANY($1::uuid[])
+			AND products.deleted_at IS NULL
+			AND ` + productRepository.Published("products") + `
+	`
+
+	err := sqlx.SelectContext(ctx, q, &res, query, pq.Array(productIds))
+	if err != nil {
+		log.Error().Err(err).Any("payload", productIds).Msg("repository: GetCartProducts failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *voucherRepository) IsShopOwner(ctx context.Context, userId, shopId string) (bool, error) {
+	var (
+		isOwner bool
+		payload = struct {
+			UserId string `json:"user_id"`
+			ShopId string `json:"shop_id"`
+		}{userId, shopId}
+	)
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM
+					shops
+				WHERE
+					user_id = $1
+					AND id = $2
+					AND deleted_at IS NULL
+			)
+	`
+
+	err := r.db.GetContext(ctx, &isOwner, query, userId, shopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", payload).Msg("repository: IsShopOwner failed")
+		return isOwner, err
+	}
+
+	return isOwner, nil
+}
+
+func (r *voucherRepository) CountShopProducts(ctx context.Context, shopId string, productIds []string) (int, error) {
+	var count int
+
+	query := `
+		SELECT
+			COUNT(*)
+		FROM
+			products
+		WHERE
+			shop_id = $1
+			AND id = ANY($2::uuid[])
+			AND deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &count, query, shopId, pq.Array(productIds))
+	if err != nil {
+		log.Error().Err(err).Str("shop_id", shopId).Any("payload", productIds).Msg("repository: CountShopProducts failed")
+		return count, err
+	}
+
+	return count, nil
+}
+
+func (r *voucherRepository) CountCategories(ctx context.Context, categoryIds []string) (int, error) {
+	var count int
+
+	query := `
+		SELECT
+			COUNT(*)
+		FROM
+			product_categories
+		WHERE
+			id = ANY($1::uuid[])
+			AND deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &count, query, pq.Array(categoryIds))
+	if err != nil {
+		log.Error().Err(err).Any("payload", categoryIds).Msg("repository: CountCategories failed")
+		return count, err
+	}
+
+	return count, nil
+}
+
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
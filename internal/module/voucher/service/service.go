@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/internal/module/voucher/entity"
+	"product-service/internal/module/voucher/ports"
+	"product-service/pkg/decimal"
+	"product-service/pkg/errmsg"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type voucherService struct {
+	repo ports.VoucherRepository
+}
+
+func NewVoucherService(r ports.VoucherRepository) ports.VoucherService {
+	return &voucherService{
+		repo: r,
+	}
+}
+
+func (s *voucherService) CreateVoucher(ctx context.Context, req *entity.CreateVoucherRequest) (entity.VoucherResponse, error) {
+	var res entity.VoucherResponse
+
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return res, err
+	}
+
+	req.Code = strings.ToUpper(req.Code)
+
+	if req.Type == entity.TypePercentage && !req.Value.LessThan(decimal.NewFromInt(100)) {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("value", "value must be less than 100 for percentage vouchers."))
+	}
+
+	if err := s.checkTerms(ctx, req.ShopId, req.ExpiresAt, req.CategoryIds, req.ProductIds); err != nil {
+		return res, err
+	}
+
+	return s.repo.CreateVoucher(ctx, req)
+}
+
+func (s *voucherService) UpdateVoucher(ctx context.Context, req *entity.UpdateVoucherRequest) (entity.VoucherResponse, error) {
+	var res entity.VoucherResponse
+
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return res, err
+	}
+
+	current, err := s.repo.GetVoucherById(ctx, req.ShopId, req.Id)
+	if err != nil {
+		return res, err
+	}
+
+	if current.Type == entity.TypePercentage && !req.Value.LessThan(decimal.NewFromInt(100)) {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("value", "value must be less than 100 for percentage vouchers."))
+	}
+
+	if err := s.checkTerms(ctx, req.ShopId, req.ExpiresAt, req.CategoryIds, req.ProductIds); err != nil {
+		return res, err
+	}
+
+	return s.repo.UpdateVoucher(ctx, req)
+}
+
+func (s *voucherService) DeleteVoucher(ctx context.Context, req *entity.DeleteVoucherRequest) error {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteVoucher(ctx, req)
+}
+
+func (s *voucherService) GetVouchers(ctx context.Context, req *entity.GetVouchersRequest) (entity.GetVouchersResponse, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return entity.GetVouchersResponse{}, err
+	}
+
+	return s.repo.GetVouchers(ctx, req)
+}
+
+// ValidateVoucher prices the cart and works out what the voucher takes off.
+// It doesn't consume the voucher, the order service redeems it on checkout.
+func (s *voucherService) ValidateVoucher(ctx context.Context, req *entity.ValidateVoucherRequest) (entity.ValidateVoucherResponse, error) {
+	var res entity.ValidateVoucherResponse
+
+	voucher, err := s.repo.GetVoucherByCode(ctx, req.ShopId, strings.ToUpper(req.Code))
+	if err != nil {
+		return res, err
+	}
+
+	if !voucher.ExpiresAt.After(time.Now()) {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("code", "voucher has expired."))
+	}
+
+	if voucher.UsageLimit != nil && voucher.UsedCount >= *voucher.UsageLimit {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("code", "voucher has been fully used."))
+	}
+
+	if voucher.PerUserLimit != nil {
+		used, err := s.repo.CountUserUsages(ctx, voucher.Id, req.UserId)
+		if err != nil {
+			return res, err
+		}
+
+		if used >= *voucher.PerUserLimit {
+			return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("code", "voucher usage limit per user reached."))
+		}
+	}
+
+	productIds := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		productIds = append(productIds, item.ProductId)
+	}
+
+	products, err := s.repo.GetCartProducts(ctx, productIds)
+	if err != nil {
+		return res, err
+	}
+
+	return priceCart(voucher, req.Items, products)
+}
+
+func (s *voucherService) RedeemVoucher(ctx context.Context, req *entity.RedeemVoucherRequest) (entity.RedeemVoucherResponse, error) {
+	req.Code = strings.ToUpper(req.Code)
+
+	return s.repo.RedeemVoucher(ctx, req, func(voucher entity.VoucherResponse, products []entity.CartProduct) error {
+		_, err := priceCart(voucher, req.Items, products)
+		return err
+	})
+}
+
+// priceCart works out what the voucher takes off the cart, products being the
+// available cart products as priced right now. It fails when the voucher
+// doesn't apply to the cart.
+func priceCart(voucher entity.VoucherResponse, items []entity.CartItem, products []entity.CartProduct) (entity.ValidateVoucherResponse, error) {
+	var res entity.ValidateVoucherResponse
+
+	productById := make(map[string]entity.CartProduct, len(products))
+	for _, p := range products {
+		productById[p.Id] = p
+	}
+
+	res.VoucherId = voucher.Id
+	res.Code = voucher.Code
+	res.Type = voucher.Type
+	res.Items = make([]entity.VoucherLine, 0, len(items))
+
+	eligibleLines := make([]int, 0, len(items))
+	for i, item := range items {
+		p, ok := productById[item.ProductId]
+		if !ok {
+			return res, errmsg.NewCostumErrors(422, errmsg.WithErrors(fmt.Sprintf("items.%d.product_id", i), "product is not available."))
+		}
+
+		line := entity.VoucherLine{
+			ProductId: p.Id,
+			Quantity:  item.Quantity,
+			UnitPrice: p.FinalPrice,
+			Eligible:  isEligible(voucher, p),
+		}
+
+		var err error
+		if line.LineTotal, err = p.FinalPrice.Mul(int64(item.Quantity)); err != nil {
+			return res, errCartTotal(err)
+		}
+
+		if res.Subtotal, err = res.Subtotal.Add(line.LineTotal); err != nil {
+			return res, errCartTotal(err)
+		}
+
+		if line.Eligible {
+			if res.EligibleSubtotal, err = res.EligibleSubtotal.Add(line.LineTotal); err != nil {
+				return res, errCartTotal(err)
+			}
+			eligibleLines = append(eligibleLines, len(res.Items))
+		}
+
+		res.Items = append(res.Items, line)
+	}
+
+	if len(eligibleLines) == 0 {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("items", "no items in the cart are eligible for this voucher."))
+	}
+
+	if res.EligibleSubtotal.LessThan(voucher.MinSpend) {
+		return res, errmsg.NewCostumErrors(422, errmsg.WithErrors("items", fmt.Sprintf("minimum spend of %s not reached.", voucher.MinSpend)))
+	}
+
+	switch voucher.Type {
+	case entity.TypePercentage:
+		discount, err := res.EligibleSubtotal.MulDiv(voucher.Value, decimal.NewFromInt(100))
+		if err != nil {
+			return res, errCartTotal(err)
+		}
+		res.Discount = discount.Round(2)
+	case entity.TypeFixed:
+		res.Discount = voucher.Value.Min(res.EligibleSubtotal)
+	}
+
+	if err := allocateDiscount(res.Items, eligibleLines, res.Discount, res.EligibleSubtotal); err != nil {
+		return res, errCartTotal(err)
+	}
+
+	total, err := res.Subtotal.Sub(res.Discount)
+	if err != nil {
+		return res, errCartTotal(err)
+	}
+	res.Total = total
+
+	return res, nil
+}
+
+// errCartTotal reports a cart whose amounts don't fit a decimal.
+func errCartTotal(err error) error {
+	log.Warn().Err(err).Msg("service: Cart total out of range")
+	return errmsg.NewCostumErrors(422, errmsg.WithErrors("items", "cart total is too large."))
+}
+
+func (s *voucherService) checkShopOwner(ctx context.Context, userId, shopId string) error {
+	isOwner, err := s.repo.IsShopOwner(ctx, userId, shopId)
+	if err != nil {
+		return err
+	}
+
+	if !isOwner {
+		log.Warn().Str("user_id", userId).Str("shop_id", shopId).Msg("service: User is not shop owner")
+		return errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not shop owner"))
+	}
+
+	return nil
+}
+
+func (s *voucherService) checkTerms(ctx context.Context, shopId string, expiresAt time.Time, categoryIds, productIds []string) error {
+	if !expiresAt.After(time.Now()) {
+		return errmsg.NewCostumErrors(422, errmsg.WithErrors("expires_at", "expires at must be in the future."))
+	}
+
+	if len(categoryIds) > 0 {
+		count, err := s.repo.CountCategories(ctx, categoryIds)
+		if err != nil {
+			return err
+		}
+
+		if count != len(categoryIds) {
+			return errmsg.NewCostumErrors(422, errmsg.WithErrors("category_ids", "one or more categories do not exist."))
+		}
+	}
+
+	if len(productIds) > 0 {
+		count, err := s.repo.CountShopProducts(ctx, shopId, productIds)
+		if err != nil {
+			return err
+		}
+
+		if count != len(productIds) {
+			return errmsg.NewCostumErrors(422, errmsg.WithErrors("product_ids", "one or more products do not belong to the shop."))
+		}
+	}
+
+	return nil
+}
+
+// isEligible reports whether the voucher covers the product. A voucher without
+// restrictions covers the whole shop, otherwise the product must be listed
+// itself or sit in one of the listed categories.
+func isEligible(voucher entity.VoucherResponse, p entity.CartProduct) bool {
+	if p.ShopId != voucher.ShopId {
+		return false
+	}
+
+	if len(voucher.CategoryIds) == 0 && len(voucher.ProductIds) == 0 {
+		return true
+	}
+
+	return slices.Contains(voucher.ProductIds, p.Id) || slices.Contains(voucher.CategoryIds, p.CategoryId)
+}
+
+// allocateDiscount spreads the discount over the eligible lines in proportion
+// to their totals. The last line takes the rounding remainder so the lines
+// always add up to the discount.
+func allocateDiscount(items []entity.VoucherLine, eligibleLines []int, discount, eligibleSubtotal decimal.Decimal) error {
+	remaining := discount
+	for i, idx := range eligibleLines {
+		if i == len(eligibleLines)-1 {
+			items[idx].Discount = remaining
+			return nil
+		}
+
+		share := decimal.Zero
+		if eligibleSubtotal.Sign() > 0 {
+			exact, err := discount.MulDiv(items[idx].LineTotal, eligibleSubtotal)
+			if err != nil {
+				return err
+			}
+			share = exact.Round(2)
+		}
+
+		items[idx].Discount = share
+
+		var err error
+		if remaining, err = remaining.Sub(share); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package entity
+
+import (
+	"encoding/json"
+	outboxEntity "product-service/internal/module/outbox/entity"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+
+	// EventTest is only sent by the test endpoint, it can't be subscribed to
+	EventTest = "webhook.test"
+)
+
+// Events are the events a webhook can subscribe to.
+var Events = []string{
+	outboxEntity.EventProductCreated,
+	outboxEntity.EventProductUpdated,
+	outboxEntity.EventProductDeleted,
+	outboxEntity.EventProductStockChanged,
+	outboxEntity.EventProductLowStock,
+	outboxEntity.EventProductApproved,
+	outboxEntity.EventProductRejected,
+	outboxEntity.EventProductPublished,
+	outboxEntity.EventProductUnpublished,
+}
+
+type CreateWebhookRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId string   `params:"id" validate:"required,uuid"`
+	Url    string   `json:"url" validate:"required,http_url,max=2048"`
+	Secret string   `json:"secret" validate:"omitempty,min=16,max=255"`
+	Events []string `json:"events" validate:"required,min=1,unique_in_slice,dive,required"`
+}
+
+// UpdateWebhookRequest replaces the url and events, an empty secret keeps
+// the current one and an empty is_active keeps the webhook as it is.
+type UpdateWebhookRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId   string   `params:"id" validate:"required,uuid"`
+	Id       string   `params:"webhook_id" validate:"required,uuid"`
+	Url      string   `json:"url" validate:"required,http_url,max=2048"`
+	Secret   string   `json:"secret" validate:"omitempty,min=16,max=255"`
+	Events   []string `json:"events" validate:"required,min=1,unique_in_slice,dive,required"`
+	IsActive *bool    `json:"is_active"`
+}
+
+type DeleteWebhookRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId string `params:"id" validate:"required,uuid"`
+	Id     string `params:"webhook_id" validate:"required,uuid"`
+}
+
+type GetWebhooksRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId string `params:"id" validate:"required,uuid"`
+}
+
+type TestWebhookRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId string `params:"id" validate:"required,uuid"`
+	Id     string `params:"webhook_id" validate:"required,uuid"`
+}
+
+type GetDeliveriesRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ShopId    string `params:"id" validate:"required,uuid"`
+	WebhookId string `params:"webhook_id" validate:"required,uuid"`
+	Status    string `query:"status" validate:"omitempty,oneof=pending succeeded failed"`
+	Page      int    `query:"page" validate:"required,min=1"`
+	Limit     int    `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetDeliveriesRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type WebhookResponse struct {
+	Id        string         `json:"id" db:"id"`
+	ShopId    string         `json:"shop_id" db:"shop_id"`
+	Url       string         `json:"url" db:"url"`
+	Events    pq.StringArray `json:"events" db:"events"`
+	IsActive  bool           `json:"is_active" db:"is_active"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// CreateWebhookResponse is the only response showing the secret.
+type CreateWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret" db:"secret"`
+}
+
+type GetWebhooksResponse struct {
+	Items []WebhookResponse `json:"items"`
+}
+
+type DeliveryResponse struct {
+	Id             string          `json:"id" db:"id"`
+	WebhookId      string          `json:"webhook_id" db:"webhook_id"`
+	EventId        string          `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at" db:"next_attempt_at"`
+	ResponseStatus *int            `json:"response_status" db:"response_status"`
+	ResponseBody   *string         `json:"-" db:"response_body"` // kept for operators, never echoed to the shop
+	LastError      *string         `json:"last_error" db:"last_error"`
+	DurationMs     *int            `json:"duration_ms" db:"duration_ms"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at" db:"delivered_at"`
+}
+
+type GetDeliveriesResponse struct {
+	Items []DeliveryResponse `json:"items"`
+	Meta  Meta               `json:"meta"`
+}
+
+// Envelope is the body every webhook receives, Id stays the same across the
+// retries of an event.
+type Envelope struct {
+	Id        string          `json:"id"`
+	Type      string          `json:"type"`
+	ShopId    string          `json:"shop_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// PendingDelivery is a delivery claimed for an attempt.
+type PendingDelivery struct {
+	Id        string `db:"id"`
+	EventType string `db:"event_type"`
+	Payload   []byte `db:"payload"`
+	Attempts  int    `db:"attempts"`
+	Url       string `db:"url"`
+	Secret    string `db:"secret"`
+}
+
+// DeliveryAttempt is the outcome of an attempt as the log records it.
+type DeliveryAttempt struct {
+	Id             string
+	Status         string
+	ResponseStatus *int
+	ResponseBody   *string
+	LastError      *string
+	DurationMs     int
+	RetryIn        time.Duration
+}
+
+type Meta struct {
+	TotalData int `json:"total_data"`
+	TotalPage int `json:"total_page"`
+	Page      int `json:"page"`
+	Limit     int `json:"limit"`
+}
+
+func (m *Meta) CountTotalPage() {
+	if m.TotalData == 0 {
+		m.TotalPage = 0
+		return
+	}
+
+	m.TotalPage = m.TotalData / m.Limit
+	if m.TotalData%m.Limit > 0 {
+		m.TotalPage++
+	}
+}
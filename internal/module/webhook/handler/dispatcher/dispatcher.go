@@ -0,0 +1,55 @@
+package dispatcher
+
+import (
+	"context"
+	"product-service/internal/adapter"
+	"product-service/internal/module/webhook/ports"
+	"product-service/internal/module/webhook/repository"
+	"product-service/internal/module/webhook/service"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// webhookDispatcher periodically sends the webhook deliveries that are due.
+// Replicas may run one each, a delivery is claimed by a single dispatcher.
+type webhookDispatcher struct {
+	service  ports.WebhookService
+	interval time.Duration
+}
+
+func NewWebhookDispatcher(interval, timeout time.Duration, batchSize, maxAttempts int) *webhookDispatcher {
+	repo := repository.NewWebhookRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewWebhookService(repo, timeout, batchSize, maxAttempts)
+
+	return &webhookDispatcher{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is cancelled, deliveries being sent are recorded
+// when they can be and retried after their lease otherwise.
+func (d *webhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", d.interval).Msg("dispatcher: Webhook dispatcher started")
+
+	for {
+		sent, err := d.service.Dispatch(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Error().Err(err).Msg("dispatcher: Webhook dispatch failed")
+		case sent > 0:
+			log.Info().Int("attempted", sent).Msg("dispatcher: Sent webhook deliveries")
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("dispatcher: Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package rest
+
+import (
+	"product-service/internal/adapter"
+	"product-service/internal/infrastructure"
+	m "product-service/internal/middleware"
+	"product-service/internal/module/webhook/entity"
+	"product-service/internal/module/webhook/ports"
+	"product-service/internal/module/webhook/repository"
+	"product-service/internal/module/webhook/service"
+	"product-service/pkg/errmsg"
+	"product-service/pkg/response"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type webhookHandler struct {
+	service ports.WebhookService
+}
+
+func NewWebhookHandler() *webhookHandler {
+	var (
+		envs    = infrastructure.Envs.Webhook
+		repo    = repository.NewWebhookRepository(adapter.Adapters.ShopeefunProductPostgres)
+		service = service.NewWebhookService(repo, time.Duration(envs.Timeout)*time.Second, envs.BatchSize, envs.MaxAttempts)
+	)
+
+	return &webhookHandler{
+		service: service,
+	}
+}
+
+func (h *webhookHandler) Register(router fiber.Router) {
+	router.Get("/shops/:id/webhooks", m.AuthQueryParams, h.getWebhooks)
+	router.Post("/shops/:id/webhooks", m.AuthQueryParams, h.createWebhook)
+	router.Patch("/shops/:id/webhooks/:webhook_id", m.AuthQueryParams, h.updateWebhook)
+	router.Delete("/shops/:id/webhooks/:webhook_id", m.AuthQueryParams, h.deleteWebhook)
+	router.Get("/shops/:id/webhooks/:webhook_id/deliveries", m.AuthQueryParams, h.getDeliveries)
+	router.Post("/shops/:id/webhooks/:webhook_id/test", m.AuthQueryParams, h.testWebhook)
+}
+
+func (h *webhookHandler) createWebhook(c *fiber.Ctx) error {
+	var (
+		req = &entity.CreateWebhookRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ShopId = c.Params("id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Str("shop_id", req.ShopId).Str("url", req.Url).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateWebhook(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *webhookHandler) getWebhooks(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetWebhooksRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ShopId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetWebhooks(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *webhookHandler) updateWebhook(c *fiber.Ctx) error {
+	var (
+		req = &entity.UpdateWebhookRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("webhook_id")
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Str("id", req.Id).Str("url", req.Url).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpdateWebhook(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *webhookHandler) deleteWebhook(c *fiber.Ctx) error {
+	var (
+		req = &entity.DeleteWebhookRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("webhook_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.DeleteWebhook(ctx, req); err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *webhookHandler) getDeliveries(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetDeliveriesRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.ShopId = c.Params("id")
+	req.WebhookId = c.Params("webhook_id")
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetDeliveries(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *webhookHandler) testWebhook(c *fiber.Ctx) error {
+	var (
+		req = &entity.TestWebhookRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("webhook_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.TestWebhook(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
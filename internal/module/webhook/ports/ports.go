@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/webhook/entity"
+	"time"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, req *entity.CreateWebhookRequest) (entity.CreateWebhookResponse, error)
+	GetWebhooks(ctx context.Context, req *entity.GetWebhooksRequest) (entity.GetWebhooksResponse, error)
+	UpdateWebhook(ctx context.Context, req *entity.UpdateWebhookRequest) (entity.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, req *entity.DeleteWebhookRequest) error
+	GetDeliveries(ctx context.Context, req *entity.GetDeliveriesRequest) (entity.GetDeliveriesResponse, error)
+	TestWebhook(ctx context.Context, req *entity.TestWebhookRequest) (entity.DeliveryResponse, error)
+
+	Dispatch(ctx context.Context) (int, error)
+}
+
+type WebhookRepository interface {
+	IsShopOwner(ctx context.Context, userId, shopId string) (bool, error)
+	CreateWebhook(ctx context.Context, req *entity.CreateWebhookRequest) (entity.CreateWebhookResponse, error)
+	GetWebhooks(ctx context.Context, req *entity.GetWebhooksRequest) (entity.GetWebhooksResponse, error)
+	UpdateWebhook(ctx context.Context, req *entity.UpdateWebhookRequest) (entity.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, req *entity.DeleteWebhookRequest) error
+	GetDeliveries(ctx context.Context, req *entity.GetDeliveriesRequest) (entity.GetDeliveriesResponse, error)
+	GetDelivery(ctx context.Context, id string) (entity.DeliveryResponse, error)
+
+	CreateTestDelivery(ctx context.Context, req *entity.TestWebhookRequest, lease time.Duration) (entity.PendingDelivery, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.PendingDelivery, error)
+	RecordAttempt(ctx context.Context, attempt entity.DeliveryAttempt) error
+}
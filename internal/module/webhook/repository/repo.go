@@ -0,0 +1,474 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"product-service/internal/module/webhook/entity"
+	"product-service/internal/module/webhook/ports"
+	"product-service/pkg/errmsg"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const webhookColumns = `
+	id,
+	shop_id,
+	url,
+	events,
+	is_active,
+	created_at,
+	updated_at
+`
+
+const deliveryColumns = `
+	id,
+	webhook_id,
+	event_id,
+	event_type,
+	payload,
+	status,
+	attempts,
+	CASE WHEN status = 'pending' THEN next_attempt_at END AS next_attempt_at,
+	response_status,
+	response_body,
+	last_error,
+	duration_ms,
+	created_at,
+	delivered_at
+`
+
+type webhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) ports.WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+// Fanout queues the event for every active webhook of the shop subscribed
+// to it. It runs in the transaction writing the event, the same way the
+// outbox does.
+func Fanout(ctx context.Context, tx sqlx.ExtContext, envelope entity.Envelope) error {
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		log.Error().Err(err).Str("event_type", envelope.Type).Msg("repository: Fanout failed to marshal payload")
+		return err
+	}
+
+	query := `
+		INSERT INTO
+			webhook_deliveries (webhook_id, event_id, event_type, payload)
+		SELECT
+			id, $2, CAST($3 AS TEXT), $4
+		FROM
+			webhooks
+		WHERE
+			shop_id = $1
+			AND is_active
+			AND deleted_at IS NULL
+			AND CAST($3 AS TEXT) = ANY(events)
+	`
+
+	_, err = tx.ExecContext(ctx, query, envelope.ShopId, envelope.Id, envelope.Type, payload)
+	if err != nil {
+		log.Error().Err(err).Str("event_type", envelope.Type).Str("shop_id", envelope.ShopId).Msg("repository: Fanout failed")
+		return err
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) IsShopOwner(ctx context.Context, userId, shopId string) (bool, error) {
+	var (
+		isOwner bool
+		payload = struct {
+			UserId string `json:"user_id"`
+			ShopId string `json:"shop_id"`
+		}{userId, shopId}
+	)
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM
+					shops
+				WHERE
+					user_id = $1
+					AND id = $2
+					AND deleted_at IS NULL
+			)
+	`
+
+	err := r.db.GetContext(ctx, &isOwner, query, userId, shopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", payload).Msg("repository: IsShopOwner failed")
+		return isOwner, err
+	}
+
+	return isOwner, nil
+}
+
+func (r *webhookRepository) CreateWebhook(ctx context.Context, req *entity.CreateWebhookRequest) (entity.CreateWebhookResponse, error) {
+	var (
+		res entity.CreateWebhookResponse
+	)
+
+	query := `
+		INSERT INTO
+			webhooks (shop_id, url, secret, events)
+		VALUES ($1, $2, $3, $4)
+		RETURNING
+			secret,
+	` + webhookColumns
+
+	err := r.db.QueryRowxContext(ctx, query, req.ShopId, req.Url, req.Secret, pq.Array(req.Events)).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Str("shop_id", req.ShopId).Str("url", req.Url).Msg("repository: CreateWebhook failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *webhookRepository) GetWebhooks(ctx context.Context, req *entity.GetWebhooksRequest) (entity.GetWebhooksResponse, error) {
+	var (
+		res = entity.GetWebhooksResponse{Items: make([]entity.WebhookResponse, 0)}
+	)
+
+	query := `
+		SELECT
+	` + webhookColumns + `
+		FROM
+			webhooks
+		WHERE
+			shop_id = $1
+			AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	err := r.db.SelectContext(ctx, &res.Items, query, req.ShopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetWebhooks failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *webhookRepository) UpdateWebhook(ctx context.Context, req *entity.UpdateWebhookRequest) (entity.WebhookResponse, error) {
+	var (
+		res entity.WebhookResponse
+	)
+
+	query := `
+		UPDATE
+			webhooks
+		SET
+			url = $3,
+			secret = COALESCE(NULLIF($4, ''), secret),
+			events = $5,
+			is_active = COALESCE($6, is_active),
+			updated_at = NOW()
+		WHERE
+			id = $1
+			AND shop_id = $2
+			AND deleted_at IS NULL
+		RETURNING
+	` + webhookColumns
+
+	err := r.db.QueryRowxContext(ctx, query, req.Id, req.ShopId, req.Url, req.Secret, pq.Array(req.Events), req.IsActive).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", req.Id).Str("shop_id", req.ShopId).Msg("repository: Webhook not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Webhook not found"))
+		}
+		log.Error().Err(err).Str("id", req.Id).Str("shop_id", req.ShopId).Msg("repository: UpdateWebhook failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// DeleteWebhook also gives up the deliveries still pending, they would go
+// to a URL the shop no longer wants called.
+func (r *webhookRepository) DeleteWebhook(ctx context.Context, req *entity.DeleteWebhookRequest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteWebhook failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE
+			webhooks
+		SET
+			deleted_at = NOW()
+		WHERE
+			id = $1
+			AND shop_id = $2
+			AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, req.Id, req.ShopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteWebhook failed")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteWebhook failed")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("payload", req).Msg("repository: Webhook not found")
+		return errmsg.NewCostumErrors(404, errmsg.WithMessage("Webhook not found"))
+	}
+
+	query = `
+		UPDATE
+			webhook_deliveries
+		SET
+			status = 'failed',
+			last_error = 'webhook deleted'
+		WHERE
+			webhook_id = $1
+			AND status = 'pending'
+	`
+
+	if _, err = tx.ExecContext(ctx, query, req.Id); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteWebhook failed to cancel deliveries")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteWebhook failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) GetDeliveries(ctx context.Context, req *entity.GetDeliveriesRequest) (entity.GetDeliveriesResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.DeliveryResponse
+	}
+	var (
+		res  entity.GetDeliveriesResponse
+		data = make([]dao, 0)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.DeliveryResponse, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+	` + deliveryColumns + `
+		FROM
+			webhook_deliveries
+		WHERE
+			webhook_id = (SELECT id FROM webhooks WHERE id = $1 AND shop_id = $2)
+			AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC
+		LIMIT $4
+		OFFSET $5
+	`
+
+	err := r.db.SelectContext(ctx, &data, query, req.WebhookId, req.ShopId, req.Status, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetDeliveries failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.DeliveryResponse)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, id string) (entity.DeliveryResponse, error) {
+	var res entity.DeliveryResponse
+
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	if err := r.db.GetContext(ctx, &res, query, id); err != nil {
+		log.Error().Err(err).Str("id", id).Msg("repository: GetDelivery failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// CreateTestDelivery queues a webhook.test delivery already claimed by the
+// caller, so the dispatcher leaves it alone while it is being sent.
+func (r *webhookRepository) CreateTestDelivery(ctx context.Context, req *entity.TestWebhookRequest, lease time.Duration) (entity.PendingDelivery, error) {
+	var (
+		res     entity.PendingDelivery
+		eventId = uuid.NewString()
+	)
+
+	data, err := json.Marshal(map[string]string{"webhook_id": req.Id})
+	if err != nil {
+		return res, err
+	}
+
+	payload, err := json.Marshal(entity.Envelope{
+		Id:        eventId,
+		Type:      entity.EventTest,
+		ShopId:    req.ShopId,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	query := `
+		WITH webhook AS (
+			SELECT
+				id,
+				url,
+				secret
+			FROM
+				webhooks
+			WHERE
+				id = $1
+				AND shop_id = $2
+				AND deleted_at IS NULL
+		),
+		delivery AS (
+			INSERT INTO
+				webhook_deliveries (webhook_id, event_id, event_type, payload, attempts, next_attempt_at)
+			SELECT
+				id, $3, $4, $5, 1, NOW() + make_interval(secs => $6)
+			FROM
+				webhook
+			RETURNING
+				id,
+				event_type,
+				payload,
+				attempts
+		)
+		SELECT
+			delivery.id,
+			delivery.event_type,
+			delivery.payload,
+			delivery.attempts,
+			webhook.url,
+			webhook.secret
+		FROM
+			delivery,
+			webhook
+	`
+
+	err = r.db.QueryRowxContext(ctx, query, req.Id, req.ShopId, eventId, entity.EventTest, payload, lease.Seconds()).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Webhook not found")
+			return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Webhook not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateTestDelivery failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// ClaimDeliveries takes due deliveries for one attempt. Claiming counts the
+// attempt and pushes next_attempt_at past the lease, so other dispatchers
+// skip them while they are sent, and a dispatcher dying mid-send only
+// delays them.
+func (r *webhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.PendingDelivery, error) {
+	res := make([]entity.PendingDelivery, 0)
+
+	query := `
+		WITH due AS (
+			SELECT
+				id
+			FROM
+				webhook_deliveries
+			WHERE
+				status = 'pending'
+				AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE
+			webhook_deliveries d
+		SET
+			attempts = d.attempts + 1,
+			next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM
+			due,
+			webhooks w
+		WHERE
+			d.id = due.id
+			AND w.id = d.webhook_id
+		RETURNING
+			d.id,
+			d.event_type,
+			d.payload,
+			d.attempts,
+			w.url,
+			w.secret
+	`
+
+	err := r.db.SelectContext(ctx, &res, query, limit, lease.Seconds())
+	if err != nil {
+		log.Error().Err(err).Msg("repository: ClaimDeliveries failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *webhookRepository) RecordAttempt(ctx context.Context, attempt entity.DeliveryAttempt) error {
+	query := `
+		UPDATE
+			webhook_deliveries
+		SET
+			status = $2,
+			response_status = $3,
+			response_body = $4,
+			last_error = $5,
+			duration_ms = $6,
+			next_attempt_at = NOW() + make_interval(secs => $7),
+			delivered_at = CASE WHEN $8 THEN NOW() END
+		WHERE
+			id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		attempt.Id,
+		attempt.Status,
+		attempt.ResponseStatus,
+		attempt.ResponseBody,
+		attempt.LastError,
+		attempt.DurationMs,
+		attempt.RetryIn.Seconds(),
+		attempt.Status == entity.DeliverySucceeded,
+	)
+	if err != nil {
+		log.Error().Err(err).Str("id", attempt.Id).Msg("repository: RecordAttempt failed")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"product-service/internal/module/webhook/entity"
+	"product-service/internal/module/webhook/ports"
+	"product-service/pkg/errmsg"
+	"product-service/pkg/webhook"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// retryBase and retryMax bound the exponential backoff between attempts
+	retryBase = 30 * time.Second
+	retryMax  = 6 * time.Hour
+)
+
+type webhookService struct {
+	repo        ports.WebhookRepository
+	client      *webhook.Client
+	lease       time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+// NewWebhookService sends webhooks with timeout, a delivery is retried
+// until it made maxAttempts attempts.
+func NewWebhookService(r ports.WebhookRepository, timeout time.Duration, batchSize, maxAttempts int) ports.WebhookService {
+	return &webhookService{
+		repo:   r,
+		client: webhook.NewClient(timeout),
+		// long enough for a claimed delivery to be sent and recorded
+		lease:       timeout + 30*time.Second,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, req *entity.CreateWebhookRequest) (entity.CreateWebhookResponse, error) {
+	var res entity.CreateWebhookResponse
+
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return res, err
+	}
+
+	if err := checkEvents(req.Events); err != nil {
+		return res, err
+	}
+
+	if req.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			log.Error().Err(err).Msg("service: Failed to generate webhook secret")
+			return res, err
+		}
+		req.Secret = secret
+	}
+
+	return s.repo.CreateWebhook(ctx, req)
+}
+
+func (s *webhookService) GetWebhooks(ctx context.Context, req *entity.GetWebhooksRequest) (entity.GetWebhooksResponse, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return entity.GetWebhooksResponse{}, err
+	}
+
+	return s.repo.GetWebhooks(ctx, req)
+}
+
+func (s *webhookService) UpdateWebhook(ctx context.Context, req *entity.UpdateWebhookRequest) (entity.WebhookResponse, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return entity.WebhookResponse{}, err
+	}
+
+	if err := checkEvents(req.Events); err != nil {
+		return entity.WebhookResponse{}, err
+	}
+
+	return s.repo.UpdateWebhook(ctx, req)
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, req *entity.DeleteWebhookRequest) error {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteWebhook(ctx, req)
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, req *entity.GetDeliveriesRequest) (entity.GetDeliveriesResponse, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return entity.GetDeliveriesResponse{}, err
+	}
+
+	return s.repo.GetDeliveries(ctx, req)
+}
+
+// TestWebhook sends a webhook.test event right away and returns how it went.
+// A failed test is retried like any other delivery.
+func (s *webhookService) TestWebhook(ctx context.Context, req *entity.TestWebhookRequest) (entity.DeliveryResponse, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return entity.DeliveryResponse{}, err
+	}
+
+	delivery, err := s.repo.CreateTestDelivery(ctx, req, s.lease)
+	if err != nil {
+		return entity.DeliveryResponse{}, err
+	}
+
+	if err = s.deliver(ctx, delivery); err != nil {
+		return entity.DeliveryResponse{}, err
+	}
+
+	return s.repo.GetDelivery(ctx, delivery.Id)
+}
+
+// Dispatch sends the due deliveries, a run claiming a full batch is
+// followed by another straight away. It returns how many were attempted.
+func (s *webhookService) Dispatch(ctx context.Context) (int, error) {
+	var total int
+
+	for {
+		deliveries, err := s.repo.ClaimDeliveries(ctx, s.batchSize, s.lease)
+		if err != nil {
+			return total, err
+		}
+
+		var (
+			wg   sync.WaitGroup
+			errs = make([]error, len(deliveries))
+		)
+
+		for i, delivery := range deliveries {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = s.deliver(ctx, delivery)
+			}()
+		}
+		wg.Wait()
+
+		total += len(deliveries)
+
+		for _, err := range errs {
+			if err != nil {
+				return total, err
+			}
+		}
+
+		if len(deliveries) < s.batchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
+
+// deliver makes one attempt at a claimed delivery and records it.
+func (s *webhookService) deliver(ctx context.Context, delivery entity.PendingDelivery) error {
+	result, err := s.client.Send(ctx, webhook.Request{
+		Url:        delivery.Url,
+		Secret:     delivery.Secret,
+		Event:      delivery.EventType,
+		DeliveryId: delivery.Id,
+		Body:       delivery.Payload,
+	})
+
+	attempt := entity.DeliveryAttempt{
+		Id:         delivery.Id,
+		Status:     entity.DeliverySucceeded,
+		DurationMs: int(result.Duration.Milliseconds()),
+	}
+
+	if result.StatusCode != 0 {
+		attempt.ResponseStatus = &result.StatusCode
+		attempt.ResponseBody = &result.Body
+	}
+
+	if err != nil {
+		msg := err.Error()
+		attempt.LastError = &msg
+		attempt.Status = entity.DeliveryPending
+		attempt.RetryIn = backoff(delivery.Attempts)
+
+		if delivery.Attempts >= s.maxAttempts {
+			attempt.Status = entity.DeliveryFailed
+		}
+
+		log.Warn().Err(err).Str("delivery_id", delivery.Id).Str("event_type", delivery.EventType).Int("attempts", delivery.Attempts).Msg("service: Webhook delivery failed")
+	}
+
+	return s.repo.RecordAttempt(ctx, attempt)
+}
+
+func (s *webhookService) checkShopOwner(ctx context.Context, userId, shopId string) error {
+	isOwner, err := s.repo.IsShopOwner(ctx, userId, shopId)
+	if err != nil {
+		return err
+	}
+
+	if !isOwner {
+		log.Warn().Str("user_id", userId).Str("shop_id", shopId).Msg("service: User is not shop owner")
+		return errmsg.NewCostumErrors(403, errmsg.WithMessage("User is not shop owner"))
+	}
+
+	return nil
+}
+
+func checkEvents(events []string) error {
+	for _, event := range events {
+		if !slices.Contains(entity.Events, event) {
+			return errmsg.NewCostumErrors(422, errmsg.WithErrors("events", event+" is not a webhook event."))
+		}
+	}
+
+	return nil
+}
+
+// newSecret returns 32 random bytes hex encoded.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// backoff doubles the wait after every failed attempt up to retryMax.
+func backoff(attempts int) time.Duration {
+	wait := retryBase
+	for i := 1; i < attempts && wait < retryMax; i++ {
+		wait *= 2
+	}
+
+	return min(wait, retryMax)
+}
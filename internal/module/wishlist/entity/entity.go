@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"product-service/internal/module/product/entity"
+	"time"
+)
+
+type AddWishlistRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId string `params:"product_id" validate:"required,uuid"`
+}
+
+type RemoveWishlistRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	ProductId string `params:"product_id" validate:"required,uuid"`
+}
+
+type WishlistResponse struct {
+	ProductId      string    `json:"product_id" db:"product_id"`
+	FavouriteCount int       `json:"favourite_count" db:"favourite_count"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+type GetWishlistRequest struct {
+	UserId string `query:"user_id" validate:"required,uuid"`
+
+	Page  int `query:"page" validate:"required,min=1"`
+	Limit int `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetWishlistRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type GetWishlistResponse struct {
+	Items []WishlistItem `json:"items"`
+	Meta  entity.Meta    `json:"meta"`
+}
+
+// WishlistItem keeps products that went out of stock or were deleted after
+// being saved, flagged so clients can grey them out.
+type WishlistItem struct {
+	entity.Product
+	IsAvailable bool      `json:"is_available" db:"is_available"`
+	IsDeleted   bool      `json:"is_deleted" db:"is_deleted"`
+	WishedAt    time.Time `json:"wished_at" db:"wished_at"`
+}
@@ -0,0 +1,113 @@
+package rest
+
+import (
+	"product-service/internal/adapter"
+	m "product-service/internal/middleware"
+	"product-service/internal/module/wishlist/entity"
+	"product-service/internal/module/wishlist/ports"
+	"product-service/internal/module/wishlist/repository"
+	"product-service/internal/module/wishlist/service"
+	"product-service/pkg/errmsg"
+	"product-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type wishlistHandler struct {
+	service ports.WishlistService
+}
+
+func NewWishlistHandler() *wishlistHandler {
+	repo := repository.NewWishlistRepository(adapter.Adapters.ShopeefunProductPostgres)
+	service := service.NewWishlistService(repo, adapter.Adapters.Cache)
+
+	return &wishlistHandler{
+		service: service,
+	}
+}
+
+func (h *wishlistHandler) Register(router fiber.Router) {
+	router.Get("/wishlist", m.AuthQueryParams, h.getWishlist)
+	router.Post("/wishlist/:product_id", m.AuthQueryParams, h.addWishlist)
+	router.Delete("/wishlist/:product_id", m.AuthQueryParams, h.removeWishlist)
+}
+
+func (h *wishlistHandler) addWishlist(c *fiber.Ctx) error {
+	var (
+		req = &entity.AddWishlistRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ProductId = c.Params("product_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.AddWishlist(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *wishlistHandler) removeWishlist(c *fiber.Ctx) error {
+	var (
+		req = &entity.RemoveWishlistRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.UserId = c.Query("user_id")
+	req.ProductId = c.Params("product_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.RemoveWishlist(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *wishlistHandler) getWishlist(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetWishlistRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetWishlist(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"product-service/internal/module/wishlist/entity"
+)
+
+type WishlistService interface {
+	AddWishlist(ctx context.Context, req *entity.AddWishlistRequest) (entity.WishlistResponse, error)
+	RemoveWishlist(ctx context.Context, req *entity.RemoveWishlistRequest) error
+	GetWishlist(ctx context.Context, req *entity.GetWishlistRequest) (entity.GetWishlistResponse, error)
+}
+
+type WishlistRepository interface {
+	AddWishlist(ctx context.Context, req *entity.AddWishlistRequest) (entity.WishlistResponse, error)
+	RemoveWishlist(ctx context.Context, req *entity.RemoveWishlistRequest) error
+	GetWishlist(ctx context.Context, req *entity.GetWishlistRequest) (entity.GetWishlistResponse, error)
+
+	IsProductExist(ctx context.Context, productId string) (bool, error)
+}
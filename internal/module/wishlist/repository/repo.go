@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	productRepository "product-service/internal/module/product/repository"
+	"product-service/internal/module/wishlist/entity"
+	"product-service/internal/module/wishlist/ports"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type wishlistRepository struct {
+	db *sqlx.DB
+}
+
+func NewWishlistRepository(db *sqlx.DB) ports.WishlistRepository {
+	return &wishlistRepository{
+		db: db,
+	}
+}
+
+func (r *wishlistRepository) AddWishlist(ctx context.Context, req *entity.AddWishlistRequest) (entity.WishlistResponse, error) {
+	var (
+		res = entity.WishlistResponse{ProductId: req.ProductId}
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: AddWishlist failed to begin transaction")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO
+			wishlists (user_id, product_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, product_id) DO NOTHING
+		RETURNING
+			created_at
+	`
+
+	err = tx.QueryRowxContext(ctx, query, req.UserId, req.ProductId).Scan(&res.CreatedAt)
+	switch {
+	case err == sql.ErrNoRows:
+		// already in the wishlist, adding it again is a no-op
+		existingQuery := `
+			SELECT
+				w.created_at,
+				p.favourite_count
+			FROM
+				wishlists w
+			JOIN
+				products p ON p.id = w.product_id
+			WHERE
+				w.user_id = $1
+				AND w.product_id = $2
+		`
+
+		err = tx.QueryRowxContext(ctx, existingQuery, req.UserId, req.ProductId).Scan(&res.CreatedAt, &res.FavouriteCount)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository: AddWishlist failed")
+			return res, err
+		}
+	case err != nil:
+		log.Error().Err(err).Any("payload", req).Msg("repository: AddWishlist failed")
+		return res, err
+	default:
+		counterQuery := `
+			UPDATE
+				products
+			SET
+				favourite_count = favourite_count + 1,
+				updated_at = NOW()
+			WHERE
+				id = $1
+			RETURNING
+				favourite_count
+		`
+
+		err = tx.QueryRowxContext(ctx, counterQuery, req.ProductId).Scan(&res.FavouriteCount)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository: AddWishlist failed to increment favourite count")
+			return res, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: AddWishlist failed to commit transaction")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r *wishlistRepository) RemoveWishlist(ctx context.Context, req *entity.RemoveWishlistRequest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RemoveWishlist failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		DELETE FROM
+			wishlists
+		WHERE
+			user_id = $1
+			AND product_id = $2
+	`
+
+	result, err := tx.ExecContext(ctx, query, req.UserId, req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RemoveWishlist failed")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RemoveWishlist failed")
+		return err
+	}
+
+	if affected > 0 {
+		counterQuery := `
+			UPDATE
+				products
+			SET
+				favourite_count = GREATEST(favourite_count - 1, 0),
+				updated_at = NOW()
+			WHERE
+				id = $1
+		`
+
+		_, err = tx.ExecContext(ctx, counterQuery, req.ProductId)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository: RemoveWishlist failed to decrement favourite count")
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RemoveWishlist failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *wishlistRepository) GetWishlist(ctx context.Context, req *entity.GetWishlistRequest) (entity.GetWishlistResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.WishlistItem
+	}
+	var (
+		res  entity.GetWishlistResponse
+		data = make([]dao, 0)
+	)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+	res.Items = make([]entity.WishlistItem, 0)
+
+	// deleted products are kept on purpose, the buyer still saved them
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			p.id,
+			p.category_id,
+			p.shop_id,
+			p.name,
+			p.image_url,
+			p.price,
+			p.currency,
+		` + productRepository.PriceColumns("p") + `,
+			p.brand,
+			p.favourite_count,
+			TRUE AS in_wishlist,
+			p.created_at,
+			p.updated_at,
+			(p.stock > 0 AND p.deleted_at IS NULL AND ` + productRepository.Published("p") + `) AS is_available,
+			(p.deleted_at IS NOT NULL) AS is_deleted,
+			w.created_at AS wished_at
+		FROM
+			wishlists w
+		JOIN
+			products p ON p.id = w.product_id
+		` + productRepository.DiscountJoin("p") + `
+		WHERE
+			w.user_id = $1
+		ORDER BY w.created_at DESC
+		LIMIT $2
+		OFFSET $3
+	`
+
+	err := r.db.SelectContext(ctx, &data, query, req.UserId, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetWishlist failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.WishlistItem)
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+func (r *wishlistRepository) IsProductExist(ctx context.Context, productId string) (bool, error) {
+	var exist bool
+
+	query := `SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL AND ` + productRepository.Published("products") + `)`
+
+	err := r.db.GetContext(ctx, &exist, query, productId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", productId).Msg("repository: IsProductExist failed")
+		return exist, err
+	}
+
+	return exist, nil
+}
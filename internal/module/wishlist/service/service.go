@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	productService "product-service/internal/module/product/service"
+	"product-service/internal/module/wishlist/entity"
+	"product-service/internal/module/wishlist/ports"
+	"product-service/pkg/cache"
+	"product-service/pkg/errmsg"
+
+	"github.com/rs/zerolog/log"
+)
+
+type wishlistService struct {
+	repo  ports.WishlistRepository
+	cache cache.Cache
+}
+
+func NewWishlistService(r ports.WishlistRepository, c cache.Cache) ports.WishlistService {
+	return &wishlistService{
+		repo:  r,
+		cache: c,
+	}
+}
+
+func (s *wishlistService) AddWishlist(ctx context.Context, req *entity.AddWishlistRequest) (entity.WishlistResponse, error) {
+	var res entity.WishlistResponse
+
+	exist, err := s.repo.IsProductExist(ctx, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if !exist {
+		log.Warn().Any("payload", req).Msg("service: Product not found")
+		return res, errmsg.NewCostumErrors(404, errmsg.WithMessage("Product not found"))
+	}
+
+	res, err = s.repo.AddWishlist(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	// cached products show the favourite_count
+	productService.InvalidateProducts(ctx, s.cache, req.ProductId)
+	return res, nil
+}
+
+func (s *wishlistService) RemoveWishlist(ctx context.Context, req *entity.RemoveWishlistRequest) error {
+	if err := s.repo.RemoveWishlist(ctx, req); err != nil {
+		return err
+	}
+
+	productService.InvalidateProducts(ctx, s.cache, req.ProductId)
+	return nil
+}
+
+func (s *wishlistService) GetWishlist(ctx context.Context, req *entity.GetWishlistRequest) (entity.GetWishlistResponse, error) {
+	return s.repo.GetWishlist(ctx, req)
+}
@@ -1,10 +1,25 @@
 package route
 
 import (
+	attributeHandler "product-service/internal/module/attribute/handler/rest"
+	brandHandler "product-service/internal/module/brand/handler/rest"
+	campaignHandler "product-service/internal/module/campaign/handler/rest"
+	currencyHandler "product-service/internal/module/currency/handler/rest"
+	discountHandler "product-service/internal/module/discount/handler/rest"
+	inquiryHandler "product-service/internal/module/inquiry/handler/rest"
 	productHandler "product-service/internal/module/product/handler/rest"
+	reviewHandler "product-service/internal/module/review/handler/rest"
 	shopHandler "product-service/internal/module/shop/handler/rest"
+	storefrontHandler "product-service/internal/module/storefront/handler/graphql"
+	userHandler "product-service/internal/module/user/handler/rest"
+	voucherHandler "product-service/internal/module/voucher/handler/rest"
+	webhookHandler "product-service/internal/module/webhook/handler/rest"
+	wishlistHandler "product-service/internal/module/wishlist/handler/rest"
 
+	"product-service/internal/adapter"
+	m "product-service/internal/middleware"
 	"product-service/pkg/response"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -12,14 +27,39 @@ import (
 func SetupRoutes(app *fiber.App) {
 	// add /api prefix to all routes
 	api := app.Group("/api")
+
+	// Cache-Control of the catalogue, registered ahead of the handlers. A CDN
+	// may keep anonymous listings and products, they are revalidated with
+	// their ETag once stale.
+	api.Get("/products", m.CacheControl(m.CachePolicy{MaxAge: 30 * time.Second, SharedMaxAge: time.Minute}))
+	api.Get("/products/:id<guid>", m.CacheControl(m.CachePolicy{MaxAge: 30 * time.Second, SharedMaxAge: time.Minute}))
+	api.Get("/products/:id<guid>/related", m.CacheControl(m.CachePolicy{MaxAge: 5 * time.Minute, SharedMaxAge: 10 * time.Minute}))
+
 	shopHandler.NewShopHandler().Register(api)
 	productHandler.NewProductHandler().Register(api)
+	inquiryHandler.NewInquiryHandler().Register(api)
+	reviewHandler.NewReviewHandler().Register(api)
+	wishlistHandler.NewWishlistHandler().Register(api)
+	userHandler.NewUserHandler().Register(api)
+	brandHandler.NewBrandHandler().Register(api)
+	attributeHandler.NewAttributeHandler().Register(api)
+	discountHandler.NewDiscountHandler().Register(api)
+	campaignHandler.NewCampaignHandler().Register(api)
+	voucherHandler.NewVoucherHandler().Register(api)
+	currencyHandler.NewCurrencyHandler().Register(api)
+	webhookHandler.NewWebhookHandler().Register(api)
+	storefrontHandler.NewStorefrontHandler().Register(api)
 
 	// health check route
 	api.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(response.Success(nil, "Server is running."))
 	})
 
+	// cache hit rates of this replica
+	api.Get("/health/cache", func(c *fiber.Ctx) error {
+		return c.JSON(response.Success(adapter.Adapters.Cache.Stats(), ""))
+	})
+
 	// fallback route
 	app.Use(func(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusNotFound).JSON(response.Error("Route not found."))
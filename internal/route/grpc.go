@@ -0,0 +1,19 @@
+package route
+
+import (
+	productHandler "product-service/internal/module/product/handler/grpc"
+
+	"product-service/internal/infrastructure"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+func SetupGrpc(srv *grpc.Server) {
+	productHandler.NewProductHandler().Register(srv)
+
+	// lets grpcurl and the like discover the services outside production
+	if infrastructure.Envs.App.Environtment != "production" {
+		reflection.Register(srv)
+	}
+}
@@ -0,0 +1,12 @@
+package pkg
+
+import "regexp"
+
+// attribute codes end up in query params like attr[ram] and attr[screen_min]
+var attributeCodeRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// IsAttributeCode reports whether code is a lowercase snake case attribute
+// code of up to 50 characters.
+func IsAttributeCode(code string) bool {
+	return attributeCodeRegex.MatchString(code)
+}
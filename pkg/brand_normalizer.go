@@ -0,0 +1,28 @@
+package pkg
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	whitespaceRegex    = regexp.MustCompile(`\s+`)
+	companySuffixRegex = regexp.MustCompile(`,?\s+(inc|ltd|llc|corp|co)\.?$`)
+	slugInvalidRegex   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// NormalizeBrandName folds brand spellings like "Apple", "apple " and
+// "APPLE Inc" into the same key. Keep it in sync with the brands migration.
+func NormalizeBrandName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = whitespaceRegex.ReplaceAllString(name, " ")
+	name = companySuffixRegex.ReplaceAllString(name, "")
+	return name
+}
+
+// Slugify turns a name into a lowercase, dash separated ascii slug.
+// It returns an empty string when nothing ascii is left.
+func Slugify(name string) string {
+	slug := slugInvalidRegex.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(slug, "-")
+}
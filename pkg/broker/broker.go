@@ -0,0 +1,40 @@
+// Package broker abstracts the message broker domain events go through, so
+// the outbox relay and consumers don't depend on a particular one.
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// Message is one event on a subject. Id is unique per event, consumers use
+// it to drop redeliveries. Key groups the events of one aggregate.
+type Message struct {
+	Id        string
+	Subject   string
+	Key       string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+type Publisher interface {
+	// Publish returns once the broker accepted the message.
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// Handler processes a delivered message, an error asks for a redelivery.
+type Handler func(ctx context.Context, msg Message) error
+
+type Subscriber interface {
+	// Subscribe registers handler for the subject, deliveries stop when ctx
+	// is cancelled or the subscriber is closed.
+	Subscribe(ctx context.Context, subject string, handler Handler) error
+	Close() error
+}
+
+// Broker is both ends of a broker connection.
+type Broker interface {
+	Publisher
+	Subscriber
+}
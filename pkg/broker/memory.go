@@ -0,0 +1,93 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrClosed = errors.New("broker: closed")
+
+// Memory is an in-process broker for tests and publishers whose subscribers
+// live in the same process. Publish delivers synchronously to the subject's
+// subscribers and returns the first handler error, so a failing handler shows
+// up as a failed publish.
+type Memory struct {
+	mu        sync.RWMutex
+	handlers  map[string][]Handler
+	record    bool
+	published []Message
+	closed    bool
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// NewRecordingMemory is NewMemory keeping every published message for
+// Published. It is meant for tests, the messages are never released.
+func NewRecordingMemory() *Memory {
+	m := NewMemory()
+	m.record = true
+	return m
+}
+
+func (m *Memory) Publish(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return ErrClosed
+	}
+	if m.record {
+		m.published = append(m.published, msg)
+	}
+	handlers := append([]Handler(nil), m.handlers[msg.Subject]...)
+	m.mu.Unlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Memory) Subscribe(ctx context.Context, subject string, handler Handler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	// a cancelled subscription stops seeing messages without unregistering
+	m.handlers[subject] = append(m.handlers[subject], func(hctx context.Context, msg Message) error {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return handler(hctx, msg)
+	})
+
+	return nil
+}
+
+// Published returns every message accepted so far in order, it is always
+// empty unless m was made by NewRecordingMemory.
+func (m *Memory) Published() []Message {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return append([]Message(nil), m.published...)
+}
+
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	m.handlers = make(map[string][]Handler)
+	return nil
+}
@@ -0,0 +1,70 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryPublishSubscribe(t *testing.T) {
+	var (
+		m        = NewRecordingMemory()
+		ctx      = context.Background()
+		received []string
+	)
+
+	err := m.Subscribe(ctx, "product.created", func(ctx context.Context, msg Message) error {
+		received = append(received, msg.Id)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.Publish(ctx, Message{Id: "1", Subject: "product.created"}))
+	assert.NoError(t, m.Publish(ctx, Message{Id: "2", Subject: "product.deleted"}))
+
+	assert.Equal(t, []string{"1"}, received)
+	assert.Len(t, m.Published(), 2)
+}
+
+func TestMemoryDoesNotRetain(t *testing.T) {
+	m := NewMemory()
+
+	assert.NoError(t, m.Publish(context.Background(), Message{Id: "1", Subject: "product.created"}))
+	assert.Empty(t, m.Published())
+}
+
+func TestMemoryHandlerError(t *testing.T) {
+	var (
+		m   = NewMemory()
+		ctx = context.Background()
+		bad = errors.New("handler failed")
+	)
+
+	_ = m.Subscribe(ctx, "product.updated", func(ctx context.Context, msg Message) error {
+		return bad
+	})
+
+	assert.ErrorIs(t, m.Publish(ctx, Message{Id: "1", Subject: "product.updated"}), bad)
+}
+
+func TestMemoryCancelledSubscription(t *testing.T) {
+	var (
+		m           = NewMemory()
+		ctx, cancel = context.WithCancel(context.Background())
+		calls       int
+	)
+
+	_ = m.Subscribe(ctx, "shop.created", func(ctx context.Context, msg Message) error {
+		calls++
+		return nil
+	})
+
+	cancel()
+	assert.NoError(t, m.Publish(context.Background(), Message{Id: "1", Subject: "shop.created"}))
+	assert.Equal(t, 0, calls)
+
+	assert.NoError(t, m.Close())
+	assert.ErrorIs(t, m.Publish(context.Background(), Message{Id: "2", Subject: "shop.created"}), ErrClosed)
+}
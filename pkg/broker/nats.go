@@ -0,0 +1,167 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// KeyHeader carries Message.Key, the message id travels in the JetStream
+// Nats-Msg-Id header so the stream drops duplicate publishes.
+const KeyHeader = "Event-Key"
+
+type NatsConfig struct {
+	Url string
+	// Stream is created with Subjects when it doesn't exist yet, messages
+	// published to other subjects need a stream of their own.
+	Stream   string
+	Subjects []string
+	// Durable prefixes the durable consumer of every subscribed subject, so
+	// replicas sharing it split the messages between them.
+	Durable string
+	// RetryDelay is how long a message waits after its handler failed.
+	RetryDelay time.Duration
+}
+
+// Nats is a JetStream broker. Deliveries are acknowledged once the handler
+// returns nil, so every message is delivered at least once.
+type Nats struct {
+	conn *nats.Conn
+	js   jetstream.JetStream
+	cfg  NatsConfig
+
+	mu       sync.Mutex
+	consumes []jetstream.ConsumeContext
+}
+
+func NewNats(ctx context.Context, cfg NatsConfig) (*Nats, error) {
+	conn, err := nats.Connect(cfg.Url, nats.Name(cfg.Durable))
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if cfg.Stream != "" {
+		_, err = js.Stream(ctx, cfg.Stream)
+		if errors.Is(err, jetstream.ErrStreamNotFound) {
+			_, err = js.CreateStream(ctx, jetstream.StreamConfig{
+				Name:     cfg.Stream,
+				Subjects: cfg.Subjects,
+			})
+		}
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("broker: stream %s: %w", cfg.Stream, err)
+		}
+	}
+
+	return &Nats{
+		conn: conn,
+		js:   js,
+		cfg:  cfg,
+	}, nil
+}
+
+func (n *Nats) Publish(ctx context.Context, msg Message) error {
+	m := nats.NewMsg(msg.Subject)
+	m.Data = msg.Payload
+	m.Header.Set(jetstream.MsgIDHeader, msg.Id)
+	m.Header.Set(KeyHeader, msg.Key)
+
+	_, err := n.js.PublishMsg(ctx, m)
+	return err
+}
+
+// Subscribe consumes the subject through a durable consumer on the stream
+// holding it. Messages are handled one at a time, a handler error asks for
+// a redelivery after RetryDelay. Cancelling ctx lets the message in
+// progress finish.
+func (n *Nats) Subscribe(ctx context.Context, subject string, handler Handler) error {
+	stream, err := n.js.StreamNameBySubject(ctx, subject)
+	if err != nil {
+		return fmt.Errorf("broker: no stream for %s: %w", subject, err)
+	}
+
+	consumer, err := n.js.CreateOrUpdateConsumer(ctx, stream, jetstream.ConsumerConfig{
+		Durable:       durableName(n.cfg.Durable, subject),
+		FilterSubject: subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("broker: consumer for %s: %w", subject, err)
+	}
+
+	handleCtx := context.WithoutCancel(ctx)
+
+	consume, err := consumer.Consume(func(m jetstream.Msg) {
+		msg := Message{
+			Id:      m.Headers().Get(jetstream.MsgIDHeader),
+			Subject: m.Subject(),
+			Key:     m.Headers().Get(KeyHeader),
+			Payload: m.Data(),
+		}
+
+		if meta, err := m.Metadata(); err == nil {
+			msg.CreatedAt = meta.Timestamp
+			if msg.Id == "" {
+				msg.Id = fmt.Sprintf("%s:%d", meta.Stream, meta.Sequence.Stream)
+			}
+		}
+
+		if err := handler(handleCtx, msg); err != nil {
+			_ = m.NakWithDelay(n.cfg.RetryDelay)
+			return
+		}
+		_ = m.Ack()
+	})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.consumes = append(n.consumes, consume)
+	n.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			consume.Drain()
+		case <-consume.Closed():
+		}
+	}()
+
+	return nil
+}
+
+// Close waits for the messages being handled, then closes the connection.
+func (n *Nats) Close() error {
+	n.mu.Lock()
+	consumes := n.consumes
+	n.consumes = nil
+	n.mu.Unlock()
+
+	for _, consume := range consumes {
+		consume.Drain()
+		<-consume.Closed()
+	}
+
+	return n.conn.Drain()
+}
+
+// durableName turns product-service and user.deleted into
+// product-service_user_deleted, durable names can't hold dots or wildcards.
+func durableName(prefix, subject string) string {
+	replacer := strings.NewReplacer(".", "_", "*", "any", ">", "all")
+	return prefix + "_" + replacer.Replace(subject)
+}
@@ -0,0 +1,12 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurableName(t *testing.T) {
+	assert.Equal(t, "product-service_user_deleted", durableName("product-service", "user.deleted"))
+	assert.Equal(t, "product-service_order_any_all", durableName("product-service", "order.*.>"))
+}
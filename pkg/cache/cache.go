@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+var ErrClosed = errors.New("cache: closed")
+
+// Cache stores encoded values by key. Keys are versioned by counters kept
+// next to the values: bumping a counter moves every key built from it, and
+// the entries left behind expire with their ttl.
+type Cache interface {
+	// Get reports a miss with ok false, errors are left for an unreachable
+	// store. Both hits and misses are counted in Stats.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+
+	// Version returns the counter at key, 0 when it was never bumped.
+	Version(ctx context.Context, key string) (int64, error)
+	Bump(ctx context.Context, key string) (int64, error)
+
+	// Stats counts the reads of this process only.
+	Stats() Stats
+	Close() error
+}
+
+type Stats struct {
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// counter keeps the hit and miss counts of a Cache implementation.
+type counter struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func (c *counter) record(hit bool) {
+	if hit {
+		c.hits.Add(1)
+		return
+	}
+	c.misses.Add(1)
+}
+
+func (c *counter) stats() Stats {
+	s := Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRate = float64(s.Hits) / float64(total)
+	}
+
+	return s
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Loader reads through a cache. Concurrent misses of a key share one load,
+// so an expired popular key reaches the database once rather than once per
+// request.
+type Loader struct {
+	cache Cache
+	group singleflight.Group
+}
+
+func NewLoader(c Cache) *Loader {
+	return &Loader{
+		cache: c,
+	}
+}
+
+// Fetch returns the value cached at key, or loads it and caches it for ttl.
+// The cache is best effort: when it fails, or holds a value that no longer
+// decodes, the value is loaded as if it missed. Load errors are returned and
+// never cached.
+func Fetch[T any](ctx context.Context, l *Loader, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+
+	if cached, ok, err := l.cache.Get(ctx, key); err == nil && ok {
+		if err = json.Unmarshal(cached, &value); err == nil {
+			return value, nil
+		}
+	}
+
+	encoded, err, _ := l.group.Do(key, func() (any, error) {
+		// the load is shared, a caller going away must not fail the others
+		ctx := context.WithoutCancel(ctx)
+
+		loaded, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded, err := json.Marshal(loaded)
+		if err != nil {
+			return nil, err
+		}
+
+		_ = l.cache.Set(ctx, key, encoded, ttl)
+		return encoded, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	// every caller decodes its own copy, so none can change another's value
+	err = json.Unmarshal(encoded.([]byte), &value)
+	return value, err
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	Id   string `json:"id"`
+	Tags []string
+}
+
+func TestFetchCachesLoadedValue(t *testing.T) {
+	var (
+		l     = NewLoader(NewMemory(10))
+		ctx   = context.Background()
+		loads int
+	)
+
+	load := func(ctx context.Context) (item, error) {
+		loads++
+		return item{Id: "1", Tags: []string{"a"}}, nil
+	}
+
+	for range 3 {
+		value, err := Fetch(ctx, l, "item:1", time.Minute, load)
+		assert.NoError(t, err)
+		assert.Equal(t, item{Id: "1", Tags: []string{"a"}}, value)
+	}
+
+	assert.Equal(t, 1, loads)
+}
+
+func TestFetchDoesNotCacheErrors(t *testing.T) {
+	var (
+		l     = NewLoader(NewMemory(10))
+		ctx   = context.Background()
+		loads int
+		fail  = errors.New("not found")
+	)
+
+	load := func(ctx context.Context) (item, error) {
+		loads++
+		return item{}, fail
+	}
+
+	_, err := Fetch(ctx, l, "item:1", time.Minute, load)
+	assert.ErrorIs(t, err, fail)
+
+	_, err = Fetch(ctx, l, "item:1", time.Minute, load)
+	assert.ErrorIs(t, err, fail)
+
+	assert.Equal(t, 2, loads)
+}
+
+func TestFetchSharesConcurrentLoads(t *testing.T) {
+	var (
+		l       = NewLoader(NewMemory(10))
+		ctx     = context.Background()
+		loads   atomic.Int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+
+	load := func(ctx context.Context) (item, error) {
+		loads.Add(1)
+		<-release
+		return item{Id: "1"}, nil
+	}
+
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := Fetch(ctx, l, "item:1", time.Minute, load)
+			assert.NoError(t, err)
+			assert.Equal(t, "1", value.Id)
+		}()
+	}
+
+	// let the callers queue up behind the first load
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+}
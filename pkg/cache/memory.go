@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// Memory is an in-process LRU cache holding up to size entries. Version
+// counters are kept apart from the entries and never evicted, an evicted
+// counter would start over and bring back the keys it had moved away from.
+type Memory struct {
+	counter
+
+	mu       sync.Mutex
+	size     int
+	order    *list.List // front is the most recently used
+	entries  map[string]*list.Element
+	versions map[string]int64
+	closed   bool
+}
+
+func NewMemory(size int) *Memory {
+	return &Memory{
+		size:     size,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		versions: make(map[string]int64),
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, false, ErrClosed
+	}
+
+	elem, ok := m.entries[key]
+	if ok && time.Now().After(elem.Value.(*memoryEntry).expiresAt) {
+		m.remove(elem)
+		ok = false
+	}
+
+	m.record(ok)
+	if !ok {
+		return nil, false, nil
+	}
+
+	m.order.MoveToFront(elem)
+	return elem.Value.(*memoryEntry).value, true, nil
+}
+
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	entry := &memoryEntry{key: key, value: value, expiresAt: time.Now().Add(ttl)}
+
+	if elem, ok := m.entries[key]; ok {
+		elem.Value = entry
+		m.order.MoveToFront(elem)
+		return nil
+	}
+
+	m.entries[key] = m.order.PushFront(entry)
+
+	for m.order.Len() > m.size {
+		m.remove(m.order.Back())
+	}
+
+	return nil
+}
+
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	for _, key := range keys {
+		if elem, ok := m.entries[key]; ok {
+			m.remove(elem)
+		}
+	}
+
+	return nil
+}
+
+func (m *Memory) Version(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return 0, ErrClosed
+	}
+
+	return m.versions[key], nil
+}
+
+func (m *Memory) Bump(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return 0, ErrClosed
+	}
+
+	m.versions[key]++
+	return m.versions[key], nil
+}
+
+// Len returns the number of entries, expired ones included until they are
+// read or evicted.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.order.Len()
+}
+
+func (m *Memory) Stats() Stats {
+	return m.stats()
+}
+
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	m.order.Init()
+	m.entries = make(map[string]*list.Element)
+	return nil
+}
+
+func (m *Memory) remove(elem *list.Element) {
+	m.order.Remove(elem)
+	delete(m.entries, elem.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryEvictsLeastRecentlyUsed(t *testing.T) {
+	var (
+		m   = NewMemory(2)
+		ctx = context.Background()
+	)
+
+	assert.NoError(t, m.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.NoError(t, m.Set(ctx, "b", []byte("2"), time.Minute))
+
+	// reading a makes b the least recently used
+	_, ok, err := m.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.NoError(t, m.Set(ctx, "c", []byte("3"), time.Minute))
+
+	_, ok, _ = m.Get(ctx, "b")
+	assert.False(t, ok)
+
+	value, ok, _ := m.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, m.Len())
+
+	assert.Equal(t, Stats{Hits: 2, Misses: 1, HitRate: 2.0 / 3.0}, m.Stats())
+}
+
+func TestMemoryExpiresEntries(t *testing.T) {
+	var (
+		m   = NewMemory(10)
+		ctx = context.Background()
+	)
+
+	assert.NoError(t, m.Set(ctx, "a", []byte("1"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok, err := m.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestMemoryVersions(t *testing.T) {
+	var (
+		m   = NewMemory(1)
+		ctx = context.Background()
+	)
+
+	version, err := m.Version(ctx, "products")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), version)
+
+	version, err = m.Bump(ctx, "products")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	// filling the cache does not evict the counter
+	assert.NoError(t, m.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.NoError(t, m.Set(ctx, "b", []byte("2"), time.Minute))
+
+	version, _ = m.Version(ctx, "products")
+	assert.Equal(t, int64(1), version)
+
+	assert.NoError(t, m.Close())
+	_, err = m.Bump(ctx, "products")
+	assert.ErrorIs(t, err, ErrClosed)
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisConfig struct {
+	Url    string // ex: redis://localhost:6379/0
+	Prefix string // namespaces the keys of this service in a shared server
+}
+
+// Redis keeps the cache in a Redis compatible server, shared by every
+// replica. Version counters are stored without expiry, the server should
+// evict with a volatile-* policy so it never drops them.
+type Redis struct {
+	counter
+
+	client *redis.Client
+	prefix string
+}
+
+func NewRedis(ctx context.Context, cfg RedisConfig) (*Redis, error) {
+	opts, err := redis.ParseURL(cfg.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	client := redis.NewClient(opts)
+
+	if err = client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &Redis{
+		client: client,
+		prefix: cfg.Prefix,
+	}, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			r.record(false)
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	r.record(true)
+	return value, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, r.prefix+key, value, ttl).Err()
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, r.prefix+key)
+	}
+
+	return r.client.Del(ctx, prefixed...).Err()
+}
+
+func (r *Redis) Version(ctx context.Context, key string) (int64, error) {
+	version, err := r.client.Get(ctx, r.prefix+key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	return version, err
+}
+
+func (r *Redis) Bump(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, r.prefix+key).Result()
+}
+
+func (r *Redis) Stats() Stats {
+	return r.stats()
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
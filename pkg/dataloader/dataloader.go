@@ -0,0 +1,89 @@
+// Package dataloader batches the lookups made while resolving a GraphQL
+// query. Keys loaded while one level of the query is resolved are fetched
+// together the first time one of their values is needed, and every key is
+// fetched at most once per loader, so a loader should live for one request.
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchFunc fetches the values of keys in one go. Keys missing from the map
+// are reported as not found rather than as an error.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+type result[V any] struct {
+	value V
+	found bool
+	err   error
+	done  bool
+}
+
+type Loader[K comparable, V any] struct {
+	fetch BatchFunc[K, V]
+
+	mu      sync.Mutex
+	pending []K
+	results map[K]*result[V]
+	batches int
+}
+
+func New[K comparable, V any](fetch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:   fetch,
+		results: make(map[K]*result[V]),
+	}
+}
+
+// Load queues the key and returns a thunk resolving its value. The first
+// thunk called fetches every key queued so far.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, bool, error) {
+	l.mu.Lock()
+	if _, ok := l.results[key]; !ok {
+		l.results[key] = &result[V]{}
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		res := l.results[key]
+		if !res.done {
+			l.dispatch(ctx)
+		}
+
+		return res.value, res.found, res.err
+	}
+}
+
+// Batches reports how many fetches the loader made.
+func (l *Loader[K, V]) Batches() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.batches
+}
+
+// dispatch fetches the pending keys, l.mu must be held.
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+	l.batches++
+
+	values, err := l.fetch(ctx, keys)
+
+	for _, key := range keys {
+		res := l.results[key]
+		res.done = true
+
+		if err != nil {
+			res.err = err
+			continue
+		}
+
+		res.value, res.found = values[key]
+	}
+}
@@ -0,0 +1,80 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("batches the queued keys", func(t *testing.T) {
+		var calls [][]string
+
+		l := New(func(ctx context.Context, keys []string) (map[string]int, error) {
+			calls = append(calls, keys)
+			return map[string]int{"a": 1, "b": 2}, nil
+		})
+
+		a, b, missing, again := l.Load(ctx, "a"), l.Load(ctx, "b"), l.Load(ctx, "c"), l.Load(ctx, "a")
+
+		value, found, err := b()
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, 2, value)
+
+		value, found, _ = a()
+		assert.True(t, found)
+		assert.Equal(t, 1, value)
+
+		_, found, err = missing()
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		value, _, _ = again()
+		assert.Equal(t, 1, value)
+
+		assert.Equal(t, [][]string{{"a", "b", "c"}}, calls)
+	})
+
+	t.Run("fetches keys queued after a batch in the next one", func(t *testing.T) {
+		l := New(func(ctx context.Context, keys []int) (map[int]int, error) {
+			values := make(map[int]int, len(keys))
+			for _, key := range keys {
+				values[key] = key * 10
+			}
+			return values, nil
+		})
+
+		first := l.Load(ctx, 1)
+		_, _, _ = first()
+
+		second, cached := l.Load(ctx, 2), l.Load(ctx, 1)
+		value, _, _ := second()
+		assert.Equal(t, 20, value)
+
+		value, _, _ = cached()
+		assert.Equal(t, 10, value)
+		assert.Equal(t, 2, l.Batches())
+	})
+
+	t.Run("reports the error to every key of the batch", func(t *testing.T) {
+		failure := errors.New("database is down")
+
+		l := New(func(ctx context.Context, keys []string) (map[string]int, error) {
+			return nil, failure
+		})
+
+		a, b := l.Load(ctx, "a"), l.Load(ctx, "b")
+
+		_, _, err := a()
+		assert.ErrorIs(t, err, failure)
+
+		_, _, err = b()
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, 1, l.Batches())
+	})
+}
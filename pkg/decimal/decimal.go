@@ -0,0 +1,342 @@
+// Package decimal provides an exact fixed-point number for money and other
+// values stored as DECIMAL(19, 4), so prices never go through float64.
+package decimal
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places a Decimal holds, matching the
+// DECIMAL(19, 4) columns it is stored in.
+const Scale = 4
+
+const (
+	unit = 10000 // 10^Scale
+
+	// maxUnits bounds values to 14 integer digits so any two of them can be
+	// added without overflowing int64.
+	maxUnits = 999_999_999_999_999_999
+)
+
+var (
+	ErrSyntax = errors.New("decimal: invalid number")
+	ErrScale  = fmt.Errorf("decimal: more than %d decimal places", Scale)
+	ErrRange  = errors.New("decimal: out of range")
+
+	ErrDivisionByZero = errors.New("decimal: division by zero")
+
+	// plain or exponent notation, rejects what big.Rat would also accept
+	// (fractions, hex, underscores)
+	numberRegex = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+	bigUnit = big.NewInt(unit)
+	bigMax  = big.NewInt(maxUnits)
+)
+
+// Decimal is a signed number with Scale decimal places, the zero value is 0.
+// NULL columns scan into *Decimal.
+type Decimal struct {
+	units int64
+}
+
+var Zero = Decimal{}
+
+// NewFromInt returns i as a Decimal.
+func NewFromInt(i int64) Decimal {
+	return Decimal{units: i * unit}
+}
+
+// NewFromFloat returns f rounded half away from zero to Scale places.
+func NewFromFloat(f float64) (Decimal, error) {
+	return parse(strconv.FormatFloat(f, 'f', -1, 64), true)
+}
+
+// Parse reads a decimal in plain or exponent notation. It rejects values with
+// more than Scale decimal places instead of rounding them.
+func Parse(s string) (Decimal, error) {
+	return parse(s, false)
+}
+
+// MustParse is like Parse but panics on error, for constants.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func parse(s string, round bool) (Decimal, error) {
+	units, err := parseUnits(s, round, bigUnit)
+	if err != nil {
+		return Zero, err
+	}
+
+	return Decimal{units: units}, nil
+}
+
+// parseUnits reads s as a whole number of units, one of which is 1/unit.
+func parseUnits(s string, round bool, unit *big.Int) (int64, error) {
+	s = strings.TrimSpace(s)
+	if !numberRegex.MatchString(s) {
+		return 0, ErrSyntax
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, ErrSyntax
+	}
+
+	num := new(big.Int).Mul(r.Num(), unit)
+	quo, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		if !round {
+			return 0, ErrScale
+		}
+		quo = roundQuo(quo, rem, r.Denom())
+	}
+
+	if new(big.Int).Abs(quo).Cmp(bigMax) > 0 {
+		return 0, ErrRange
+	}
+
+	return quo.Int64(), nil
+}
+
+// roundQuo rounds the truncated quotient quo half away from zero given the
+// remainder and the positive divisor it came from.
+func roundQuo(quo, rem, divisor *big.Int) *big.Int {
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	if twice.Cmp(new(big.Int).Abs(divisor)) < 0 {
+		return quo
+	}
+
+	if rem.Sign()*divisor.Sign() < 0 {
+		return quo.Sub(quo, big.NewInt(1))
+	}
+	return quo.Add(quo, big.NewInt(1))
+}
+
+func fromBig(units *big.Int) (Decimal, error) {
+	if new(big.Int).Abs(units).Cmp(bigMax) > 0 {
+		return Zero, ErrRange
+	}
+	return Decimal{units: units.Int64()}, nil
+}
+
+// fromUnits checks a sum of two in range values, which can't overflow int64.
+func fromUnits(units int64) (Decimal, error) {
+	if absUnits(units) > maxUnits {
+		return Zero, ErrRange
+	}
+	return Decimal{units: units}, nil
+}
+
+// Add returns d + o, or ErrRange when the sum has more than 14 integer digits.
+func (d Decimal) Add(o Decimal) (Decimal, error) {
+	return fromUnits(d.units + o.units)
+}
+
+// Sub returns d - o, or ErrRange like Add.
+func (d Decimal) Sub(o Decimal) (Decimal, error) {
+	return fromUnits(d.units - o.units)
+}
+
+// Mul multiplies by an integer, typically a quantity. It returns ErrRange
+// when the product has more than 14 integer digits.
+func (d Decimal) Mul(n int64) (Decimal, error) {
+	return fromBig(new(big.Int).Mul(big.NewInt(d.units), big.NewInt(n)))
+}
+
+// MulDiv returns d * mul / div rounded half away from zero to Scale places,
+// ex: price.MulDiv(percent, NewFromInt(100)). It returns ErrDivisionByZero
+// when div is zero and ErrRange when the result doesn't fit.
+func (d Decimal) MulDiv(mul, div Decimal) (Decimal, error) {
+	if div.units == 0 {
+		return Zero, ErrDivisionByZero
+	}
+
+	num := new(big.Int).Mul(big.NewInt(d.units), big.NewInt(mul.units))
+	divisor := big.NewInt(div.units)
+	quo, rem := new(big.Int).QuoRem(num, divisor, new(big.Int))
+	if rem.Sign() != 0 {
+		quo = roundQuo(quo, rem, divisor)
+	}
+
+	return fromBig(quo)
+}
+
+// Round rounds half away from zero to places decimal places, 0 to Scale.
+func (d Decimal) Round(places int) Decimal {
+	if places >= Scale {
+		return d
+	}
+	if places < 0 {
+		places = 0
+	}
+
+	step := int64(1)
+	for i := places; i < Scale; i++ {
+		step *= 10
+	}
+
+	quo, rem := d.units/step, d.units%step
+	if rem*2 >= step {
+		quo++
+	} else if rem*2 <= -step {
+		quo--
+	}
+
+	return Decimal{units: quo * step}
+}
+
+// Cmp returns -1, 0 or 1 as d is less than, equal to or greater than o.
+func (d Decimal) Cmp(o Decimal) int {
+	switch {
+	case d.units < o.units:
+		return -1
+	case d.units > o.units:
+		return 1
+	}
+	return 0
+}
+
+func (d Decimal) Sign() int {
+	return d.Cmp(Zero)
+}
+
+func (d Decimal) IsZero() bool {
+	return d.units == 0
+}
+
+func (d Decimal) LessThan(o Decimal) bool {
+	return d.units < o.units
+}
+
+func (d Decimal) GreaterThan(o Decimal) bool {
+	return d.units > o.units
+}
+
+// Min returns the smaller of d and o.
+func (d Decimal) Min(o Decimal) Decimal {
+	if o.units < d.units {
+		return o
+	}
+	return d
+}
+
+// Float64 approximates d, it is meant for validation and logging only.
+func (d Decimal) Float64() float64 {
+	return float64(d.units) / unit
+}
+
+// String formats d without trailing zeros, ex: 1500, 19.99, -0.5.
+func (d Decimal) String() string {
+	s := d.StringFixed(Scale)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// StringFixed formats d rounded to exactly places decimal places.
+func (d Decimal) StringFixed(places int) string {
+	if places > Scale {
+		places = Scale
+	}
+	if places < 0 {
+		places = 0
+	}
+
+	return formatUnits(d.Round(places).units, Scale, places)
+}
+
+// formatUnits formats units of 10^-scale with the first places decimals.
+func formatUnits(units int64, scale, places int) string {
+	sign := ""
+	if units < 0 {
+		sign = "-"
+	}
+
+	abs := strconv.FormatUint(absUnits(units), 10)
+	if len(abs) <= scale {
+		abs = strings.Repeat("0", scale-len(abs)+1) + abs
+	}
+
+	integer, fraction := abs[:len(abs)-scale], abs[len(abs)-scale:]
+	if places == 0 {
+		return sign + integer
+	}
+	return sign + integer + "." + fraction[:places]
+}
+
+func absUnits(units int64) uint64 {
+	if units < 0 {
+		return uint64(-units)
+	}
+	return uint64(units)
+}
+
+// Scan reads a numeric column. Values with more places than Scale, as
+// computed columns may have, are rounded.
+func (d *Decimal) Scan(src any) error {
+	var err error
+
+	switch v := src.(type) {
+	case []byte:
+		*d, err = parse(string(v), true)
+	case string:
+		*d, err = parse(v, true)
+	case int64:
+		*d, err = parse(strconv.FormatInt(v, 10), true)
+	case float64:
+		*d, err = NewFromFloat(v)
+	case nil:
+		return errors.New("decimal: cannot scan NULL, use *Decimal")
+	default:
+		return fmt.Errorf("decimal: cannot scan %T", src)
+	}
+
+	return err
+}
+
+// Value writes d as text, which Postgres casts to numeric exactly.
+func (d Decimal) Value() (driver.Value, error) {
+	if absUnits(d.units) > maxUnits {
+		return nil, ErrRange
+	}
+	return d.String(), nil
+}
+
+// MarshalJSON writes d as a JSON number with its exact digits.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a number or a numeric string, ex: 19.99 or "19.99".
+// null leaves d untouched.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*d = v
+	return nil
+}
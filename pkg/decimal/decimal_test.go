@@ -0,0 +1,146 @@
+package decimal
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{in: "19.99", want: "19.99"},
+		{in: "-0.5", want: "-0.5"},
+		{in: "150000", want: "150000"},
+		{in: "1.2e3", want: "1200"},
+		{in: ".25", want: "0.25"},
+		{in: "0.0001", want: "0.0001"},
+		{in: "0.00001", err: ErrScale},
+		{in: "100000000000000", err: ErrRange},
+		{in: "1/3", err: ErrSyntax},
+		{in: "0x10", err: ErrSyntax},
+		{in: "", err: ErrSyntax},
+	}
+
+	for _, c := range cases {
+		d, err := Parse(c.in)
+		if c.err != nil {
+			assert.ErrorIs(t, err, c.err, c.in)
+			continue
+		}
+
+		assert.NoError(t, err, c.in)
+		assert.Equal(t, c.want, d.String(), c.in)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	must := func(d Decimal, err error) Decimal {
+		assert.NoError(t, err)
+		return d
+	}
+
+	price := MustParse("0.1")
+	assert.Equal(t, "0.3", must(price.Add(MustParse("0.2"))).String())
+	assert.Equal(t, "0.3", must(price.Mul(3)).String())
+	assert.Equal(t, "-0.1", must(price.Sub(MustParse("0.2"))).String())
+
+	// 20% off 19.99
+	assert.Equal(t, "15.992", must(MustParse("19.99").MulDiv(NewFromInt(80), NewFromInt(100))).String())
+	assert.Equal(t, "0.3333", must(NewFromInt(1).MulDiv(NewFromInt(1), NewFromInt(3))).String())
+	assert.Equal(t, "-0.6667", must(NewFromInt(-2).MulDiv(NewFromInt(1), NewFromInt(3))).String())
+
+	assert.Equal(t, "15.99", MustParse("15.992").Round(2).String())
+	assert.Equal(t, "1.01", MustParse("1.005").Round(2).String())
+	assert.Equal(t, "-1.01", MustParse("-1.005").Round(2).String())
+	assert.Equal(t, "2", MustParse("1.5").Round(0).String())
+
+	assert.Equal(t, "19.9900", MustParse("19.99").StringFixed(4))
+	assert.Equal(t, "-0.50", MustParse("-0.5").StringFixed(2))
+	assert.Equal(t, 1, MustParse("1.0001").Cmp(NewFromInt(1)))
+}
+
+func TestOverflow(t *testing.T) {
+	largest := MustParse("99999999999999.9999")
+
+	_, err := largest.Add(MustParse("0.0001"))
+	assert.ErrorIs(t, err, ErrRange)
+
+	_, err = MustParse("-99999999999999.9999").Sub(MustParse("0.0001"))
+	assert.ErrorIs(t, err, ErrRange)
+
+	_, err = MustParse("10000000000").Mul(100000)
+	assert.ErrorIs(t, err, ErrRange)
+
+	_, err = largest.MulDiv(NewFromInt(2), NewFromInt(1))
+	assert.ErrorIs(t, err, ErrRange)
+
+	_, err = largest.MulDiv(NewFromInt(1), Zero)
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+
+	// quantities that can't overflow int64 on their own still fail the range
+	_, err = NewFromInt(1).Mul(1 << 62)
+	assert.ErrorIs(t, err, ErrRange)
+}
+
+func TestScanValue(t *testing.T) {
+	var d Decimal
+
+	assert.NoError(t, d.Scan([]byte("12.345678")))
+	assert.Equal(t, "12.3457", d.String())
+
+	assert.NoError(t, d.Scan(int64(7)))
+	assert.Equal(t, "7", d.String())
+
+	assert.Error(t, d.Scan(nil))
+
+	v, err := MustParse("1999.5").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "1999.5", v)
+}
+
+func TestJSON(t *testing.T) {
+	var req struct {
+		Price    Decimal  `json:"price"`
+		MinSpend Decimal  `json:"min_spend"`
+		Lowest   *Decimal `json:"lowest"`
+	}
+
+	err := json.Unmarshal([]byte(`{"price": 19.99, "min_spend": "100.5", "lowest": null}`), &req)
+	assert.NoError(t, err)
+	assert.Equal(t, "19.99", req.Price.String())
+	assert.Equal(t, "100.5", req.MinSpend.String())
+	assert.Nil(t, req.Lowest)
+
+	b, err := json.Marshal(req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"price": 19.99, "min_spend": 100.5, "lowest": null}`, string(b))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price": 0.12345}`), &req))
+	assert.Error(t, json.Unmarshal([]byte(`{"price": true}`), &req))
+}
+
+func TestRate(t *testing.T) {
+	r, err := ParseRate("0.0000625")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0000625", r.String())
+	assert.Equal(t, 1, r.Sign())
+
+	_, err = ParseRate("0.000000001")
+	assert.ErrorIs(t, err, ErrRateScale)
+
+	assert.NoError(t, r.Scan([]byte("16000.000000004")))
+	assert.Equal(t, "16000", r.String())
+
+	var req struct {
+		Rate Rate `json:"rate"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"rate": "0.00006250"}`), &req))
+	b, err := json.Marshal(req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"rate": 0.0000625}`, string(b))
+}
@@ -0,0 +1,119 @@
+package decimal
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the number of decimal places a Rate holds, matching the
+// DECIMAL(19, 8) column exchange rates are stored in. Prices keep Scale
+// places, rates need more to convert into a currency worth far more.
+const RateScale = 8
+
+var (
+	ErrRateScale = fmt.Errorf("decimal: more than %d decimal places", RateScale)
+
+	bigRateUnit = big.NewInt(100_000_000) // 10^RateScale
+)
+
+// Rate is an exchange rate with RateScale decimal places, the zero value is 0.
+type Rate struct {
+	units int64
+}
+
+// ParseRate reads a rate in plain or exponent notation. It rejects values
+// with more than RateScale decimal places instead of rounding them.
+func ParseRate(s string) (Rate, error) {
+	units, err := parseUnits(s, false, bigRateUnit)
+	if errors.Is(err, ErrScale) {
+		return Rate{}, ErrRateScale
+	}
+
+	return Rate{units: units}, err
+}
+
+// Sign returns -1, 0 or 1 as r is negative, zero or positive.
+func (r Rate) Sign() int {
+	switch {
+	case r.units < 0:
+		return -1
+	case r.units > 0:
+		return 1
+	}
+	return 0
+}
+
+// Float64 approximates r, it is meant for validation and logging only.
+func (r Rate) Float64() float64 {
+	return float64(r.units) / float64(bigRateUnit.Int64())
+}
+
+// String formats r without trailing zeros, ex: 0.0000625, 16000.
+func (r Rate) String() string {
+	s := formatUnits(r.units, RateScale, RateScale)
+	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+}
+
+// Scan reads a numeric column, rounding values with more than RateScale
+// places.
+func (r *Rate) Scan(src any) error {
+	var (
+		units int64
+		err   error
+	)
+
+	switch v := src.(type) {
+	case []byte:
+		units, err = parseUnits(string(v), true, bigRateUnit)
+	case string:
+		units, err = parseUnits(v, true, bigRateUnit)
+	case int64:
+		units, err = parseUnits(strconv.FormatInt(v, 10), true, bigRateUnit)
+	case nil:
+		return errors.New("decimal: cannot scan NULL, use *Rate")
+	default:
+		return fmt.Errorf("decimal: cannot scan %T", src)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	r.units = units
+	return nil
+}
+
+// Value writes r as text, which Postgres casts to numeric exactly.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// MarshalJSON writes r as a JSON number with its exact digits.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accepts a number or a numeric string, ex: 0.0000625 or
+// "0.0000625". null leaves r untouched.
+func (r *Rate) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+
+	*r = v
+	return nil
+}
@@ -0,0 +1,90 @@
+package errmsg
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Status is Errors for gRPC: the HTTP code is mapped to a status code and the
+// field errors are attached as BadRequest field violations. Server errors
+// keep their cause out of the message, like the REST responses do.
+func Status[T any](err error, payloads ...*T) *status.Status {
+	if s, ok := status.FromError(err); ok && err != nil {
+		return s
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return status.New(codes.Canceled, err.Error())
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.New(codes.DeadlineExceeded, err.Error())
+	}
+
+	var (
+		code, errs = Errors(err, payloads...)
+		msg        = "Your request has been failed to process"
+		fields     map[string][]string
+	)
+
+	switch errs := errs.(type) {
+	case *CostumError:
+		msg, fields = errs.Msg, errs.Errors
+	case map[string][]string:
+		fields = errs
+	}
+
+	s := status.New(GrpcCode(code), msg)
+	if len(fields) == 0 {
+		return s
+	}
+
+	keys := make([]string, 0, len(fields))
+	for field := range fields {
+		keys = append(keys, field)
+	}
+	sort.Strings(keys)
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(keys))
+	for _, field := range keys {
+		for _, description := range fields[field] {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+		}
+	}
+
+	detailed, detailErr := s.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if detailErr != nil {
+		return s
+	}
+
+	return detailed
+}
+
+// GrpcCode maps the HTTP status codes used by CostumError to gRPC codes.
+func GrpcCode(code int) codes.Code {
+	switch code {
+	case 400, 422:
+		return codes.InvalidArgument
+	case 401:
+		return codes.Unauthenticated
+	case 403:
+		return codes.PermissionDenied
+	case 404:
+		return codes.NotFound
+	case 409:
+		return codes.FailedPrecondition
+	case 429:
+		return codes.ResourceExhausted
+	case 503:
+		return codes.Unavailable
+	case 504:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,54 @@
+package errmsg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func TestStatus(t *testing.T) {
+	type request struct {
+		OrderId string `json:"order_id" validate:"required"`
+	}
+
+	t.Run("custom error", func(t *testing.T) {
+		err := NewCostumErrors(409, WithMessage("Insufficient stock"), WithErrors("items.0.quantity", "only 2 left in stock."))
+
+		s := Status[request](err)
+		assert.Equal(t, codes.FailedPrecondition, s.Code())
+		assert.Equal(t, "Insufficient stock", s.Message())
+
+		details := s.Details()
+		if assert.Len(t, details, 1) {
+			badRequest := details[0].(*errdetails.BadRequest)
+			assert.Equal(t, "items.0.quantity", badRequest.FieldViolations[0].Field)
+			assert.Equal(t, "only 2 left in stock.", badRequest.FieldViolations[0].Description)
+		}
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		req := &request{}
+
+		s := Status(validator.New().Struct(req), req)
+		assert.Equal(t, codes.InvalidArgument, s.Code())
+		assert.Len(t, s.Details(), 1)
+	})
+
+	t.Run("server error hides the cause", func(t *testing.T) {
+		s := Status[request](errors.New("pq: connection refused"))
+		assert.Equal(t, codes.Internal, s.Code())
+		assert.NotContains(t, s.Message(), "connection refused")
+		assert.Empty(t, s.Details())
+	})
+
+	t.Run("context errors", func(t *testing.T) {
+		assert.Equal(t, codes.Canceled, Status[request](fmt.Errorf("query: %w", context.Canceled)).Code())
+		assert.Equal(t, codes.DeadlineExceeded, Status[request](context.DeadlineExceeded).Code())
+	})
+}
@@ -68,6 +68,8 @@ func errorValidationHandler[T any](err error, payload *T) (int, map[string][]str
 			message = fmt.Sprintf("%s is not a valid ULID.", fieldInMsg)
 		case "uuid":
 			message = fmt.Sprintf("%s is not a valid UUID.", fieldInMsg)
+		case "http_url":
+			message = fmt.Sprintf("%s must be a valid http or https URL.", fieldInMsg)
 		case "min":
 			// check if the field is a number or a string
 			if valueType.Kind() == reflect.Int || valueType.Kind() == reflect.Int8 || valueType.Kind() == reflect.Int16 || valueType.Kind() == reflect.Int32 || valueType.Kind() == reflect.Int64 || valueType.Kind() == reflect.Float32 || valueType.Kind() == reflect.Float64 {
@@ -121,6 +123,10 @@ func errorValidationHandler[T any](err error, payload *T) (int, map[string][]str
 			message = fmt.Sprintf("%s must be one of %s.", fieldInMsg, err.Param())
 		case "unique_in_slice":
 			message = fmt.Sprintf("%s elements must be unique.", fieldInMsg)
+		case "gt":
+			message = fmt.Sprintf("%s must be greater than %s.", fieldInMsg, err.Param())
+		case "gte":
+			message = fmt.Sprintf("%s must be at least %s.", fieldInMsg, err.Param())
 		}
 
 		errorMessages[field] = append(errorMessages[field], message)
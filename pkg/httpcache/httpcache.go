@@ -0,0 +1,69 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ETag builds a weak entity tag from the parts identifying a version of a
+// representation, ex: its encoded body. Weak, as equal tags promise the same
+// content but not the same bytes.
+func ETag(parts ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return `W/"` + hex.EncodeToString(hash[:12]) + `"`
+}
+
+// NotModified sets the ETag and Last-Modified headers of the response and
+// reports whether the client's copy is still current, in which case the
+// caller answers 304 without a body. If-None-Match wins over
+// If-Modified-Since as RFC 9110 asks, a zero lastModified is not sent.
+//
+// fiber's Ctx.Fresh is not used, it takes any If-Modified-Since as fresh.
+func NotModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if etag != "" {
+		c.Set(fiber.HeaderETag, etag)
+	}
+
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+		return false
+	}
+
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		return etag != "" && matchesETag(noneMatch, etag)
+	}
+
+	if modifiedSince := c.Get(fiber.HeaderIfModifiedSince); modifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(modifiedSince)
+		if err != nil {
+			return false
+		}
+
+		// HTTP dates have whole seconds
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// matchesETag compares the If-None-Match list weakly, W/ prefixes ignored.
+func matchesETag(noneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for _, candidate := range strings.Split(noneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,64 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotModified(t *testing.T) {
+	var (
+		updatedAt = time.Date(2024, 7, 22, 10, 30, 15, 500, time.UTC)
+		etag      = ETag("product-1", updatedAt.Format(time.RFC3339Nano))
+	)
+
+	app := fiber.New()
+	app.Get("/products/1", func(c *fiber.Ctx) error {
+		if NotModified(c, etag, updatedAt) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+		return c.SendString("product")
+	})
+
+	cases := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{"unconditional", nil, fiber.StatusOK},
+		{"matching etag", map[string]string{"If-None-Match": etag}, fiber.StatusNotModified},
+		{"matching etag in a list", map[string]string{"If-None-Match": `W/"other", ` + etag}, fiber.StatusNotModified},
+		{"strong form of the etag", map[string]string{"If-None-Match": etag[2:]}, fiber.StatusNotModified},
+		{"any etag", map[string]string{"If-None-Match": "*"}, fiber.StatusNotModified},
+		{"stale etag", map[string]string{"If-None-Match": `W/"other"`}, fiber.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": updatedAt.Format(http.TimeFormat)}, fiber.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": updatedAt.Add(-time.Second).Format(http.TimeFormat)}, fiber.StatusOK},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, fiber.StatusOK},
+		{"etag wins over date", map[string]string{"If-None-Match": `W/"other"`, "If-Modified-Since": updatedAt.Format(http.TimeFormat)}, fiber.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/products/1", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+
+			res, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, res.StatusCode)
+			assert.Equal(t, etag, res.Header.Get("ETag"))
+			assert.Equal(t, "Mon, 22 Jul 2024 10:30:15 GMT", res.Header.Get("Last-Modified"))
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, ETag("a", "b"), ETag("a", "b"))
+	assert.NotEqual(t, ETag("a", "b"), ETag("ab"))
+	assert.Regexp(t, `^W/"[0-9a-f]{24}"$`, ETag("a"))
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"product-service/internal/adapter"
+	"product-service/pkg/decimal"
 	"reflect"
 	"strings"
 
@@ -55,6 +56,17 @@ func NewValidator() *Validator {
 		log.Fatal().Err(err).Msg("Error while registering unique validator")
 	}
 
+	// decimals validate as numbers, so required, gt, gte and friends apply
+	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		switch d := field.Interface().(type) {
+		case decimal.Decimal:
+			return d.Float64()
+		case decimal.Rate:
+			return d.Float64()
+		}
+		return nil
+	}, decimal.Decimal{}, decimal.Rate{})
+
 	validatorCustom.validator = v
 
 	return validatorCustom
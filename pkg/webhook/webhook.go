@@ -0,0 +1,168 @@
+// Package webhook sends signed HTTP callbacks. The receiver recomputes the
+// signature over the timestamp and the raw body with the shared secret, and
+// should reject old timestamps to stop replays.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+
+	// maxResponseBody is how much of the response is kept for the log
+	maxResponseBody = 1024
+)
+
+// Sign returns sha256=<hex HMAC-SHA256 of "timestamp.body">.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature in constant time.
+func Verify(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+type Request struct {
+	Url        string
+	Secret     string
+	Event      string
+	DeliveryId string
+	Body       []byte
+}
+
+// Result is what the receiver answered, StatusCode is 0 when it didn't.
+type Result struct {
+	StatusCode int
+	Body       string
+	Duration   time.Duration
+}
+
+// ErrForbiddenAddress is returned for receivers resolving to an address
+// that isn't public, webhooks must not reach the service's own network.
+var ErrForbiddenAddress = errors.New("webhook: receiver address is not public")
+
+// sharedAddressSpace is 100.64.0.0/10, carrier-grade NAT that IsPrivate
+// doesn't cover.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+type Client struct {
+	http *http.Client
+}
+
+// NewClient returns a client that only connects to public addresses.
+func NewClient(timeout time.Duration) *Client {
+	return newClient(timeout, isPublic)
+}
+
+// newClient checks every address the client connects to with allow. The
+// check runs on the resolved address right before connecting, so a host
+// resolving to a public address when registered and to a private one when
+// called (DNS rebinding) is refused as well.
+func newClient(timeout time.Duration, allow func(netip.Addr) bool) *Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+
+			if !allow(addrPort.Addr().Unmap()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// a proxy would connect on our behalf, past the address check
+	transport.Proxy = nil
+
+	return &Client{
+		http: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// a redirect would resend the payload somewhere the shop didn't register
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// isPublic refuses loopback, private, link-local, multicast and unspecified
+// addresses.
+func isPublic(addr netip.Addr) bool {
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// Send posts the body and fails unless the receiver answers 2xx.
+func (c *Client) Send(ctx context.Context, req Request) (Result, error) {
+	var (
+		res       Result
+		timestamp = time.Now().Unix()
+		start     = time.Now()
+	)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.Url, bytes.NewReader(req.Body))
+	if err != nil {
+		return res, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "product-service-webhook")
+	httpReq.Header.Set(EventHeader, req.Event)
+	httpReq.Header.Set(DeliveryHeader, req.DeliveryId)
+	httpReq.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(SignatureHeader, Sign(req.Secret, timestamp, req.Body))
+
+	httpRes, err := c.http.Do(httpReq)
+	res.Duration = time.Since(start)
+	if err != nil {
+		return res, err
+	}
+	defer httpRes.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(httpRes.Body, maxResponseBody))
+	res.StatusCode = httpRes.StatusCode
+	res.Body = string(body)
+
+	if httpRes.StatusCode < 200 || httpRes.StatusCode > 299 {
+		return res, fmt.Errorf("webhook: receiver answered %d", httpRes.StatusCode)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	signature := Sign("secret", 1721550000, body)
+
+	assert.True(t, Verify("secret", signature, 1721550000, body))
+	assert.False(t, Verify("other", signature, 1721550000, body))
+	assert.False(t, Verify("secret", signature, 1721550001, body))
+	assert.False(t, Verify("secret", signature, 1721550000, []byte(`{"id":"2"}`)))
+}
+
+func TestSend(t *testing.T) {
+	var (
+		body     = []byte(`{"type":"product.updated"}`)
+		verified bool
+	)
+
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+
+		verified = Verify("secret", r.Header.Get(SignatureHeader), timestamp, received) &&
+			r.Header.Get(EventHeader) == "product.updated" &&
+			r.Header.Get(DeliveryHeader) == "d-1"
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer stub.Close()
+
+	res, err := newClient(time.Second, allowAll).Send(context.Background(), Request{
+		Url:        stub.URL,
+		Secret:     "secret",
+		Event:      "product.updated",
+		DeliveryId: "d-1",
+		Body:       body,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, verified)
+	assert.Equal(t, http.StatusAccepted, res.StatusCode)
+	assert.Equal(t, "ok", res.Body)
+}
+
+func TestSendFailures(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer stub.Close()
+
+	client := newClient(50*time.Millisecond, allowAll)
+
+	res, err := client.Send(context.Background(), Request{Url: stub.URL, Secret: "secret"})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+
+	res, err = client.Send(context.Background(), Request{Url: stub.URL + "/redirect", Secret: "secret"})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+
+	res, err = client.Send(context.Background(), Request{Url: stub.URL + "/slow", Secret: "secret"})
+	assert.Error(t, err)
+	assert.Equal(t, 0, res.StatusCode)
+}
+
+func TestSendRefusesPrivateAddresses(t *testing.T) {
+	var called bool
+
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer stub.Close()
+
+	client := NewClient(time.Second)
+
+	// the stub listens on loopback, like a service next to this one would
+	res, err := client.Send(context.Background(), Request{Url: stub.URL, Secret: "secret"})
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.Equal(t, 0, res.StatusCode)
+	assert.False(t, called)
+
+	// hostnames are checked once resolved
+	res, err = client.Send(context.Background(), Request{Url: strings.Replace(stub.URL, "127.0.0.1", "localhost", 1), Secret: "secret"})
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.False(t, called)
+}
+
+func TestIsPublic(t *testing.T) {
+	cases := map[string]bool{
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"fc00::1":         false,
+		"fe80::1":         false,
+		"224.0.0.1":       false,
+		"0.0.0.0":         false,
+	}
+
+	for addr, public := range cases {
+		assert.Equal(t, public, isPublic(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func allowAll(netip.Addr) bool {
+	return true
+}